@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"path"
+)
+
+// ACLs are set on directories and volumes, files follow the ACL of their directory
+
+func (fs *PCFS) GetAcl(dirPath string) ([]*pb.AclEntry, error) {
+	item, err := fs.lookup(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type != pb.DirectoryItem_DIR {
+		return nil, errors.New("acl can only be set on dirs")
+	}
+	return item.Dir.Acl, nil
+}
+
+func (fs *PCFS) SetAcl(dirPath string, acl []*pb.AclEntry) error {
+	item, err := fs.lookup(dirPath)
+	if err != nil {
+		return err
+	}
+	if item.Type != pb.DirectoryItem_DIR {
+		return errors.New("acl can only be set on dirs")
+	}
+	return fs.execPermissionContract(serv.SET_ACL, &pb.SetAclContract{
+		Key: item.Dir.Key,
+		Acl: acl,
+	})
+}
+
+func (fs *PCFS) GetVolumeAcl(volume string) ([]*pb.AclEntry, error) {
+	dirRes := fs.Ls(path.Join("/", volume))
+	if dirRes == nil {
+		return nil, errors.New("cannot find volume")
+	}
+	return dirRes.Volume.Acl, nil
+}
+
+func (fs *PCFS) SetVolumeAcl(volume string, acl []*pb.AclEntry) error {
+	dirRes := fs.Ls(path.Join("/", volume))
+	if dirRes == nil {
+		return errors.New("cannot find volume")
+	}
+	return fs.execPermissionContract(serv.SET_ACL, &pb.SetAclContract{
+		Key:    dirRes.Volume.Key,
+		Volume: true,
+		Acl:    acl,
+	})
+}
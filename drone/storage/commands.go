@@ -0,0 +1,556 @@
+package storage
+
+import (
+	"fmt"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	pcfs "github.com/PomeloCloud/pcfs/server"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commands run once on a started drone and then exit
+// Usage: drone <command> [args...]
+
+func runCommand(fs *PCFS, config pcfs.FileConfig, args []string) {
+	switch args[0] {
+	case "balance":
+		throttle := pcfs.NewThrottle(pcfs.ParseBandwidth(config.BalanceBandwidth, 10*1024*1024))
+		moved, err := fs.Network.BalanceStashes(config.BalanceThreshold, throttle)
+		if err != nil {
+			log.Println("balance failed:", err)
+		} else {
+			log.Println("balance succeed, moved", moved, "blocks")
+		}
+	case "maintenance":
+		// drone maintenance on|off, a stash in maintenance keeps it's replicas but receives no new blocks
+		state := pb.StashState_MAINTENANCE
+		if len(args) > 1 && args[1] == "off" {
+			state = pb.StashState_ONLINE
+		}
+		if err := fs.Network.SetStashState(state); err != nil {
+			log.Println("cannot set stash state:", err)
+		} else {
+			log.Println("stash is now", state)
+		}
+	case "drain":
+		// drone drain, move all replicas off this stash and deregister it
+		throttle := pcfs.NewThrottle(pcfs.ParseBandwidth(config.RepairBandwidth, 10*1024*1024))
+		if err := fs.Network.DrainStash(throttle); err != nil {
+			log.Println("drain failed, run it again to resume:", err)
+		} else {
+			log.Println("drain succeed, this node can leave the stash group")
+		}
+	case "volume":
+		runVolumeCommand(fs, args[1:])
+	case "policy":
+		runPolicyCommand(fs, args[1:])
+	case "quota":
+		runQuotaCommand(fs, args[1:])
+	case "snapshot":
+		runSnapshotCommand(fs, args[1:])
+	case "diff":
+		// drone diff <volume> <from|-> <to>
+		if len(args) < 4 {
+			log.Println("usage: diff <volume> <from|-> <to>")
+			return
+		}
+		entries, err := fs.Diff(args[1], snapshotArg(args[2]), args[3])
+		if err != nil {
+			log.Println("diff failed:", err)
+			return
+		}
+		for _, entry := range entries {
+			if entry.Change == pb.DiffEntry_RENAMED {
+				log.Println(entry.Change, entry.OldPath, "->", entry.Path)
+			} else {
+				log.Println(entry.Change, entry.Path)
+			}
+		}
+	case "export":
+		// drone export <volume> <from|-> <to> <archive>, exports the whole snapshot when from is -
+		if len(args) < 5 {
+			log.Println("usage: export <volume> <from|-> <to> <archive>")
+			return
+		}
+		if err := fs.Export(args[1], snapshotArg(args[2]), args[3], args[4]); err != nil {
+			log.Println("export failed:", err)
+		}
+	case "import":
+		// drone import <archive> <volume>
+		if len(args) < 3 {
+			log.Println("usage: import <archive> <volume>")
+			return
+		}
+		if err := fs.Import(args[1], args[2]); err != nil {
+			log.Println("import failed:", err)
+		}
+	case "versions":
+		runVersionCommand(fs, args[1:])
+	case "rm":
+		// drone rm [-r] [-p] <path>, -p skips the trash
+		recursive, permanent := false, false
+		for len(args) > 2 && strings.HasPrefix(args[1], "-") {
+			recursive = recursive || strings.Contains(args[1], "r")
+			permanent = permanent || strings.Contains(args[1], "p")
+			args = append(args[:1], args[2:]...)
+		}
+		if len(args) < 2 {
+			log.Println("usage: rm [-r] [-p] <path>")
+			return
+		}
+		if err := fs.deleteItem(args[1], recursive, permanent); err != nil {
+			log.Println("rm failed:", err)
+		}
+	case "trash":
+		runTrashCommand(fs, args[1:])
+	case "stat":
+		// drone stat <path>
+		if len(args) < 2 {
+			log.Println("usage: stat <path>")
+			return
+		}
+		item, err := fs.Stat(args[1], true)
+		if err != nil {
+			log.Println("stat failed:", err)
+			return
+		}
+		xattrs := item.File.Xattrs
+		if item.Type == pb.DirectoryItem_DIR {
+			log.Println(args[1], "dir owner:", item.Dir.Owner, "group:", item.Dir.Group, fmt.Sprintf("mode: %o", item.Dir.Mode))
+			log.Println("modify:", timeString(item.Dir.LastModified), "change:", timeString(item.Dir.ChangedAt),
+				"birth:", timeString(item.Dir.CreatedAt))
+			xattrs = item.Dir.Xattrs
+		} else {
+			log.Println(args[1], "file size:", item.File.Size, "blocks:", len(item.File.Blocks), "owner:", item.File.Owner,
+				"group:", item.File.Group, fmt.Sprintf("mode: %o", item.File.Mode))
+			log.Println("access:", timeString(item.File.AccessedAt), "modify:", timeString(item.File.LastModified),
+				"change:", timeString(item.File.ChangedAt), "birth:", timeString(item.File.CreatedAt))
+		}
+		for _, xattr := range xattrs {
+			log.Println(xattr.Name, "=", string(xattr.Value))
+		}
+	case "xattr":
+		runXattrCommand(fs, args[1:])
+	case "chtimes":
+		// drone chtimes <path> <mtime|-> [atime], times like 2006-01-02T15:04:05Z07:00
+		if len(args) < 3 {
+			log.Println("usage: chtimes <path> <mtime|-> [atime]")
+			return
+		}
+		var times [2]time.Time
+		if len(args) > 4 {
+			args = args[:4]
+		}
+		for i, arg := range args[2:] {
+			if arg == "-" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, arg)
+			if err != nil {
+				log.Println("invalid time:", arg)
+				return
+			}
+			times[i] = t
+		}
+		if err := fs.Chtimes(args[1], times[1], times[0]); err != nil {
+			log.Println("chtimes failed:", err)
+		}
+	case "usergroup":
+		runUserGroupCommand(fs, args[1:])
+	case "du":
+		// drone du <path>
+		if len(args) < 2 {
+			log.Println("usage: du <path>")
+			return
+		}
+		if usage, err := fs.Du(args[1]); err != nil {
+			log.Println("du failed:", err)
+		} else {
+			log.Println(args[1], "bytes:", usage.Bytes, "files:", usage.Files, "dirs:", usage.Dirs)
+		}
+	default:
+		log.Println("unknown command:", args[0])
+	}
+}
+
+// drone volume list | create <name> [replications] [block size] | inspect <name>
+// drone volume update <name> <replications> [block size] | delete <name>
+func runVolumeCommand(fs *PCFS, args []string) {
+	if len(args) == 0 {
+		log.Println("usage: volume list|create|inspect|update|delete")
+		return
+	}
+	arg := func(i int) uint32 {
+		if len(args) <= i {
+			return 0
+		}
+		n, err := strconv.ParseUint(args[i], 10, 32)
+		if err != nil {
+			log.Println("invalid number:", args[i])
+		}
+		return uint32(n)
+	}
+	if args[0] != "list" && len(args) < 2 {
+		log.Println("volume name required")
+		return
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		var volumes []*pb.Volume
+		if volumes, err = fs.ListVolumes(); err == nil {
+			for _, volume := range volumes {
+				log.Println(fmt.Sprint(volume.Owner, ":", volume.Name), "replications:", volume.Replications,
+					"block size:", volume.BlockSize)
+			}
+		}
+	case "create":
+		err = fs.CreateVolume(args[1], arg(2), arg(3))
+	case "inspect":
+		var usage *pb.VolumeUsage
+		if usage, err = fs.InspectVolume(args[1]); err == nil {
+			log.Println(fmt.Sprint(usage.Volume.Owner, ":", usage.Volume.Name), "replications:", usage.Volume.Replications,
+				"block size:", usage.Volume.BlockSize, "files:", usage.Files, "dirs:", usage.Dirs,
+				"size:", usage.Size)
+			if job := usage.Job; job != nil && !job.Done {
+				log.Println("replicating to", job.Replications, "replicas:", job.Processed, "/", job.Total, "files")
+			}
+		}
+	case "update":
+		err = fs.UpdateVolume(args[1], arg(2), arg(3))
+	case "delete":
+		err = fs.DeleteVolume(args[1])
+	default:
+		log.Println("unknown volume command:", args[0])
+		return
+	}
+	if err != nil {
+		log.Println("volume", args[0], "failed:", err)
+	} else {
+		log.Println("volume", args[0], "succeed")
+	}
+}
+
+// drone policy get <path> | set <dir> <replications> [block size] [zone,zone...]
+// zero replications or block size follow the parent
+func runPolicyCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || (args[0] == "set" && len(args) < 3) {
+		log.Println("usage: policy get|set <path> [replications] [block size] [zones]")
+		return
+	}
+	switch args[0] {
+	case "get":
+		policy, err := fs.GetPolicy(args[1])
+		if err != nil {
+			log.Println("policy get failed:", err)
+		} else if policy == nil {
+			log.Println(args[1], "follows the volume")
+		} else {
+			log.Println(args[1], "replications:", policy.Replications, "block size:", policy.BlockSize,
+				"zones:", policy.Zones)
+		}
+	case "set":
+		policy := &pb.StoragePolicy{}
+		for i, arg := range args[2:] {
+			if i == 2 {
+				policy.Zones = strings.Split(arg, ",")
+				break
+			}
+			n, err := strconv.ParseUint(arg, 10, 32)
+			if err != nil {
+				log.Println("invalid number:", arg)
+				return
+			}
+			if i == 0 {
+				policy.Replications = uint32(n)
+			} else {
+				policy.BlockSize = uint32(n)
+			}
+		}
+		if err := fs.SetPolicy(args[1], policy); err != nil {
+			log.Println("policy set failed:", err)
+		} else {
+			log.Println("policy set succeed")
+		}
+	default:
+		log.Println("unknown policy command:", args[0])
+	}
+}
+
+// drone quota get <dir> | set <dir> <bytes> [files], zero removes the limit
+func runQuotaCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || (args[0] == "set" && len(args) < 3) {
+		log.Println("usage: quota get|set <dir> [bytes] [files]")
+		return
+	}
+	switch args[0] {
+	case "get":
+		quota, usage, err := fs.GetQuota(args[1])
+		if err != nil {
+			log.Println("quota get failed:", err)
+			return
+		}
+		if quota == nil {
+			quota = &pb.Quota{}
+		}
+		log.Println(args[1], "bytes:", usage.Bytes, "/", limitString(quota.Bytes),
+			"files and dirs:", usage.Files+usage.Dirs, "/", limitString(quota.Files))
+	case "set":
+		quota := &pb.Quota{}
+		var err error
+		if quota.Bytes, err = strconv.ParseUint(args[2], 10, 64); err != nil {
+			log.Println("invalid number:", args[2])
+			return
+		}
+		if len(args) > 3 {
+			if quota.Files, err = strconv.ParseUint(args[3], 10, 64); err != nil {
+				log.Println("invalid number:", args[3])
+				return
+			}
+		}
+		if err := fs.SetQuota(args[1], quota); err != nil {
+			log.Println("quota set failed:", err)
+		} else {
+			log.Println("quota set succeed")
+		}
+	default:
+		log.Println("unknown quota command:", args[0])
+	}
+}
+
+func limitString(limit uint64) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return strconv.FormatUint(limit, 10)
+}
+
+// drone snapshot list <volume> | create <volume> <name> | delete <volume> <name>
+func runSnapshotCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || (args[0] != "list" && len(args) < 3) {
+		log.Println("usage: snapshot list|create|delete <volume> [name]")
+		return
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		var names []string
+		if names, err = fs.ListSnapshots(args[1]); err == nil {
+			for _, name := range names {
+				log.Println(name)
+			}
+		}
+	case "create":
+		err = fs.CreateSnapshot(args[1], args[2])
+	case "delete":
+		err = fs.DeleteSnapshot(args[1], args[2])
+	default:
+		log.Println("unknown snapshot command:", args[0])
+		return
+	}
+	if err != nil {
+		log.Println("snapshot", args[0], "failed:", err)
+	} else {
+		log.Println("snapshot", args[0], "succeed")
+	}
+}
+
+func snapshotArg(arg string) string {
+	if arg == "-" {
+		return ""
+	}
+	return arg
+}
+
+// drone versions list <file> | restore <file> <number> | enable <volume> [keep] [max age] | disable <volume>
+func runVersionCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || (args[0] == "restore" && len(args) < 3) {
+		log.Println("usage: versions list|restore|enable|disable <path> [args]")
+		return
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		var versions []*pb.FileVersion
+		if versions, err = fs.ListVersions(args[1]); err == nil {
+			for _, version := range versions {
+				log.Println(version.Number, time.Unix(0, int64(version.CreatedAt)), "size:", version.Meta.Size)
+			}
+		}
+	case "restore":
+		var number uint64
+		if number, err = strconv.ParseUint(args[2], 10, 64); err == nil {
+			err = fs.RestoreVersion(args[1], number)
+		}
+	case "enable":
+		retention := &pb.VersionRetention{Enabled: true}
+		if len(args) > 2 {
+			var keep uint64
+			if keep, err = strconv.ParseUint(args[2], 10, 32); err != nil {
+				break
+			}
+			retention.Keep = uint32(keep)
+		}
+		if len(args) > 3 {
+			var maxAge time.Duration
+			if maxAge, err = time.ParseDuration(args[3]); err != nil {
+				break
+			}
+			retention.MaxAge = uint64(maxAge)
+		}
+		err = fs.SetVersioning(args[1], retention)
+	case "disable":
+		err = fs.SetVersioning(args[1], &pb.VersionRetention{})
+	default:
+		log.Println("unknown versions command:", args[0])
+		return
+	}
+	if err != nil {
+		log.Println("versions", args[0], "failed:", err)
+	} else {
+		log.Println("versions", args[0], "succeed")
+	}
+}
+
+// drone trash list <volume> | restore <volume> <path> | purge <volume> <path>
+// drone trash enable <volume> [retention] | disable <volume>
+func runTrashCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || ((args[0] == "restore" || args[0] == "purge") && len(args) < 3) {
+		log.Println("usage: trash list|restore|purge|enable|disable <volume> [args]")
+		return
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		var entries []*pb.TrashEntry
+		if entries, err = fs.ListTrash(args[1]); err == nil {
+			for _, entry := range entries {
+				log.Println(entry.Path, "deleted at", time.Unix(0, int64(entry.DeletedAt)), "bytes:", entry.Usage.Bytes)
+			}
+		}
+	case "restore":
+		err = fs.RestoreTrash(args[1], args[2])
+	case "purge":
+		err = fs.PurgeTrash(args[1], args[2])
+	case "enable":
+		retention := &pb.TrashRetention{Enabled: true}
+		if len(args) > 2 {
+			var keep time.Duration
+			if keep, err = time.ParseDuration(args[2]); err != nil {
+				break
+			}
+			retention.Retention = uint64(keep)
+		}
+		err = fs.SetTrash(args[1], retention)
+	case "disable":
+		err = fs.SetTrash(args[1], &pb.TrashRetention{})
+	default:
+		log.Println("unknown trash command:", args[0])
+		return
+	}
+	if err != nil {
+		log.Println("trash", args[0], "failed:", err)
+	} else {
+		log.Println("trash", args[0], "succeed")
+	}
+}
+
+// drone xattr list <path> | get <path> <name> | set <path> <name> <value> | rm <path> <name>
+func runXattrCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || (args[0] != "list" && len(args) < 3) || (args[0] == "set" && len(args) < 4) {
+		log.Println("usage: xattr list|get|set|rm <path> [name] [value]")
+		return
+	}
+	var err error
+	switch args[0] {
+	case "list":
+		var xattrs []*pb.Xattr
+		if xattrs, err = fs.ListXattrs(args[1]); err == nil {
+			for _, xattr := range xattrs {
+				log.Println(xattr.Name, "=", string(xattr.Value))
+			}
+		}
+	case "get":
+		var value []byte
+		if value, err = fs.GetXattr(args[1], args[2]); err == nil {
+			log.Println(args[2], "=", string(value))
+		}
+	case "set":
+		err = fs.SetXattr(args[1], args[2], []byte(args[3]))
+	case "rm":
+		err = fs.RemoveXattr(args[1], args[2])
+	default:
+		log.Println("unknown xattr command:", args[0])
+		return
+	}
+	if err != nil {
+		log.Println("xattr", args[0], "failed:", err)
+	} else {
+		log.Println("xattr", args[0], "succeed")
+	}
+}
+
+// groups are given by id or by name
+// drone usergroup get <group> | set <group> [member,...] | rename <group> <name>
+func runUserGroupCommand(fs *PCFS, args []string) {
+	if len(args) < 2 || (args[0] == "rename" && len(args) < 3) {
+		log.Println("usage: usergroup get|set|rename <group> [args]")
+		return
+	}
+	id, err := strconv.ParseUint(args[1], 10, 64)
+	name := ""
+	if err != nil {
+		id, name, err = 0, args[1], nil
+	}
+	switch args[0] {
+	case "get":
+		var userGroup *pb.UserGroup
+		if userGroup, err = fs.GetUserGroup(id, name); err == nil {
+			log.Println("group", userGroup.Id, userGroup.Name, "owner:", userGroup.Owner, "members:", userGroup.Members)
+		}
+	case "set":
+		members := []uint64{}
+		if len(args) > 2 {
+			for _, member := range strings.Split(args[2], ",") {
+				var memberId uint64
+				if memberId, err = strconv.ParseUint(member, 10, 64); err != nil {
+					break
+				}
+				members = append(members, memberId)
+			}
+		}
+		if err == nil && name != "" {
+			err = fs.SetNamedUserGroup(name, members)
+		} else if err == nil {
+			err = fs.SetUserGroup(id, members)
+		}
+	case "rename":
+		if name != "" {
+			var userGroup *pb.UserGroup
+			if userGroup, err = fs.GetUserGroup(0, name); err == nil {
+				id = userGroup.Id
+			}
+		}
+		if err == nil {
+			err = fs.RenameUserGroup(id, args[2])
+		}
+	default:
+		log.Println("unknown usergroup command:", args[0])
+		return
+	}
+	if err != nil {
+		log.Println("usergroup", args[0], "failed:", err)
+	} else {
+		log.Println("usergroup", args[0], "succeed")
+	}
+}
+
+// unset times are left empty
+func timeString(t uint64) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(0, int64(t)).Format(time.RFC3339Nano)
+}
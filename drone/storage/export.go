@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"io"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Export writes the changes between two snapshots into a local archive, with data of changed blocks only
+// Import replays an archive onto a volume that has the content of the older snapshot,
+// the volume can be on another cluster. An archive exported from no snapshot holds the full volume
+// Records are length prefixed protobuf messages
+
+func (fs *PCFS) Diff(volume string, from string, to string) ([]*pb.DiffEntry, error) {
+	req := &pb.DiffRequest{
+		Group:    serv.STASH_GROUP,
+		Volume:   volume,
+		From:     from,
+		To:       to,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	diffI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.DiffSnapshots(context.Background(), req)
+		if err != nil {
+			log.Print("cannot diff snapshots: ", err)
+			return nil, []byte{}
+		}
+		feature, err := proto.Marshal(res)
+		if err != nil {
+			return nil, []byte{}
+		}
+		return res, feature
+	})
+	if diffI == nil {
+		return nil, errors.New("cannot diff snapshots")
+	}
+	return diffI.(*pb.DiffResponse).Entries, nil
+}
+
+func writeRecord(w *bufio.Writer, record *pb.ExportRecord) error {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	size := make([]byte, binary.MaxVarintLen64)
+	if _, err := w.Write(size[:binary.PutUvarint(size, uint64(len(data)))]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func readRecord(r *bufio.Reader) (*pb.ExportRecord, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	record := &pb.ExportRecord{}
+	return record, proto.Unmarshal(data, record)
+}
+
+// from can be empty to export the whole snapshot
+func (fs *PCFS) Export(volume string, from string, to string, archivePath string) error {
+	entries, err := fs.Diff(volume, from, to)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	header := &pb.ExportHeader{Volume: volume, From: from, To: to, CreatedAt: uint64(time.Now().UnixNano())}
+	if err := writeRecord(w, &pb.ExportRecord{Header: header}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := writeRecord(w, &pb.ExportRecord{Entry: entry}); err != nil {
+			return err
+		}
+		if entry.Dir || len(entry.Blocks) == 0 {
+			continue
+		}
+		stream, err := fs.NewStream(path.Join("/", volume, serv.SNAPSHOTS_DIR, to, entry.Path))
+		if err != nil {
+			return err
+		}
+		blockSize := uint64(stream.Meta.BlockSize)
+		for _, index := range entry.Blocks {
+			if err := stream.Seek(index * blockSize); err != nil {
+				return err
+			}
+			data := make([]byte, blockSize)
+			n, err := stream.Read(&data)
+			if err != nil {
+				return err
+			}
+			if err := writeRecord(w, &pb.ExportRecord{Offset: index * blockSize, Data: data[:n]}); err != nil {
+				return err
+			}
+		}
+	}
+	log.Println("exported", len(entries), "changes to", archivePath)
+	return w.Flush()
+}
+
+func (fs *PCFS) Import(archivePath string, volume string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	record, err := readRecord(r)
+	if err != nil {
+		return err
+	}
+	if record.Header == nil {
+		return errors.New("archive has no header")
+	}
+	log.Println("importing changes of", record.Header.Volume, "from", record.Header.From, "to", record.Header.To)
+	// renamed items are moved aside under the volume root before anything is removed, then put at their new paths
+	// shallowest first before added items deeper than them, as their new parents may be renamed or added too
+	type aside struct {
+		temp   string
+		old    string
+		target string
+	}
+	asides := []aside{}
+	importId := time.Now().UnixNano()
+	depth := func(p string) int {
+		return strings.Count(p, "/")
+	}
+	placeRenamed := func(before int) error {
+		sort.SliceStable(asides, func(i, j int) bool {
+			return depth(asides[i].target) < depth(asides[j].target)
+		})
+		for len(asides) > 0 && (before < 0 || depth(asides[0].target) < before) {
+			if err := fs.Mv(asides[0].temp, asides[0].target); err != nil {
+				return errors.New(fmt.Sprint("cannot move renamed item to ", asides[0].target, ": ", err))
+			}
+			asides = asides[1:]
+		}
+		return nil
+	}
+	// removed items under a renamed dir are found where the nearest renamed ancestor was moved aside
+	asidePath := func(oldPath string) string {
+		found := aside{}
+		for _, a := range asides {
+			if strings.HasPrefix(oldPath, a.old+"/") && len(a.old) > len(found.old) {
+				found = a
+			}
+		}
+		if found.temp == "" {
+			return path.Join("/", volume, oldPath)
+		}
+		return path.Join(found.temp, strings.TrimPrefix(oldPath, found.old))
+	}
+	var stream *FileStream
+	// modification time of the source is kept after the content is written
+	var streamPath string
+	var streamTime uint64
+	// the last block of the file is cleared after it's data, files can shrink
+	var streamBlocks uint64
+	closeStream := func() error {
+		if stream == nil {
+			return nil
+		}
+		err := stream.Close()
+		stream = nil
+		if err == nil && streamTime != 0 {
+			err = fs.Chtimes(streamPath, time.Time{}, time.Unix(0, int64(streamTime)))
+		}
+		return err
+	}
+	applied := 0
+	for {
+		record, err := readRecord(r)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if record.Entry == nil {
+			if stream == nil {
+				return errors.New("block data without a file")
+			}
+			if err := stream.Seek(record.Offset); err != nil {
+				return err
+			}
+			if _, err := stream.Write(&record.Data); err != nil {
+				return err
+			}
+			if record.Offset/uint64(stream.Meta.BlockSize)+1 == streamBlocks {
+				if err := stream.Truncate(record.Offset + uint64(len(record.Data))); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := closeStream(); err != nil {
+			return err
+		}
+		entry := record.Entry
+		target := path.Join("/", volume, entry.Path)
+		switch entry.Change {
+		case pb.DiffEntry_ADDED:
+			err = placeRenamed(depth(entry.Path))
+		case pb.DiffEntry_MODIFIED:
+			err = placeRenamed(-1)
+		}
+		if err != nil {
+			return err
+		}
+		switch entry.Change {
+		case pb.DiffEntry_REMOVED:
+			err = fs.Rmr(asidePath(entry.Path))
+		case pb.DiffEntry_RENAMED:
+			temp := path.Join("/", volume, fmt.Sprint(".import-", importId, "-", applied))
+			if err = fs.Mv(path.Join("/", volume, entry.OldPath), temp); err == nil {
+				asides = append(asides, aside{temp: temp, old: entry.OldPath, target: target})
+			}
+		case pb.DiffEntry_ADDED, pb.DiffEntry_MODIFIED:
+			if entry.Dir && entry.Change == pb.DiffEntry_ADDED {
+				err = fs.Mkdir(target)
+			} else if !entry.Dir {
+				stream, err = fs.NewStream(target)
+				streamPath, streamTime, streamBlocks = target, 0, 0
+				if entry.File != nil {
+					streamTime = entry.File.LastModified
+					streamBlocks = uint64(len(entry.File.Blocks))
+				}
+				if err == nil {
+					err = stream.Truncate(streamBlocks * uint64(stream.Meta.BlockSize))
+				}
+			}
+			if err == nil && entry.Mode != 0 {
+				err = fs.Chmod(target, entry.Mode)
+			}
+		}
+		if err != nil {
+			return errors.New(fmt.Sprint("cannot replay ", entry.Change, " of ", entry.Path, ": ", err))
+		}
+		applied++
+	}
+	if err := closeStream(); err != nil {
+		return err
+	}
+	if err := placeRenamed(-1); err != nil {
+		return err
+	}
+	log.Println("imported", applied, "changes")
+	return nil
+}
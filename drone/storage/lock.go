@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Advisory locks on files and directories, they don't stop anyone from reading or writing
+// Hold a lock longer than ttl by calling Lock again before it expires
+
+// Lock acquires the lock on the path. Without wait it fails immediately when the lock is taken,
+// with wait it queues up and returns once the lock is granted
+func (fs *PCFS) Lock(itemPath string, mode pb.LockMode, ttl time.Duration, wait bool) error {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	for true {
+		contract := &pb.LockContract{
+			Key:        itemKey(item),
+			Mode:       mode,
+			Ttl:        uint64(ttl),
+			ClientTime: uint64(time.Now().UnixNano()),
+			Wait:       wait,
+		}
+		contractData, err := proto.Marshal(contract)
+		if err != nil {
+			return err
+		}
+		res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.LOCK, contractData)
+		if err != nil {
+			return err
+		}
+		switch (*res)[0] {
+		case serv.LOCK_GRANTED:
+			log.Println("locked", itemPath, mode)
+			return nil
+		case serv.LOCK_QUEUED:
+			// stay in the queue by asking again before the ttl passed
+			time.Sleep(time.Second)
+		default:
+			return errors.New("lock is held by others")
+		}
+	}
+	return nil
+}
+
+func (fs *PCFS) Unlock(itemPath string) error {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	contractData, err := proto.Marshal(&pb.UnlockContract{Key: itemKey(item)})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.UNLOCK, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("unlock failed")
+	}
+	return nil
+}
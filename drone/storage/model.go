@@ -10,13 +10,16 @@ import (
 	"github.com/golang/protobuf/proto"
 	"log"
 	"path"
-	"strconv"
 	"time"
 	"os"
 )
 
 type PCFS struct {
 	Network *serv.PCFSServer
+	// permission bits removed from modes of new files and directories
+	Umask uint32
+	// reads record access times of files
+	Atime bool
 }
 
 type FileStream struct {
@@ -26,14 +29,41 @@ type FileStream struct {
 	Offset            uint64
 	currentBlockData  *pb.BlockData
 	currentBlockDirty bool
+	writeLocked       bool
+	leaseStop         chan bool
+	// key of the snapshot or number of the version the file belongs to, such streams are read only
+	snapshot []byte
+	version  uint64
+	// access time is recorded once for a stream
+	accessed bool
+}
+
+func (fs *FileStream) readOnly() bool {
+	return fs.snapshot != nil || fs.version != 0
 }
 
 func (fs *PCFS) Ls(dirPath string) *pb.ListDirectoryResponse {
+	return fs.list(dirPath, false)
+}
+
+// items come with their extended attributes
+func (fs *PCFS) LsWithXattrs(dirPath string) *pb.ListDirectoryResponse {
+	return fs.list(dirPath, true)
+}
+
+func (fs *PCFS) list(dirPath string, xattrs bool) *pb.ListDirectoryResponse {
+	req := &pb.ListDirectoryRequest{
+		Group:    serv.STASH_GROUP,
+		Path:     dirPath,
+		ClientId: fs.Network.BFTRaft.Id,
+		Xattrs:   xattrs,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		log.Print("cannot sign dir list request")
+		return nil
+	}
 	dirI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
-		res, err := client.ListDirectory(context.Background(), &pb.ListDirectoryRequest{
-			Group: serv.STASH_GROUP,
-			Path:  dirPath,
-		})
+		res, err := client.ListDirectory(context.Background(), req)
 		if err != nil {
 			log.Print("cannot access node for dir list")
 			return nil, []byte{}
@@ -54,12 +84,61 @@ func (fs *PCFS) Ls(dirPath string) *pb.ListDirectoryResponse {
 	}
 }
 
+// finds the file or directory at the path, the root of a volume is a directory too
+func (fs *PCFS) lookup(itemPath string) (*pb.DirectoryItem, error) {
+	itemPath = path.Clean(itemPath)
+	dir, name := path.Split(itemPath)
+	if dir == "/" {
+		dirRes := fs.Ls(itemPath)
+		if dirRes == nil {
+			return nil, errors.New("cannot find volume")
+		}
+		return &pb.DirectoryItem{
+			Type: pb.DirectoryItem_DIR,
+			File: &pb.FileMeta{},
+			Dir:  dirRes.Dir,
+		}, nil
+	}
+	dirRes := fs.Ls(dir)
+	if dirRes == nil {
+		return nil, errors.New("cannot find parent dir")
+	}
+	for _, item := range dirRes.Items {
+		if (item.Type == pb.DirectoryItem_FILE && item.File.Name == name) ||
+			(item.Type == pb.DirectoryItem_DIR && item.Dir.Name == name) {
+			return item, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprint("cannot find ", itemPath))
+}
+
+func itemKey(item *pb.DirectoryItem) []byte {
+	if item.Type == pb.DirectoryItem_DIR {
+		return item.Dir.Key
+	}
+	return item.File.Key
+}
+
 func (fs *PCFS) NewStream(filepath string) (*FileStream, error) {
+	return fs.newStream(filepath, nil)
+}
+
+// policy overrides the one inherited from the directory when the file is created,
+// it's ignored for an existing file
+func (fs *PCFS) NewStreamWithPolicy(filepath string, policy *pb.StoragePolicy) (*FileStream, error) {
+	return fs.newStream(filepath, policy)
+}
+
+func (fs *PCFS) newStream(filepath string, policy *pb.StoragePolicy) (*FileStream, error) {
 	dir, filename := path.Split(filepath)
 	dirRes := fs.Ls(dir)
 	if dirRes == nil {
 		return nil, errors.New("cannot found dir for stream")
 	}
+	var snapshot []byte
+	if dirRes.Snapshot != nil {
+		snapshot = dirRes.Snapshot.Key
+	}
 	for _, item := range dirRes.Items {
 		if item.Type == pb.DirectoryItem_FILE && item.File.Name == filename {
 			return &FileStream{
@@ -69,26 +148,32 @@ func (fs *PCFS) NewStream(filepath string) (*FileStream, error) {
 				currentBlockData:  nil, // lazy load
 				currentBlockDirty: false,
 				Filesystem:        fs,
+				snapshot:          snapshot,
 			}, nil
 		}
 	}
+	if snapshot != nil {
+		return nil, errors.New("snapshots are read only")
+	}
 	// filename not found, touch it
-	if err := fs.touchFile(dirRes.Volume.Key, dirRes.Key, filename); err != nil {
+	if err := fs.touchFile(dirRes.Volume.Key, dirRes.Key, filename, policy); err != nil {
 		log.Println("cannot touch file:", err)
 		return nil, err
 	} else {
 		log.Println("file touched, retry:", filepath)
-		return fs.NewStream(filepath)
+		return fs.newStream(filepath, nil)
 	}
 	return nil, errors.New("cannot find filename for stream")
 }
 
-func (fs *PCFS) touchFile(volume []byte, dir []byte, filename string) error {
+func (fs *PCFS) touchFile(volume []byte, dir []byte, filename string, policy *pb.StoragePolicy) error {
 	touchFileContract := &pb.TouchFileContract{
 		ClientTime: uint64(time.Now().UnixNano()),
 		Name:       filename,
 		Dir:        dir,
 		Volume:     volume,
+		Mode:       0666 &^ fs.Umask,
+		Policy:     policy,
 	}
 	contractData, err := proto.Marshal(touchFileContract)
 	if err != nil {
@@ -100,14 +185,17 @@ func (fs *PCFS) touchFile(volume []byte, dir []byte, filename string) error {
 	} else {
 		if (*res)[0] == 1 {
 			return nil
+		} else if (*res)[0] == serv.QUOTA_EXCEEDED {
+			return serv.ErrQuotaExceeded
 		} else {
 			return errors.New("touch file failed")
 		}
 	}
 }
 
+// home volume of this node, named with the owner so the path is the same for everyone
 func (fs *PCFS) Home() string {
-	return fmt.Sprint("/", strconv.Itoa(int(fs.Network.BFTRaft.Id)))
+	return fmt.Sprint("/", fs.Network.BFTRaft.Id, ":home")
 }
 
 func (fs *PCFS) PutFile(src string, dest string)  {
@@ -129,18 +217,28 @@ func (fs *PCFS) PutFile(src string, dest string)  {
 			break
 		}
 	}
-	stream.LandWrite()
+	if err := stream.Close(); err != nil {
+		log.Println("cannot close stream:", err)
+	}
 	log.Println("insert file succeed")
 }
 
+func (fs *FileStream) replications() uint32 {
+	return serv.FileReplications(fs.Meta, fs.volume)
+}
+
 func (fs *FileStream) newBlock(file []byte, index uint64) (*pb.FileMeta, error) {
 	log.Println("create block at index:", index)
+	if err := fs.acquireWriteLock(); err != nil {
+		return nil, err
+	}
 	hostSuggestionsI := fs.Filesystem.Network.GroupMajorityResponse(
 		serv.STASH_GROUP,
 		func(client pb.PCFSClient) (interface{}, []byte) {
 			suggestion, err := client.SuggestBlockStash(context.Background(), &pb.BlockStashSuggestionRequest{
 				Group: serv.STASH_GROUP,
-				Num:   fs.volume.Replications * 2,
+				Num:   fs.replications() * 2,
+				Zones: serv.FileZones(fs.Meta),
 			})
 			if err != nil {
 				log.Println("cannot create new block", err)
@@ -160,18 +258,28 @@ func (fs *FileStream) newBlock(file []byte, index uint64) (*pb.FileMeta, error)
 	}
 	hostSuggestions := hostSuggestionsI.([]*pb.HostStash)
 	succeedReplicas := []uint64{}
+	blockHash := []byte{}
 	raft := fs.Filesystem.Network.BFTRaft
 	blockReq := &pb.CreateBlockRequest{
-		Group: serv.STASH_GROUP,
-		Index: index,
-		File:  file,
+		Group:    serv.STASH_GROUP,
+		Index:    index,
+		File:     file,
+		ClientId: raft.Id,
+	}
+	if err := fs.Filesystem.Network.SignRequest(blockReq, &blockReq.Signature); err != nil {
+		return nil, err
 	}
+	// suggestions are ordered to spread across failure domains, take first replications of them
 	for _, host := range hostSuggestions {
+		if uint32(len(succeedReplicas)) >= fs.replications() {
+			break
+		}
 		host := raft.GetHostNTXN(host.HostId)
-		c := serv.GetPeerRPC(host.ServerAddr)
+		c := fs.Filesystem.Network.GetPeerRPC(host)
 		if res, err := c.CreateBlock(context.Background(), blockReq); err == nil {
 			if res.Succeed {
 				succeedReplicas = append(succeedReplicas, host.Id)
+				blockHash = res.BlockHash
 			}
 		} else {
 			log.Println("cannot set block to stash:", err)
@@ -185,6 +293,7 @@ func (fs *FileStream) newBlock(file []byte, index uint64) (*pb.FileMeta, error)
 		ClientTime: uint64(time.Now().UnixNano()),
 		NodeIds:    succeedReplicas,
 		File:       file,
+		Hash:       blockHash,
 	}
 	contractData, err := proto.Marshal(commitContract)
 	if err != nil {
@@ -207,6 +316,9 @@ func (fs *FileStream) newBlock(file []byte, index uint64) (*pb.FileMeta, error)
 				log.Print(msg)
 				return nil, errors.New(msg)
 			}
+		} else if (*res)[0] == serv.QUOTA_EXCEEDED {
+			log.Print("cannot commit block: ", serv.ErrQuotaExceeded)
+			return nil, serv.ErrQuotaExceeded
 		} else {
 			msg := fmt.Sprint("commit block contract failed")
 			log.Print(msg)
@@ -221,30 +333,14 @@ func (fs *FileStream) getBlock(index uint64) error {
 		return nil
 	}
 	fs.LandWrite()
-	blockI := fs.Filesystem.Network.GroupMajorityResponse(
-		serv.STASH_GROUP,
-		func(client pb.PCFSClient) (interface{}, []byte) {
-			block, err := client.GetBlock(context.Background(), &pb.GetBlockRequest{
-				Group: serv.STASH_GROUP,
-				Index: index,
-				File:  fs.Meta.Key,
-			})
-			if err != nil {
-				msg := "cannot get block"
-				log.Println(msg)
-				return nil, []byte{}
-			} else {
-				return block, block.Data
-			}
-		},
-	)
-	if blockI == nil {
-		msg := fmt.Sprint("cannot get block data for: ", index)
+	block, err := fs.readBlockReplicas(index)
+	if err != nil {
+		msg := fmt.Sprint("cannot get block data for: ", index, " ", err)
 		log.Println(msg)
 		return errors.New(msg)
 	} else {
 		log.Println("got block data:", index)
-		fs.currentBlockData = blockI.(*pb.BlockData)
+		fs.currentBlockData = block
 		return nil
 	}
 }
@@ -283,6 +379,7 @@ func (fs *FileStream) Read(bytes *[]byte) (uint64, error) {
 	if bytes == nil {
 		return 0, errors.New("need a sized byte buffer")
 	}
+	fs.recordAccess()
 	origOffset := fs.Offset
 	var i uint64
 	for i = 0; i < uint64(len(*bytes)); i++ {
@@ -302,6 +399,9 @@ func (fs *FileStream) Write(bytes *[]byte) (uint64, error) {
 	if bytes == nil {
 		return 0, errors.New("need a sized byte buffer")
 	}
+	if err := fs.acquireWriteLock(); err != nil {
+		return 0, err
+	}
 	origOffset := fs.Offset
 	var i uint64
 	for i = 0; i < uint64(len(*bytes)); i++ {
@@ -318,6 +418,62 @@ func (fs *FileStream) Write(bytes *[]byte) (uint64, error) {
 	return i, nil
 }
 
+// cuts the file down to size, blocks after it are dropped and the last one is cleared after size
+func (fs *FileStream) Truncate(size uint64) error {
+	if err := fs.acquireWriteLock(); err != nil {
+		return err
+	}
+	blockSize := uint64(fs.Meta.BlockSize)
+	blocks := (size + blockSize - 1) / blockSize
+	if blocks > uint64(len(fs.Meta.Blocks)) {
+		return nil
+	}
+	if blocks < uint64(len(fs.Meta.Blocks)) {
+		fs.LandWrite()
+		if fs.currentBlockData != nil && fs.currentBlockData.Index >= blocks {
+			fs.currentBlockData = nil
+		}
+		contractData, err := proto.Marshal(&pb.TruncateFileContract{
+			File:       fs.Meta.Key,
+			Blocks:     blocks,
+			ClientTime: uint64(time.Now().UnixNano()),
+		})
+		if err != nil {
+			return err
+		}
+		res, err := fs.Filesystem.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.TRUNCATE_FILE, contractData)
+		if err != nil {
+			return err
+		}
+		if len(*res) == 1 {
+			return errors.New("truncate file contract failed")
+		}
+		deleted := &pb.DeletedFiles{}
+		if err := proto.Unmarshal(*res, deleted); err != nil {
+			return err
+		}
+		fs.Meta.Blocks = fs.Meta.Blocks[:blocks]
+		fs.Meta.Size = blocks * blockSize
+		fs.Filesystem.deleteBlocks(deleted.Files, false)
+	}
+	fs.Offset = size
+	tail := size % blockSize
+	if tail == 0 {
+		return nil
+	}
+	if err := fs.ensureBlock(); err != nil {
+		return err
+	}
+	if fs.currentBlockData.Tail >= uint32(tail) {
+		fs.currentBlockData.Tail = uint32(tail - 1)
+		for i := tail; i < uint64(len(fs.currentBlockData.Data)); i++ {
+			fs.currentBlockData.Data[i] = 0
+		}
+		fs.currentBlockDirty = true
+	}
+	return nil
+}
+
 // write all buffed data into the file system
 func (fs *FileStream) LandWrite() {
 	if !fs.currentBlockDirty {
@@ -329,57 +485,172 @@ func (fs *FileStream) LandWrite() {
 	index := fs.currentBlockData.Index
 	blockMeta := fs.Meta.Blocks[index]
 	hostIds := blockMeta.Hosts
+	fs.currentBlockData.ClientId = fs.Filesystem.Network.BFTRaft.Id
+	if err := fs.Filesystem.Network.SignRequest(fs.currentBlockData, &fs.currentBlockData.Signature); err != nil {
+		log.Println("cannot sign block:", err)
+		return
+	}
+	hashes := map[string]int{}
 	for _, hostId := range hostIds {
 		host := fs.Filesystem.Network.BFTRaft.GetHostNTXN(hostId)
 		if host == nil {
 			log.Println("cannot find host:", hostId)
 		}
-		client := serv.GetPeerRPC(host.ServerAddr)
+		client := fs.Filesystem.Network.GetPeerRPC(host)
 		wr, err := client.SetBlock(context.Background(), fs.currentBlockData)
 		if err != nil {
 			log.Println("cannot set block to", hostId, err)
 		} else {
 			if wr.Succeed == true {
 				log.Println("set block succeed")
+				hashes[string(wr.BlockHash)]++
 			} else {
 				log.Println("set block failed")
 			}
 		}
 	}
 	fs.currentBlockDirty = false
+	blockHash := ""
+	for hash, count := range hashes {
+		if count > hashes[blockHash] {
+			blockHash = hash
+		}
+	}
+	if blockHash != "" {
+		fs.updateBlockHash(index, []byte(blockHash))
+	}
 }
 
-func (fs *PCFS) Mkdir(path string) error {
+// stash nodes only accept block writes from the holder of the file write lease
+// the lease is renewed in background until the stream is closed
+func (fs *FileStream) acquireWriteLock() error {
+	if fs.readOnly() {
+		return errors.New("snapshots and past versions are read only")
+	}
+	if fs.writeLocked {
+		return nil
+	}
+	if err := fs.renewWriteLock(); err != nil {
+		return err
+	}
+	fs.writeLocked = true
+	fs.leaseStop = make(chan bool)
+	go func(stop chan bool) {
+		for true {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Duration(serv.LEASE_DURATION) / 3):
+				if err := fs.renewWriteLock(); err != nil {
+					log.Println("cannot renew write lease:", err)
+				}
+			}
+		}
+	}(fs.leaseStop)
 	return nil
 }
 
-func (fs *PCFS) Rm(path string) error {
+func (fs *FileStream) renewWriteLock() error {
+	contract := &pb.AcquireFileWriteLockContract{
+		Key:        fs.Meta.Key,
+		ClientTime: uint64(time.Now().UnixNano()),
+	}
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := fs.Filesystem.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.ACQUIRE_FILE_LOCK, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("cannot acquire file write lock")
+	}
 	return nil
 }
 
-func (fs *PCFS) Rmr(path string) error {
+// lands buffered data and gives up the write lease
+func (fs *FileStream) Close() error {
+	fs.LandWrite()
+	if !fs.writeLocked {
+		return nil
+	}
+	if err := fs.commitVersion(); err != nil {
+		log.Println("cannot commit version:", err)
+	}
+	close(fs.leaseStop)
+	fs.writeLocked = false
+	contractData, err := proto.Marshal(&pb.ReleaseFileWriteLockContract{Key: fs.Meta.Key})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Filesystem.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.RELEASE_FILE_LOCK, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("cannot release file write lock")
+	}
 	return nil
 }
 
-func (fs *PCFS) Mv(path string) error {
-	return nil
+// record the expected hash of the block in file meta data for integrity verification
+func (fs *FileStream) updateBlockHash(index uint64, hash []byte) {
+	contract := &pb.UpdateBlockHashContract{
+		File:       fs.Meta.Key,
+		Index:      index,
+		Hash:       hash,
+		ClientTime: uint64(time.Now().UnixNano()),
+	}
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		log.Println("cannot encode update block hash contract:", err)
+		return
+	}
+	res, err := fs.Filesystem.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.UPDATE_BLOCK_HASH, contractData)
+	if err != nil || (*res)[0] != 1 {
+		log.Println("cannot update block hash:", err)
+		return
+	}
+	fs.Meta.Blocks[index].Hash = hash
 }
 
-func (fs *PCFS) NewVolume() {
-	vol := &pb.Volume{
-		Name:         strconv.Itoa(int(fs.Network.BFTRaft.Id)),
-		Key:          []byte{},
-		Replications: 3,
-		BlockSize:    8 * 1024,
-		RootDir:      []byte{},
-	}
-	volData, err := proto.Marshal(vol)
+func (fs *PCFS) Mkdir(dirPath string) error {
+	return fs.MkdirWithPolicy(dirPath, nil)
+}
+
+// fields set in policy override the ones inherited from the parent
+func (fs *PCFS) MkdirWithPolicy(dirPath string, policy *pb.StoragePolicy) error {
+	parentPath, name := path.Split(path.Clean(dirPath))
+	parent, err := fs.lookup(parentPath)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if parent.Type != pb.DirectoryItem_DIR {
+		return errors.New("parent is not a dir")
+	}
+	contract := &pb.NewDirectoryContract{
+		ParentDir: parent.Dir.Key,
+		Dir: &pb.Directory{
+			Name:   name,
+			Mode:   0777 &^ fs.Umask,
+			Policy: policy,
+		},
+		ClientTime: uint64(time.Now().UnixNano()),
 	}
-	volRes, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.NEW_VOLUME, volData)
+	contractData, err := proto.Marshal(contract)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.NEW_DIR, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] == serv.QUOTA_EXCEEDED {
+		return serv.ErrQuotaExceeded
 	}
-	log.Println("new vol res:", volRes)
+	if (*res)[0] != 1 {
+		return errors.New("mkdir failed")
+	}
+	return nil
 }
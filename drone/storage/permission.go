@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Owners and groups are node ids, user groups are created and maintained by their owners
+// User groups can have unique names, they are found by id or by name
+
+func (fs *PCFS) execPermissionContract(funcId uint64, contract proto.Message) error {
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, funcId, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("permission denied")
+	}
+	return nil
+}
+
+func (fs *PCFS) Chmod(itemPath string, mode uint32) error {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	return fs.execPermissionContract(serv.CHMOD, &pb.ChmodContract{
+		Key:  itemKey(item),
+		Dir:  item.Type == pb.DirectoryItem_DIR,
+		Mode: mode,
+	})
+}
+
+func (fs *PCFS) Chown(itemPath string, owner uint64, group uint64) error {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	return fs.execPermissionContract(serv.CHOWN, &pb.ChownContract{
+		Key:   itemKey(item),
+		Dir:   item.Type == pb.DirectoryItem_DIR,
+		Owner: owner,
+		Group: group,
+	})
+}
+
+// creates the user group, or replaces it's members when this node owns it
+func (fs *PCFS) SetUserGroup(id uint64, members []uint64) error {
+	return fs.execPermissionContract(serv.SET_USER_GROUP, &pb.UserGroup{
+		Id:      id,
+		Members: members,
+	})
+}
+
+// creates the user group by name, or replaces it's members when this node owns it
+func (fs *PCFS) SetNamedUserGroup(name string, members []uint64) error {
+	return fs.execPermissionContract(serv.SET_USER_GROUP, &pb.UserGroup{
+		Name:    name,
+		Members: members,
+	})
+}
+
+func (fs *PCFS) RenameUserGroup(id uint64, name string) error {
+	userGroup, err := fs.GetUserGroup(id, "")
+	if err != nil {
+		return err
+	}
+	return fs.execPermissionContract(serv.SET_USER_GROUP, &pb.UserGroup{
+		Id:      id,
+		Name:    name,
+		Members: userGroup.Members,
+	})
+}
+
+// by id, or by name when id is 0
+func (fs *PCFS) GetUserGroup(id uint64, name string) (*pb.UserGroup, error) {
+	req := &pb.GetUserGroupRequest{
+		Group:    serv.STASH_GROUP,
+		Id:       id,
+		Name:     name,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	userGroupI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.GetUserGroup(context.Background(), req)
+		if err != nil {
+			log.Print("cannot get user group: ", err)
+			return nil, []byte{}
+		}
+		feature, err := proto.Marshal(res)
+		if err != nil {
+			return nil, []byte{}
+		}
+		return res, feature
+	})
+	if userGroupI == nil {
+		return nil, errors.New("cannot find user group")
+	}
+	return userGroupI.(*pb.UserGroup), nil
+}
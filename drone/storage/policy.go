@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+)
+
+// Storage policies are set on directories for files and directories created in them afterwards,
+// files can override it at creation with NewStreamWithPolicy
+
+func (fs *PCFS) GetPolicy(itemPath string) (*pb.StoragePolicy, error) {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type == pb.DirectoryItem_DIR {
+		return item.Dir.Policy, nil
+	}
+	return item.File.Policy, nil
+}
+
+func (fs *PCFS) SetPolicy(dirPath string, policy *pb.StoragePolicy) error {
+	item, err := fs.lookup(dirPath)
+	if err != nil {
+		return err
+	}
+	if item.Type != pb.DirectoryItem_DIR {
+		return errors.New("policy can only be changed on dirs")
+	}
+	contractData, err := proto.Marshal(&pb.SetPolicyContract{
+		Key:    item.Dir.Key,
+		Policy: policy,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.SET_POLICY, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("set policy failed")
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+)
+
+// Quotas are set on directories, the quota of a volume is set on it's root path "/<volume>"
+
+// returns limits and current usage of the directory subtree, limits are nil when there are none
+func (fs *PCFS) GetQuota(dirPath string) (*pb.Quota, *pb.DirUsage, error) {
+	item, err := fs.lookup(dirPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item.Type != pb.DirectoryItem_DIR {
+		return nil, nil, errors.New("quota can only be set on dirs")
+	}
+	usage := item.Dir.Usage
+	if usage == nil {
+		if usage, err = fs.countDirUsage(item.Dir.Key); err != nil {
+			return nil, nil, err
+		}
+	}
+	return item.Dir.Quota, usage, nil
+}
+
+func (fs *PCFS) SetQuota(dirPath string, quota *pb.Quota) error {
+	item, err := fs.lookup(dirPath)
+	if err != nil {
+		return err
+	}
+	if item.Type != pb.DirectoryItem_DIR {
+		return errors.New("quota can only be set on dirs")
+	}
+	contractData, err := proto.Marshal(&pb.SetQuotaContract{
+		Key:   item.Dir.Key,
+		Quota: quota,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.SET_QUOTA, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("set quota failed")
+	}
+	return nil
+}
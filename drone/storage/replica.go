@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Block reads go to all replicas of the block and compare their hashes with the hash in file meta data
+// or with the majority when the hash is not recorded yet.
+// Divergent replicas are healed on access, the client pushes the good version back to them.
+// Replicas which cannot be healed are reported to the stash leader, which drops the ones it finds diverged too,
+// so repair will replace them
+// Healing is checked against the current file meta data, not the reader's copy, and is skipped
+// when the hash is not recorded or another client holds the write lease, so a new write is never reverted
+
+type replicaRead struct {
+	hostId uint64
+	block  *pb.BlockData
+	hash   []byte
+}
+
+func (fs *FileStream) readReplica(hostId uint64, index uint64) *replicaRead {
+	host := fs.Filesystem.Network.BFTRaft.GetHostNTXN(hostId)
+	if host == nil {
+		log.Println("cannot find host:", hostId)
+		return nil
+	}
+	client := fs.Filesystem.Network.GetPeerRPC(host)
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := &pb.GetBlockRequest{
+		Group:    serv.STASH_GROUP,
+		Index:    index,
+		File:     fs.Meta.Key,
+		ClientId: fs.Filesystem.Network.BFTRaft.Id,
+	}
+	if fs.readOnly() {
+		req.Hash = fs.Meta.Blocks[index].Hash
+		req.Snapshot = fs.snapshot
+		req.Version = fs.version
+	}
+	if err := fs.Filesystem.Network.SignRequest(req, &req.Signature); err != nil {
+		log.Println("cannot sign block request:", err)
+		return nil
+	}
+	block, err := client.GetBlock(ctx, req)
+	if err != nil {
+		log.Println("cannot get block from", hostId, err)
+		return nil
+	}
+	hash, _ := utils.SHA1Hash(block.Data)
+	return &replicaRead{hostId: hostId, block: block, hash: hash}
+}
+
+func (fs *FileStream) readBlockReplicas(index uint64) (*pb.BlockData, error) {
+	blockMeta := fs.Meta.Blocks[index]
+	reads := make(chan *replicaRead, len(blockMeta.Hosts))
+	for _, hostId := range blockMeta.Hosts {
+		go func(hostId uint64) {
+			reads <- fs.readReplica(hostId, index)
+		}(hostId)
+	}
+	responses := []*replicaRead{}
+	for range blockMeta.Hosts {
+		if read := <-reads; read != nil {
+			responses = append(responses, read)
+		}
+	}
+	if len(responses) == 0 {
+		return nil, errors.New("no replica responded")
+	}
+	expected := blockMeta.Hash
+	if len(expected) == 0 {
+		counts := map[string]int{}
+		for _, read := range responses {
+			counts[string(read.hash)]++
+			if counts[string(read.hash)] > counts[string(expected)] {
+				expected = read.hash
+			}
+		}
+	}
+	var good *replicaRead
+	divergent := []*replicaRead{}
+	for _, read := range responses {
+		if bytes.Equal(read.hash, expected) {
+			if good == nil {
+				good = read
+			}
+		} else {
+			divergent = append(divergent, read)
+		}
+	}
+	if good == nil {
+		return nil, errors.New("no replica matches the block hash")
+	}
+	// replicas of snapshots and versions are frozen, they are not healed by readers
+	if len(divergent) > 0 && !fs.readOnly() {
+		go fs.repairReplicas(good.block, expected, divergent)
+	}
+	return good.block, nil
+}
+
+func (fs *FileStream) repairReplicas(good *pb.BlockData, expected []byte, divergent []*replicaRead) {
+	network := fs.Filesystem.Network
+	meta, err := network.GetMajorityFileMeta(serv.STASH_GROUP, good.File)
+	if err != nil || good.Index >= uint64(len(meta.Blocks)) || !bytes.Equal(meta.Blocks[good.Index].Hash, expected) {
+		log.Println("block", good.Index, "changed since read, skip healing")
+		return
+	}
+	if network.WriteLeasedByOther(serv.STASH_GROUP, good.File, network.BFTRaft.Id) {
+		log.Println("block", good.Index, "is being written, skip healing")
+		return
+	}
+	hosts := meta.Blocks[good.Index].Hosts
+	failed := []uint64{}
+	signed := proto.Clone(good).(*pb.BlockData)
+	signed.ClientId = fs.Filesystem.Network.BFTRaft.Id
+	if err := fs.Filesystem.Network.SignRequest(signed, &signed.Signature); err != nil {
+		log.Println("cannot sign block for repair:", err)
+		return
+	}
+	for _, read := range divergent {
+		listed := false
+		for _, hostId := range hosts {
+			listed = listed || hostId == read.hostId
+		}
+		if !listed {
+			continue
+		}
+		log.Println("replica of block", good.Index, "on", read.hostId, "diverged, pushing good version")
+		host := fs.Filesystem.Network.BFTRaft.GetHostNTXN(read.hostId)
+		if host != nil {
+			if client := fs.Filesystem.Network.GetPeerRPC(host); client != nil {
+				wr, err := client.SetBlock(context.Background(), signed)
+				if err == nil && wr.Succeed && bytes.Equal(wr.BlockHash, expected) {
+					log.Println("replica on", read.hostId, "healed")
+					continue
+				}
+			}
+		}
+		failed = append(failed, read.hostId)
+	}
+	if len(failed) == 0 {
+		return
+	}
+	leader := network.BFTRaft.GetHostNTXN(network.StashLeader())
+	if leader == nil {
+		log.Println("cannot find stash leader to report divergent replicas")
+		return
+	}
+	client := network.GetPeerRPC(leader)
+	if client == nil {
+		return
+	}
+	req := &pb.ReportReplicasRequest{
+		Group:    serv.STASH_GROUP,
+		File:     good.File,
+		Index:    good.Index,
+		Hash:     expected,
+		Hosts:    failed,
+		ClientId: network.BFTRaft.Id,
+	}
+	if err := network.SignRequest(req, &req.Signature); err != nil {
+		log.Println("cannot sign replica report:", err)
+		return
+	}
+	if res, err := client.ReportReplicas(context.Background(), req); err != nil || !res.Succeed {
+		log.Println("cannot report divergent replicas for repair:", err)
+	} else {
+		log.Println("reported divergent replicas for repair:", failed)
+	}
+}
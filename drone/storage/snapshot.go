@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"path"
+	"time"
+)
+
+// Snapshots are browsed read only under "/<volume>/.snapshots/<name>", files in them are opened with NewStream
+
+func (fs *PCFS) execSnapshotContract(funcId uint64, contract proto.Message) (*[]byte, error) {
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return nil, err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, funcId, contractData)
+	if err != nil {
+		return nil, err
+	}
+	if len(*res) == 1 && (*res)[0] != 1 {
+		return nil, errors.New("snapshot contract failed")
+	}
+	return res, nil
+}
+
+func (fs *PCFS) volumeKey(volume string) ([]byte, error) {
+	dirRes := fs.Ls(path.Join("/", volume))
+	if dirRes == nil {
+		return nil, errors.New("cannot find volume")
+	}
+	return dirRes.Volume.Key, nil
+}
+
+func (fs *PCFS) CreateSnapshot(volume string, name string) error {
+	key, err := fs.volumeKey(volume)
+	if err != nil {
+		return err
+	}
+	_, err = fs.execSnapshotContract(serv.SNAPSHOT_VOLUME, &pb.SnapshotContract{
+		Volume:     key,
+		Name:       name,
+		ClientTime: uint64(time.Now().UnixNano()),
+	})
+	return err
+}
+
+func (fs *PCFS) ListSnapshots(volume string) ([]string, error) {
+	dirRes := fs.Ls(path.Join("/", volume, serv.SNAPSHOTS_DIR))
+	if dirRes == nil {
+		return nil, errors.New("cannot list snapshots")
+	}
+	names := []string{}
+	for _, item := range dirRes.Items {
+		names = append(names, item.Dir.Name)
+	}
+	return names, nil
+}
+
+// block contents released by the snapshot are deleted from stashes afterwards
+func (fs *PCFS) DeleteSnapshot(volume string, name string) error {
+	key, err := fs.volumeKey(volume)
+	if err != nil {
+		return err
+	}
+	res, err := fs.execSnapshotContract(serv.DELETE_SNAPSHOT, &pb.DeleteSnapshotContract{
+		Volume: key,
+		Name:   name,
+	})
+	if err != nil {
+		return err
+	}
+	released := &pb.DeletedFiles{}
+	if err := proto.Unmarshal(*res, released); err != nil {
+		return err
+	}
+	fs.deleteBlocks(released.Files, true)
+	return nil
+}
@@ -75,7 +75,8 @@ func Main() {
 		panic(err)
 	}
 	time.Sleep(1 * time.Second)
-	fs := pcfs.GetServer(bftRaft)
+	storageConfig := pcfs.ReadConfigFile("storage.json")
+	fs := pcfs.GetServer(bftRaft, storageConfig)
 	log.Println("registering storage contracts")
 	fs.RegisterStorageContracts()
 	bftRaft.StartServer()
@@ -83,8 +84,17 @@ func Main() {
 	fs.CheckJoinAlphaGroup()
 	//time.Sleep(1 * time.Second)
 	//fs.CheckStashGroup(true)
-	fs.RegisterNode(pcfs.ReadConfigFile("storage.json"))
-	pfs := PCFS{Network: fs}
+	fs.RegisterNode(storageConfig)
+	fs.StartHeartbeat(storageConfig)
+	fs.StartRepair(storageConfig)
+	fs.StartBalancer(storageConfig)
+	fs.StartScrubber(storageConfig)
+	pfs := PCFS{Network: fs, Umask: pcfs.ParseUmask(storageConfig.Umask), Atime: storageConfig.Atime}
+	if len(os.Args) > 1 {
+		runCommand(&pfs, storageConfig, os.Args[1:])
+		fs.BFTRaft.DB.Close()
+		return
+	}
 	pfs.NewVolume()
 	time.Sleep(1 * time.Second)
 	putExampleFiles(&pfs)
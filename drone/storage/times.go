@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Times of files and directories are kept by the stash group, the client only sends it's clock with contracts
+
+// sets access and modification times, zero times are left unchanged
+func (fs *PCFS) Chtimes(itemPath string, atime time.Time, mtime time.Time) error {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	contract := &pb.ChtimesContract{
+		Key:        itemKey(item),
+		Dir:        item.Type == pb.DirectoryItem_DIR,
+		ClientTime: uint64(time.Now().UnixNano()),
+	}
+	if !atime.IsZero() {
+		contract.AccessedAt = uint64(atime.UnixNano())
+	}
+	if !mtime.IsZero() {
+		contract.LastModified = uint64(mtime.UnixNano())
+	}
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.CHTIMES, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("chtimes failed")
+	}
+	return nil
+}
+
+// best effort, sent on the first read of a stream when the access time is stale
+func (fs *FileStream) recordAccess() {
+	if fs.accessed || !fs.Filesystem.Atime || fs.readOnly() {
+		return
+	}
+	fs.accessed = true
+	now := uint64(time.Now().UnixNano())
+	if !serv.AccessTimeStale(fs.Meta, now) {
+		return
+	}
+	contractData, err := proto.Marshal(&pb.AccessFileContract{File: fs.Meta.Key, ClientTime: now})
+	if err != nil {
+		return
+	}
+	res, err := fs.Filesystem.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.ACCESS_FILE, contractData)
+	if err != nil || (*res)[0] != 1 {
+		log.Println("cannot record access time:", err)
+		return
+	}
+	fs.Meta.AccessedAt = now
+}
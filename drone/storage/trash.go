@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"path"
+	"time"
+)
+
+// Trash entries are found by their original path in the volume, the latest deleted one goes first
+
+func (fs *PCFS) ListTrash(volume string) ([]*pb.TrashEntry, error) {
+	key, err := fs.volumeKey(volume)
+	if err != nil {
+		return nil, err
+	}
+	req := &pb.ListTrashRequest{
+		Group:    serv.STASH_GROUP,
+		Volume:   key,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	entriesI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.ListTrash(context.Background(), req)
+		if err != nil {
+			log.Print("cannot list trash: ", err)
+			return nil, []byte{}
+		}
+		feature, err := proto.Marshal(res)
+		if err != nil {
+			return nil, []byte{}
+		}
+		return res, feature
+	})
+	if entriesI == nil {
+		return nil, errors.New("cannot list trash")
+	}
+	return entriesI.(*pb.ListTrashResponse).Entries, nil
+}
+
+func (fs *PCFS) findTrash(volume string, itemPath string) (*pb.TrashEntry, error) {
+	entries, err := fs.ListTrash(volume)
+	if err != nil {
+		return nil, err
+	}
+	var found *pb.TrashEntry
+	itemPath = path.Join("/", itemPath)
+	for _, entry := range entries {
+		if entry.Path == itemPath && (found == nil || entry.DeletedAt > found.DeletedAt) {
+			found = entry
+		}
+	}
+	if found == nil {
+		return nil, errors.New("cannot find " + itemPath + " in trash")
+	}
+	return found, nil
+}
+
+// puts the item back to where it was deleted from
+func (fs *PCFS) RestoreTrash(volume string, itemPath string) error {
+	entry, err := fs.findTrash(volume, itemPath)
+	if err != nil {
+		return err
+	}
+	contractData, err := proto.Marshal(&pb.RestoreTrashContract{
+		Volume:     entry.Volume,
+		Key:        entry.Key,
+		ClientTime: uint64(time.Now().UnixNano()),
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.RESTORE_TRASH, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] == serv.QUOTA_EXCEEDED {
+		return serv.ErrQuotaExceeded
+	}
+	if (*res)[0] != 1 {
+		return errors.New("restore failed")
+	}
+	return nil
+}
+
+// deletes the item permanently before it expires
+func (fs *PCFS) PurgeTrash(volume string, itemPath string) error {
+	entry, err := fs.findTrash(volume, itemPath)
+	if err != nil {
+		return err
+	}
+	contractData, err := proto.Marshal(&pb.PurgeTrashContract{
+		Volume: entry.Volume,
+		Key:    entry.Key,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.PURGE_TRASH, contractData)
+	if err != nil {
+		return err
+	}
+	if len(*res) == 1 {
+		return errors.New("purge failed")
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := proto.Unmarshal(*res, deleted); err != nil {
+		return err
+	}
+	fs.deleteBlocks(deleted.Files, false)
+	fs.deleteBlocks(deleted.Released, true)
+	return nil
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"path"
+	"time"
+)
+
+// Directory usage is kept by the stash group, Du answers without walking the tree
+// Dirs from before usage was kept are counted by the stash group when asked for
+
+func (fs *PCFS) Du(itemPath string) (*pb.DirUsage, error) {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type == pb.DirectoryItem_FILE {
+		return &pb.DirUsage{Bytes: item.File.Size, Files: 1}, nil
+	}
+	if item.Dir.Usage == nil {
+		return fs.countDirUsage(item.Dir.Key)
+	}
+	return item.Dir.Usage, nil
+}
+
+// usage of a dir not counted yet, counted from it's subtree by the stash group
+func (fs *PCFS) countDirUsage(key []byte) (*pb.DirUsage, error) {
+	req := &pb.GetDirectoryRequest{
+		Group:    serv.STASH_GROUP,
+		Key:      key,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	dirI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.GetDirectory(context.Background(), req)
+		if err != nil {
+			log.Print("cannot access node for dir usage")
+			return nil, []byte{}
+		}
+		feature, _ := proto.Marshal(res.Usage)
+		return res, feature
+	})
+	if dirI == nil {
+		return nil, errors.New("cannot count dir usage")
+	}
+	return dirI.(*pb.Directory).Usage, nil
+}
+
+// removes a file or an empty dir, into the trash when the volume has it enabled
+func (fs *PCFS) Rm(itemPath string) error {
+	return fs.deleteItem(itemPath, false, false)
+}
+
+// removes a file or a dir with everything in it, into the trash when the volume has it enabled
+func (fs *PCFS) Rmr(itemPath string) error {
+	return fs.deleteItem(itemPath, true, false)
+}
+
+// removes without going through the trash
+func (fs *PCFS) RmPermanent(itemPath string, recursive bool) error {
+	return fs.deleteItem(itemPath, recursive, true)
+}
+
+func (fs *PCFS) deleteItem(itemPath string, recursive bool, permanent bool) error {
+	parentPath, _ := path.Split(path.Clean(itemPath))
+	parent, err := fs.lookup(parentPath)
+	if err != nil {
+		return err
+	}
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	if parent.Type != pb.DirectoryItem_DIR {
+		return errors.New("parent is not a dir")
+	}
+	contractData, err := proto.Marshal(&pb.DeleteItemContract{
+		Dir:        parent.Dir.Key,
+		Key:        itemKey(item),
+		Recursive:  recursive,
+		Permanent:  permanent,
+		ClientTime: uint64(time.Now().UnixNano()),
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.DELETE_ITEM, contractData)
+	if err != nil {
+		return err
+	}
+	if len(*res) == 1 {
+		return errors.New("delete failed")
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := proto.Unmarshal(*res, deleted); err != nil {
+		return err
+	}
+	fs.deleteBlocks(deleted.Files, false)
+	fs.deleteBlocks(deleted.Released, true)
+	return nil
+}
+
+// best effort, blocks left behind are not referenced by any file
+// with kept, copies kept for snapshots are deleted instead of live blocks
+func (fs *PCFS) deleteBlocks(files []*pb.FileMeta, kept bool) {
+	raft := fs.Network.BFTRaft
+	for _, file := range files {
+		for _, block := range file.Blocks {
+			for _, hostId := range block.Hosts {
+				host := raft.GetHostNTXN(hostId)
+				if host == nil {
+					continue
+				}
+				c := fs.Network.GetPeerRPC(host)
+				if c == nil {
+					continue
+				}
+				req := &pb.DeleteBlockRequest{
+					Group:    serv.STASH_GROUP,
+					Index:    block.Index,
+					File:     file.Key,
+					ClientId: raft.Id,
+				}
+				if kept {
+					req.Hash = block.Hash
+				}
+				if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+					log.Println("cannot sign delete block request:", err)
+					continue
+				}
+				if _, err := c.DeleteBlock(context.Background(), req); err != nil {
+					log.Println("cannot delete block", block.Index, "of", file.Name, "from", hostId, ":", err)
+				}
+			}
+		}
+	}
+}
+
+// moves or renames an item, into dst when it's an existing dir
+func (fs *PCFS) Mv(src string, dst string) error {
+	srcParentPath, name := path.Split(path.Clean(src))
+	srcParent, err := fs.lookup(srcParentPath)
+	if err != nil {
+		return err
+	}
+	item, err := fs.lookup(src)
+	if err != nil {
+		return err
+	}
+	dstParent, err := fs.lookup(dst)
+	if err != nil || dstParent.Type != pb.DirectoryItem_DIR {
+		var dstParentPath string
+		dstParentPath, name = path.Split(path.Clean(dst))
+		if dstParent, err = fs.lookup(dstParentPath); err != nil {
+			return err
+		}
+	}
+	contractData, err := proto.Marshal(&pb.MoveItemContract{
+		SrcDir:     srcParent.Dir.Key,
+		Key:        itemKey(item),
+		DstDir:     dstParent.Dir.Key,
+		Name:       name,
+		ClientTime: uint64(time.Now().UnixNano()),
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.MOVE_ITEM, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] == serv.QUOTA_EXCEEDED {
+		return serv.ErrQuotaExceeded
+	}
+	if (*res)[0] != 1 {
+		return errors.New("move failed")
+	}
+	return nil
+}
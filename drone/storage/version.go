@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// A version is committed when a write session is closed on a volume with versioning enabled
+
+// invoked while the write lock is still held
+func (fs *FileStream) commitVersion() error {
+	contractData, err := proto.Marshal(&pb.CommitVersionContract{
+		File:       fs.Meta.Key,
+		ClientTime: uint64(time.Now().UnixNano()),
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Filesystem.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.COMMIT_VERSION, contractData)
+	if err != nil {
+		return err
+	}
+	if len(*res) == 1 {
+		return errors.New("commit version contract failed")
+	}
+	released := &pb.DeletedFiles{}
+	if err := proto.Unmarshal(*res, released); err != nil {
+		return err
+	}
+	fs.Filesystem.deleteBlocks(released.Released, true)
+	return nil
+}
+
+// oldest first
+func (fs *PCFS) ListVersions(filePath string) ([]*pb.FileVersion, error) {
+	item, err := fs.lookup(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type != pb.DirectoryItem_FILE {
+		return nil, errors.New("only files have versions")
+	}
+	req := &pb.ListVersionsRequest{
+		Group:    serv.STASH_GROUP,
+		File:     item.File.Key,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	versionsI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.ListVersions(context.Background(), req)
+		if err != nil {
+			log.Print("cannot list versions: ", err)
+			return nil, []byte{}
+		}
+		feature, err := proto.Marshal(res)
+		if err != nil {
+			return nil, []byte{}
+		}
+		return res, feature
+	})
+	if versionsI == nil {
+		return nil, errors.New("cannot list versions")
+	}
+	return versionsI.(*pb.ListVersionsResponse).Versions, nil
+}
+
+// the stream is read only
+func (fs *PCFS) OpenVersion(filePath string, number uint64) (*FileStream, error) {
+	versions, err := fs.ListVersions(filePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		if version.Number == number {
+			return &FileStream{
+				Meta:       version.Meta,
+				Filesystem: fs,
+				version:    number,
+			}, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprint("cannot find version ", number, " of ", filePath))
+}
+
+// the file takes the blocks of the version back, closing the stream commits it as a new version
+func (fs *PCFS) RestoreVersion(filePath string, number uint64) error {
+	old, err := fs.OpenVersion(filePath, number)
+	if err != nil {
+		return err
+	}
+	stream, err := fs.NewStream(filePath)
+	if err != nil {
+		return err
+	}
+	if err := stream.acquireWriteLock(); err != nil {
+		return err
+	}
+	live := stream.Meta
+	contractData, err := proto.Marshal(&pb.RestoreVersionContract{
+		File:       live.Key,
+		Number:     number,
+		ClientTime: uint64(time.Now().UnixNano()),
+	})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.RESTORE_VERSION, contractData)
+	if err != nil {
+		return err
+	}
+	if len(*res) == 1 {
+		stream.Close()
+		if (*res)[0] == serv.QUOTA_EXCEEDED {
+			return serv.ErrQuotaExceeded
+		}
+		return errors.New("restore version contract failed")
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := proto.Unmarshal(*res, deleted); err != nil {
+		return err
+	}
+	for _, block := range old.Meta.Blocks {
+		if block.Index < uint64(len(live.Blocks)) && bytes.Equal(live.Blocks[block.Index].Hash, block.Hash) {
+			continue
+		}
+		fs.restoreBlock(live.Key, block)
+	}
+	fs.deleteBlocks(deleted.Files, false)
+	stream.Meta = proto.Clone(old.Meta).(*pb.FileMeta)
+	stream.currentBlockData = nil
+	return stream.Close()
+}
+
+// best effort, hosts that cannot put the content back diverge and are healed by readers or repaired
+func (fs *PCFS) restoreBlock(file []byte, block *pb.Block) {
+	raft := fs.Network.BFTRaft
+	for _, hostId := range block.Hosts {
+		host := raft.GetHostNTXN(hostId)
+		if host == nil {
+			continue
+		}
+		c := fs.Network.GetPeerRPC(host)
+		if c == nil {
+			continue
+		}
+		req := &pb.ReplicateBlockRequest{
+			Group:    serv.STASH_GROUP,
+			Index:    block.Index,
+			File:     file,
+			Source:   hostId,
+			Hash:     block.Hash,
+			ClientId: raft.Id,
+		}
+		if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+			log.Println("cannot sign restore block request:", err)
+			continue
+		}
+		if _, err := c.ReplicateBlock(context.Background(), req); err != nil {
+			log.Println("cannot restore block", block.Index, "on", hostId, ":", err)
+		}
+	}
+}
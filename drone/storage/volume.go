@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+func (fs *PCFS) execVolumeContract(funcId uint64, contract proto.Message) error {
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, funcId, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("volume contract failed")
+	}
+	return nil
+}
+
+// zero replications or block size take the defaults
+func (fs *PCFS) CreateVolume(name string, replications uint32, blockSize uint32) error {
+	return fs.execVolumeContract(serv.NEW_VOLUME, &pb.Volume{
+		Name:         name,
+		Replications: replications,
+		BlockSize:    blockSize,
+	})
+}
+
+// creates the home volume of this node if it's not there
+func (fs *PCFS) NewVolume() {
+	if err := fs.CreateVolume("home", 0, 0); err != nil {
+		log.Println("cannot create home volume:", err)
+	}
+}
+
+func (fs *PCFS) InspectVolume(name string) (*pb.VolumeUsage, error) {
+	req := &pb.GetVolumeRequest{
+		Group:    serv.STASH_GROUP,
+		Name:     name,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	usageI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.GetVolumeUsage(context.Background(), req)
+		if err != nil {
+			log.Print("cannot access node for volume usage")
+			return nil, []byte{}
+		}
+		feature, _ := proto.Marshal(res)
+		return res, feature
+	})
+	if usageI == nil {
+		return nil, errors.New("cannot get volume usage")
+	}
+	return usageI.(*pb.VolumeUsage), nil
+}
+
+// zero keeps the current value
+func (fs *PCFS) UpdateVolume(name string, replications uint32, blockSize uint32) error {
+	usage, err := fs.InspectVolume(name)
+	if err != nil {
+		return err
+	}
+	return fs.execVolumeContract(serv.UPDATE_VOLUME, &pb.UpdateVolumeContract{
+		Key:          usage.Volume.Key,
+		Replications: replications,
+		BlockSize:    blockSize,
+	})
+}
+
+// versions of files are kept on closing write sessions when enabled, with zero keep or max age unlimited
+func (fs *PCFS) SetVersioning(name string, retention *pb.VersionRetention) error {
+	usage, err := fs.InspectVolume(name)
+	if err != nil {
+		return err
+	}
+	return fs.execVolumeContract(serv.UPDATE_VOLUME, &pb.UpdateVolumeContract{
+		Key:        usage.Volume.Key,
+		Versioning: retention,
+	})
+}
+
+// deleted items are kept in the trash when enabled, with zero retention until purged
+func (fs *PCFS) SetTrash(name string, retention *pb.TrashRetention) error {
+	usage, err := fs.InspectVolume(name)
+	if err != nil {
+		return err
+	}
+	return fs.execVolumeContract(serv.UPDATE_VOLUME, &pb.UpdateVolumeContract{
+		Key:   usage.Volume.Key,
+		Trash: retention,
+	})
+}
+
+func (fs *PCFS) DeleteVolume(name string) error {
+	usage, err := fs.InspectVolume(name)
+	if err != nil {
+		return err
+	}
+	return fs.execVolumeContract(serv.DELETE_VOLUME, &pb.DeleteVolumeContract{Key: usage.Volume.Key})
+}
+
+// volumes owned by this node and volumes it can list
+func (fs *PCFS) ListVolumes() ([]*pb.Volume, error) {
+	req := &pb.ListVolumesRequest{
+		Group:    serv.STASH_GROUP,
+		ClientId: fs.Network.BFTRaft.Id,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	volumesI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.ListVolumes(context.Background(), req)
+		if err != nil {
+			log.Print("cannot access node for volume list")
+			return nil, []byte{}
+		}
+		feature, _ := proto.Marshal(res)
+		return res.Volumes, feature
+	})
+	if volumesI == nil {
+		return nil, errors.New("cannot list volumes")
+	}
+	return volumesI.([]*pb.Volume), nil
+}
+
+// only stash owners can allow other clients to create volumes
+func (fs *PCFS) SetVolumeCreators(ids []uint64) error {
+	contractData, err := proto.Marshal(&pb.PrincipalList{Ids: ids})
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.SET_VOL_CREATORS, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("set volume creators failed")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	serv "github.com/PomeloCloud/pcfs/server"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Extended attributes of files and directories, values are opaque bytes to the file system
+
+func (fs *PCFS) Stat(itemPath string, xattrs bool) (*pb.DirectoryItem, error) {
+	req := &pb.StatRequest{
+		Group:    serv.STASH_GROUP,
+		Path:     itemPath,
+		ClientId: fs.Network.BFTRaft.Id,
+		Xattrs:   xattrs,
+	}
+	if err := fs.Network.SignRequest(req, &req.Signature); err != nil {
+		return nil, err
+	}
+	itemI := fs.Network.GroupMajorityResponse(serv.STASH_GROUP, func(client pb.PCFSClient) (interface{}, []byte) {
+		res, err := client.Stat(context.Background(), req)
+		if err != nil {
+			log.Print("cannot stat: ", err)
+			return nil, []byte{}
+		}
+		feature, err := proto.Marshal(res)
+		if err != nil {
+			return nil, []byte{}
+		}
+		return res, feature
+	})
+	if itemI == nil {
+		return nil, errors.New("cannot stat " + itemPath)
+	}
+	return itemI.(*pb.DirectoryItem), nil
+}
+
+func (fs *PCFS) ListXattrs(itemPath string) ([]*pb.Xattr, error) {
+	item, err := fs.Stat(itemPath, true)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type == pb.DirectoryItem_DIR {
+		return item.Dir.Xattrs, nil
+	}
+	return item.File.Xattrs, nil
+}
+
+func (fs *PCFS) GetXattr(itemPath string, name string) ([]byte, error) {
+	xattrs, err := fs.ListXattrs(itemPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, xattr := range xattrs {
+		if xattr.Name == name {
+			return xattr.Value, nil
+		}
+	}
+	return nil, errors.New("no attribute " + name + " on " + itemPath)
+}
+
+func (fs *PCFS) SetXattr(itemPath string, name string, value []byte) error {
+	return fs.setXattr(itemPath, &pb.SetXattrContract{Name: name, Value: value})
+}
+
+func (fs *PCFS) RemoveXattr(itemPath string, name string) error {
+	return fs.setXattr(itemPath, &pb.SetXattrContract{Name: name, Remove: true})
+}
+
+func (fs *PCFS) setXattr(itemPath string, contract *pb.SetXattrContract) error {
+	item, err := fs.lookup(itemPath)
+	if err != nil {
+		return err
+	}
+	contract.Key = itemKey(item)
+	contract.Dir = item.Type == pb.DirectoryItem_DIR
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := fs.Network.BFTRaft.Client.ExecCommand(serv.STASH_GROUP, serv.SET_XATTR, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("set xattr contract failed")
+	}
+	return nil
+}
@@ -11,8 +11,40 @@ It has these top-level messages:
 	BlockData
 	Block
 	FileMeta
+	Xattr
+	SetXattrContract
 	Directory
+	Quota
+	DirUsage
+	DeleteItemContract
+	DeletedFiles
+	MoveItemContract
+	Snapshot
+	SnapshotContract
+	MoveKeptBlockContract
+	DeleteSnapshotContract
+	DiffRequest
+	DiffEntry
+	DiffResponse
+	ExportHeader
+	ExportRecord
+	SetQuotaContract
+	StoragePolicy
+	SetPolicyContract
 	Volume
+	TrashRetention
+	TrashEntry
+	RestoreTrashContract
+	PurgeTrashContract
+	ListTrashRequest
+	ListTrashResponse
+	VersionRetention
+	FileVersion
+	CommitVersionContract
+	RestoreVersionContract
+	ListVersionsRequest
+	ListVersionsResponse
+	AclEntry
 	HostStash
 	OpenRequest
 	GetBlockRequest
@@ -21,9 +53,17 @@ It has these top-level messages:
 	CreateBlockRequest
 	GetFileRequest
 	GetVolumeRequest
+	ListVolumesRequest
+	ListVolumesResponse
+	VolumeUsage
+	ReplicationJob
+	UpdateVolumeContract
+	DeleteVolumeContract
+	PrincipalList
 	GetDirectoryRequest
 	BlockStashSuggestionRequest
 	BlockStashSuggestion
+	ReplicateBlockRequest
 	WriteResult
 	NewDirectoryContract
 	AcquireFileWriteLockContract
@@ -31,10 +71,29 @@ It has these top-level messages:
 	TouchFileContract
 	ConfirmBlockContract
 	CommitBlockContract
+	UpdateBlockHashContract
+	TruncateFileContract
+	AccessFileContract
+	ChtimesContract
+	ReplaceBlockReplicasContract
+	ReportReplicasRequest
+	StashHeartbeatContract
+	SetStashStateContract
+	DeregStashContract
 	FileWriteLock
+	AdvisoryLockHolder
+	AdvisoryLock
+	LockContract
+	UnlockContract
 	DirectoryItem
 	ListDirectoryResponse
 	ListDirectoryRequest
+	StatRequest
+	ChmodContract
+	ChownContract
+	UserGroup
+	GetUserGroupRequest
+	SetAclContract
 	Nothing
 */
 package client
@@ -59,6 +118,84 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type StashState int32
+
+const (
+	StashState_ONLINE      StashState = 0
+	StashState_SUSPECT     StashState = 1
+	StashState_DEAD        StashState = 2
+	StashState_MAINTENANCE StashState = 3
+	StashState_DRAINING    StashState = 4
+)
+
+var StashState_name = map[int32]string{
+	0: "ONLINE",
+	1: "SUSPECT",
+	2: "DEAD",
+	3: "MAINTENANCE",
+	4: "DRAINING",
+}
+var StashState_value = map[string]int32{
+	"ONLINE":      0,
+	"SUSPECT":     1,
+	"DEAD":        2,
+	"MAINTENANCE": 3,
+	"DRAINING":    4,
+}
+
+func (x StashState) String() string {
+	return proto.EnumName(StashState_name, int32(x))
+}
+func (StashState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type LockMode int32
+
+const (
+	LockMode_SHARED    LockMode = 0
+	LockMode_EXCLUSIVE LockMode = 1
+)
+
+var LockMode_name = map[int32]string{
+	0: "SHARED",
+	1: "EXCLUSIVE",
+}
+var LockMode_value = map[string]int32{
+	"SHARED":    0,
+	"EXCLUSIVE": 1,
+}
+
+func (x LockMode) String() string {
+	return proto.EnumName(LockMode_name, int32(x))
+}
+func (LockMode) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+type DiffEntry_Change int32
+
+const (
+	DiffEntry_ADDED    DiffEntry_Change = 0
+	DiffEntry_REMOVED  DiffEntry_Change = 1
+	DiffEntry_MODIFIED DiffEntry_Change = 2
+	DiffEntry_RENAMED  DiffEntry_Change = 3
+)
+
+var DiffEntry_Change_name = map[int32]string{
+	0: "ADDED",
+	1: "REMOVED",
+	2: "MODIFIED",
+	3: "RENAMED",
+}
+var DiffEntry_Change_value = map[string]int32{
+	"ADDED":    0,
+	"REMOVED":  1,
+	"MODIFIED": 2,
+	"RENAMED":  3,
+}
+
+func (x DiffEntry_Change) String() string {
+	return proto.EnumName(DiffEntry_Change_name, int32(x))
+}
+func (DiffEntry_Change) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{16, 0} }
+
 type DirectoryItem_ItemType int32
 
 const (
@@ -78,14 +215,16 @@ var DirectoryItem_ItemType_value = map[string]int32{
 func (x DirectoryItem_ItemType) String() string {
 	return proto.EnumName(DirectoryItem_ItemType_name, int32(x))
 }
-func (DirectoryItem_ItemType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{24, 0} }
+func (DirectoryItem_ItemType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{77, 0} }
 
 type BlockData struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Index uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
-	Tail  uint32 `protobuf:"varint,3,opt,name=tail" json:"tail,omitempty"`
-	File  []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
-	Data  []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Index     uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Tail      uint32 `protobuf:"varint,3,opt,name=tail" json:"tail,omitempty"`
+	File      []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Data      []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	ClientId  uint64 `protobuf:"varint,6,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (m *BlockData) Reset()                    { *m = BlockData{} }
@@ -128,9 +267,24 @@ func (m *BlockData) GetData() []byte {
 	return nil
 }
 
+func (m *BlockData) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *BlockData) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type Block struct {
 	Index uint64   `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
 	Hosts []uint64 `protobuf:"varint,2,rep,packed,name=hosts" json:"hosts,omitempty"`
+	Hash  []byte   `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
 }
 
 func (m *Block) Reset()                    { *m = Block{} }
@@ -152,14 +306,31 @@ func (m *Block) GetHosts() []uint64 {
 	return nil
 }
 
+func (m *Block) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
 type FileMeta struct {
-	Name         string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Size         uint64   `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
-	LastModified uint64   `protobuf:"varint,3,opt,name=last_modified,json=lastModified" json:"last_modified,omitempty"`
-	CreatedAt    uint64   `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
-	BlockSize    uint32   `protobuf:"varint,6,opt,name=block_size,json=blockSize" json:"block_size,omitempty"`
-	Key          []byte   `protobuf:"bytes,7,opt,name=key,proto3" json:"key,omitempty"`
-	Blocks       []*Block `protobuf:"bytes,8,rep,name=blocks" json:"blocks,omitempty"`
+	Name         string         `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Size         uint64         `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+	LastModified uint64         `protobuf:"varint,3,opt,name=last_modified,json=lastModified" json:"last_modified,omitempty"`
+	CreatedAt    uint64         `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	BlockSize    uint32         `protobuf:"varint,6,opt,name=block_size,json=blockSize" json:"block_size,omitempty"`
+	Key          []byte         `protobuf:"bytes,7,opt,name=key,proto3" json:"key,omitempty"`
+	Blocks       []*Block       `protobuf:"bytes,8,rep,name=blocks" json:"blocks,omitempty"`
+	Volume       []byte         `protobuf:"bytes,9,opt,name=volume,proto3" json:"volume,omitempty"`
+	Dir          []byte         `protobuf:"bytes,10,opt,name=dir,proto3" json:"dir,omitempty"`
+	Owner        uint64         `protobuf:"varint,11,opt,name=owner" json:"owner,omitempty"`
+	Group        uint64         `protobuf:"varint,12,opt,name=group" json:"group,omitempty"`
+	Mode         uint32         `protobuf:"varint,13,opt,name=mode" json:"mode,omitempty"`
+	Policy       *StoragePolicy `protobuf:"bytes,14,opt,name=policy" json:"policy,omitempty"`
+	Version      uint64         `protobuf:"varint,15,opt,name=version" json:"version,omitempty"`
+	Xattrs       []*Xattr       `protobuf:"bytes,16,rep,name=xattrs" json:"xattrs,omitempty"`
+	ChangedAt    uint64         `protobuf:"varint,17,opt,name=changed_at,json=changedAt" json:"changed_at,omitempty"`
+	AccessedAt   uint64         `protobuf:"varint,18,opt,name=accessed_at,json=accessedAt" json:"accessed_at,omitempty"`
 }
 
 func (m *FileMeta) Reset()                    { *m = FileMeta{} }
@@ -216,732 +387,3370 @@ func (m *FileMeta) GetBlocks() []*Block {
 	return nil
 }
 
-type Directory struct {
-	Name  string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Key   []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Files [][]byte `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
-}
-
-func (m *Directory) Reset()                    { *m = Directory{} }
-func (m *Directory) String() string            { return proto.CompactTextString(m) }
-func (*Directory) ProtoMessage()               {}
-func (*Directory) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
-
-func (m *Directory) GetName() string {
+func (m *FileMeta) GetVolume() []byte {
 	if m != nil {
-		return m.Name
+		return m.Volume
 	}
-	return ""
+	return nil
 }
 
-func (m *Directory) GetKey() []byte {
+func (m *FileMeta) GetDir() []byte {
 	if m != nil {
-		return m.Key
+		return m.Dir
 	}
 	return nil
 }
 
-func (m *Directory) GetFiles() [][]byte {
+func (m *FileMeta) GetOwner() uint64 {
 	if m != nil {
-		return m.Files
+		return m.Owner
 	}
-	return nil
+	return 0
 }
 
-type Volume struct {
-	Name         string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Key          []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Replications uint32 `protobuf:"varint,3,opt,name=replications" json:"replications,omitempty"`
-	BlockSize    uint32 `protobuf:"varint,4,opt,name=block_size,json=blockSize" json:"block_size,omitempty"`
-	RootDir      []byte `protobuf:"bytes,5,opt,name=root_dir,json=rootDir,proto3" json:"root_dir,omitempty"`
+func (m *FileMeta) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
 }
 
-func (m *Volume) Reset()                    { *m = Volume{} }
-func (m *Volume) String() string            { return proto.CompactTextString(m) }
-func (*Volume) ProtoMessage()               {}
-func (*Volume) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
-
-func (m *Volume) GetName() string {
+func (m *FileMeta) GetMode() uint32 {
 	if m != nil {
-		return m.Name
+		return m.Mode
 	}
-	return ""
+	return 0
 }
 
-func (m *Volume) GetKey() []byte {
+func (m *FileMeta) GetPolicy() *StoragePolicy {
 	if m != nil {
-		return m.Key
+		return m.Policy
 	}
 	return nil
 }
 
-func (m *Volume) GetReplications() uint32 {
+func (m *FileMeta) GetVersion() uint64 {
 	if m != nil {
-		return m.Replications
+		return m.Version
 	}
 	return 0
 }
 
-func (m *Volume) GetBlockSize() uint32 {
+func (m *FileMeta) GetXattrs() []*Xattr {
 	if m != nil {
-		return m.BlockSize
+		return m.Xattrs
+	}
+	return nil
+}
+
+func (m *FileMeta) GetChangedAt() uint64 {
+	if m != nil {
+		return m.ChangedAt
 	}
 	return 0
 }
 
-func (m *Volume) GetRootDir() []byte {
+func (m *FileMeta) GetAccessedAt() uint64 {
 	if m != nil {
-		return m.RootDir
+		return m.AccessedAt
 	}
-	return nil
+	return 0
 }
 
-type HostStash struct {
-	HostId   uint64 `protobuf:"varint,1,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
-	Capacity uint64 `protobuf:"varint,2,opt,name=capacity" json:"capacity,omitempty"`
-	Used     uint64 `protobuf:"varint,3,opt,name=used" json:"used,omitempty"`
-	Owner    uint64 `protobuf:"varint,4,opt,name=owner" json:"owner,omitempty"`
+type Xattr struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (m *HostStash) Reset()                    { *m = HostStash{} }
-func (m *HostStash) String() string            { return proto.CompactTextString(m) }
-func (*HostStash) ProtoMessage()               {}
-func (*HostStash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *Xattr) Reset()                    { *m = Xattr{} }
+func (m *Xattr) String() string            { return proto.CompactTextString(m) }
+func (*Xattr) ProtoMessage()               {}
+func (*Xattr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
-func (m *HostStash) GetHostId() uint64 {
+func (m *Xattr) GetName() string {
 	if m != nil {
-		return m.HostId
+		return m.Name
 	}
-	return 0
+	return ""
 }
 
-func (m *HostStash) GetCapacity() uint64 {
+func (m *Xattr) GetValue() []byte {
 	if m != nil {
-		return m.Capacity
+		return m.Value
 	}
-	return 0
+	return nil
 }
 
-func (m *HostStash) GetUsed() uint64 {
-	if m != nil {
-		return m.Used
-	}
-	return 0
+type SetXattrContract struct {
+	Key    []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Dir    bool   `protobuf:"varint,2,opt,name=dir" json:"dir,omitempty"`
+	Name   string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Value  []byte `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Remove bool   `protobuf:"varint,5,opt,name=remove" json:"remove,omitempty"`
 }
 
-func (m *HostStash) GetOwner() uint64 {
+func (m *SetXattrContract) Reset()                    { *m = SetXattrContract{} }
+func (m *SetXattrContract) String() string            { return proto.CompactTextString(m) }
+func (*SetXattrContract) ProtoMessage()               {}
+func (*SetXattrContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *SetXattrContract) GetKey() []byte {
 	if m != nil {
-		return m.Owner
+		return m.Key
 	}
-	return 0
+	return nil
 }
 
-type OpenRequest struct {
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Key  []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+func (m *SetXattrContract) GetDir() bool {
+	if m != nil {
+		return m.Dir
+	}
+	return false
 }
 
-func (m *OpenRequest) Reset()                    { *m = OpenRequest{} }
-func (m *OpenRequest) String() string            { return proto.CompactTextString(m) }
-func (*OpenRequest) ProtoMessage()               {}
-func (*OpenRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
-
-func (m *OpenRequest) GetName() string {
+func (m *SetXattrContract) GetName() string {
 	if m != nil {
 		return m.Name
 	}
 	return ""
 }
 
-func (m *OpenRequest) GetKey() []byte {
+func (m *SetXattrContract) GetValue() []byte {
 	if m != nil {
-		return m.Key
+		return m.Value
 	}
 	return nil
 }
 
-type GetBlockRequest struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Index uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
-	File  []byte `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+func (m *SetXattrContract) GetRemove() bool {
+	if m != nil {
+		return m.Remove
+	}
+	return false
 }
 
-func (m *GetBlockRequest) Reset()                    { *m = GetBlockRequest{} }
-func (m *GetBlockRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetBlockRequest) ProtoMessage()               {}
-func (*GetBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+type Directory struct {
+	Name         string         `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Key          []byte         `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Files        [][]byte       `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Owner        uint64         `protobuf:"varint,4,opt,name=owner" json:"owner,omitempty"`
+	Group        uint64         `protobuf:"varint,5,opt,name=group" json:"group,omitempty"`
+	Mode         uint32         `protobuf:"varint,6,opt,name=mode" json:"mode,omitempty"`
+	Acl          []*AclEntry    `protobuf:"bytes,7,rep,name=acl" json:"acl,omitempty"`
+	Parent       []byte         `protobuf:"bytes,8,opt,name=parent,proto3" json:"parent,omitempty"`
+	Volume       []byte         `protobuf:"bytes,9,opt,name=volume,proto3" json:"volume,omitempty"`
+	Policy       *StoragePolicy `protobuf:"bytes,10,opt,name=policy" json:"policy,omitempty"`
+	Quota        *Quota         `protobuf:"bytes,11,opt,name=quota" json:"quota,omitempty"`
+	Usage        *DirUsage      `protobuf:"bytes,12,opt,name=usage" json:"usage,omitempty"`
+	Xattrs       []*Xattr       `protobuf:"bytes,13,rep,name=xattrs" json:"xattrs,omitempty"`
+	LastModified uint64         `protobuf:"varint,14,opt,name=last_modified,json=lastModified" json:"last_modified,omitempty"`
+	CreatedAt    uint64         `protobuf:"varint,15,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	ChangedAt    uint64         `protobuf:"varint,16,opt,name=changed_at,json=changedAt" json:"changed_at,omitempty"`
+}
 
-func (m *GetBlockRequest) GetGroup() uint64 {
+func (m *Directory) Reset()                    { *m = Directory{} }
+func (m *Directory) String() string            { return proto.CompactTextString(m) }
+func (*Directory) ProtoMessage()               {}
+func (*Directory) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Directory) GetName() string {
 	if m != nil {
-		return m.Group
+		return m.Name
 	}
-	return 0
+	return ""
 }
 
-func (m *GetBlockRequest) GetIndex() uint64 {
+func (m *Directory) GetKey() []byte {
 	if m != nil {
-		return m.Index
+		return m.Key
 	}
-	return 0
+	return nil
 }
 
-func (m *GetBlockRequest) GetFile() []byte {
+func (m *Directory) GetFiles() [][]byte {
 	if m != nil {
-		return m.File
+		return m.Files
 	}
 	return nil
 }
 
-type AppendToBlockRequest struct {
-	Group  uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Index  uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
-	Offset uint32 `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
-	File   []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
-	Data   []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
-}
-
-func (m *AppendToBlockRequest) Reset()                    { *m = AppendToBlockRequest{} }
-func (m *AppendToBlockRequest) String() string            { return proto.CompactTextString(m) }
-func (*AppendToBlockRequest) ProtoMessage()               {}
-func (*AppendToBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
-
-func (m *AppendToBlockRequest) GetGroup() uint64 {
+func (m *Directory) GetOwner() uint64 {
 	if m != nil {
-		return m.Group
+		return m.Owner
 	}
 	return 0
 }
 
-func (m *AppendToBlockRequest) GetIndex() uint64 {
+func (m *Directory) GetGroup() uint64 {
 	if m != nil {
-		return m.Index
+		return m.Group
 	}
 	return 0
 }
 
-func (m *AppendToBlockRequest) GetOffset() uint32 {
+func (m *Directory) GetMode() uint32 {
 	if m != nil {
-		return m.Offset
+		return m.Mode
 	}
 	return 0
 }
 
-func (m *AppendToBlockRequest) GetFile() []byte {
+func (m *Directory) GetAcl() []*AclEntry {
 	if m != nil {
-		return m.File
+		return m.Acl
 	}
 	return nil
 }
 
-func (m *AppendToBlockRequest) GetData() []byte {
+func (m *Directory) GetParent() []byte {
 	if m != nil {
-		return m.Data
+		return m.Parent
 	}
 	return nil
 }
 
-type DeleteBlockRequest struct {
-	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-}
-
-func (m *DeleteBlockRequest) Reset()                    { *m = DeleteBlockRequest{} }
-func (m *DeleteBlockRequest) String() string            { return proto.CompactTextString(m) }
-func (*DeleteBlockRequest) ProtoMessage()               {}
-func (*DeleteBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
-
-func (m *DeleteBlockRequest) GetKey() []byte {
+func (m *Directory) GetVolume() []byte {
 	if m != nil {
-		return m.Key
+		return m.Volume
 	}
 	return nil
 }
 
-type CreateBlockRequest struct {
-	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Index     uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
-	File      []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
-	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
-}
-
-func (m *CreateBlockRequest) Reset()                    { *m = CreateBlockRequest{} }
-func (m *CreateBlockRequest) String() string            { return proto.CompactTextString(m) }
-func (*CreateBlockRequest) ProtoMessage()               {}
-func (*CreateBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
-
-func (m *CreateBlockRequest) GetGroup() uint64 {
+func (m *Directory) GetPolicy() *StoragePolicy {
 	if m != nil {
-		return m.Group
+		return m.Policy
 	}
-	return 0
+	return nil
 }
 
-func (m *CreateBlockRequest) GetIndex() uint64 {
+func (m *Directory) GetQuota() *Quota {
 	if m != nil {
-		return m.Index
+		return m.Quota
 	}
-	return 0
+	return nil
 }
 
-func (m *CreateBlockRequest) GetFile() []byte {
+func (m *Directory) GetUsage() *DirUsage {
 	if m != nil {
-		return m.File
+		return m.Usage
 	}
 	return nil
 }
 
-func (m *CreateBlockRequest) GetSignature() []byte {
+func (m *Directory) GetXattrs() []*Xattr {
 	if m != nil {
-		return m.Signature
+		return m.Xattrs
 	}
 	return nil
 }
 
-type GetFileRequest struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	File  []byte `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+func (m *Directory) GetLastModified() uint64 {
+	if m != nil {
+		return m.LastModified
+	}
+	return 0
 }
 
-func (m *GetFileRequest) Reset()                    { *m = GetFileRequest{} }
-func (m *GetFileRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetFileRequest) ProtoMessage()               {}
-func (*GetFileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
-
-func (m *GetFileRequest) GetGroup() uint64 {
+func (m *Directory) GetCreatedAt() uint64 {
 	if m != nil {
-		return m.Group
+		return m.CreatedAt
 	}
 	return 0
 }
 
-func (m *GetFileRequest) GetFile() []byte {
+func (m *Directory) GetChangedAt() uint64 {
 	if m != nil {
-		return m.File
+		return m.ChangedAt
 	}
-	return nil
+	return 0
 }
 
-type GetVolumeRequest struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Name  string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+type Quota struct {
+	Bytes uint64 `protobuf:"varint,1,opt,name=bytes" json:"bytes,omitempty"`
+	Files uint64 `protobuf:"varint,2,opt,name=files" json:"files,omitempty"`
 }
 
-func (m *GetVolumeRequest) Reset()                    { *m = GetVolumeRequest{} }
-func (m *GetVolumeRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetVolumeRequest) ProtoMessage()               {}
-func (*GetVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (m *Quota) Reset()                    { *m = Quota{} }
+func (m *Quota) String() string            { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()               {}
+func (*Quota) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
-func (m *GetVolumeRequest) GetGroup() uint64 {
+func (m *Quota) GetBytes() uint64 {
 	if m != nil {
-		return m.Group
+		return m.Bytes
 	}
 	return 0
 }
 
-func (m *GetVolumeRequest) GetName() string {
+func (m *Quota) GetFiles() uint64 {
 	if m != nil {
-		return m.Name
+		return m.Files
 	}
-	return ""
+	return 0
 }
 
-type GetDirectoryRequest struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Key   []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+type DirUsage struct {
+	Bytes uint64 `protobuf:"varint,1,opt,name=bytes" json:"bytes,omitempty"`
+	Files uint64 `protobuf:"varint,2,opt,name=files" json:"files,omitempty"`
+	Dirs  uint64 `protobuf:"varint,3,opt,name=dirs" json:"dirs,omitempty"`
 }
 
-func (m *GetDirectoryRequest) Reset()                    { *m = GetDirectoryRequest{} }
-func (m *GetDirectoryRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetDirectoryRequest) ProtoMessage()               {}
-func (*GetDirectoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (m *DirUsage) Reset()                    { *m = DirUsage{} }
+func (m *DirUsage) String() string            { return proto.CompactTextString(m) }
+func (*DirUsage) ProtoMessage()               {}
+func (*DirUsage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
-func (m *GetDirectoryRequest) GetGroup() uint64 {
+func (m *DirUsage) GetBytes() uint64 {
 	if m != nil {
-		return m.Group
+		return m.Bytes
 	}
 	return 0
 }
 
-func (m *GetDirectoryRequest) GetKey() []byte {
+func (m *DirUsage) GetFiles() uint64 {
 	if m != nil {
-		return m.Key
+		return m.Files
 	}
-	return nil
+	return 0
 }
 
-type BlockStashSuggestionRequest struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Num   uint32 `protobuf:"varint,2,opt,name=num" json:"num,omitempty"`
-}
-
-func (m *BlockStashSuggestionRequest) Reset()                    { *m = BlockStashSuggestionRequest{} }
-func (m *BlockStashSuggestionRequest) String() string            { return proto.CompactTextString(m) }
-func (*BlockStashSuggestionRequest) ProtoMessage()               {}
-func (*BlockStashSuggestionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
-
-func (m *BlockStashSuggestionRequest) GetGroup() uint64 {
-	if m != nil {
-		return m.Group
-	}
-	return 0
-}
-
-func (m *BlockStashSuggestionRequest) GetNum() uint32 {
+func (m *DirUsage) GetDirs() uint64 {
 	if m != nil {
-		return m.Num
+		return m.Dirs
 	}
 	return 0
 }
 
-type BlockStashSuggestion struct {
-	Nodes []*HostStash `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+type DeleteItemContract struct {
+	Dir        []byte `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	Key        []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Recursive  bool   `protobuf:"varint,3,opt,name=recursive" json:"recursive,omitempty"`
+	Permanent  bool   `protobuf:"varint,4,opt,name=permanent" json:"permanent,omitempty"`
+	ClientTime uint64 `protobuf:"varint,5,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
 }
 
-func (m *BlockStashSuggestion) Reset()                    { *m = BlockStashSuggestion{} }
-func (m *BlockStashSuggestion) String() string            { return proto.CompactTextString(m) }
-func (*BlockStashSuggestion) ProtoMessage()               {}
-func (*BlockStashSuggestion) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (m *DeleteItemContract) Reset()                    { *m = DeleteItemContract{} }
+func (m *DeleteItemContract) String() string            { return proto.CompactTextString(m) }
+func (*DeleteItemContract) ProtoMessage()               {}
+func (*DeleteItemContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
-func (m *BlockStashSuggestion) GetNodes() []*HostStash {
+func (m *DeleteItemContract) GetDir() []byte {
 	if m != nil {
-		return m.Nodes
+		return m.Dir
 	}
 	return nil
 }
 
-type WriteResult struct {
-	Succeed   bool   `protobuf:"varint,1,opt,name=succeed" json:"succeed,omitempty"`
-	Remains   uint64 `protobuf:"varint,2,opt,name=remains" json:"remains,omitempty"`
-	BlockHash []byte `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+func (m *DeleteItemContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
 }
 
-func (m *WriteResult) Reset()                    { *m = WriteResult{} }
-func (m *WriteResult) String() string            { return proto.CompactTextString(m) }
-func (*WriteResult) ProtoMessage()               {}
-func (*WriteResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (m *DeleteItemContract) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
 
-func (m *WriteResult) GetSucceed() bool {
+func (m *DeleteItemContract) GetPermanent() bool {
 	if m != nil {
-		return m.Succeed
+		return m.Permanent
 	}
 	return false
 }
 
-func (m *WriteResult) GetRemains() uint64 {
+func (m *DeleteItemContract) GetClientTime() uint64 {
 	if m != nil {
-		return m.Remains
+		return m.ClientTime
 	}
 	return 0
 }
 
-func (m *WriteResult) GetBlockHash() []byte {
+type DeletedFiles struct {
+	Files    []*FileMeta `protobuf:"bytes,1,rep,name=files" json:"files,omitempty"`
+	Released []*FileMeta `protobuf:"bytes,2,rep,name=released" json:"released,omitempty"`
+}
+
+func (m *DeletedFiles) Reset()                    { *m = DeletedFiles{} }
+func (m *DeletedFiles) String() string            { return proto.CompactTextString(m) }
+func (*DeletedFiles) ProtoMessage()               {}
+func (*DeletedFiles) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *DeletedFiles) GetFiles() []*FileMeta {
 	if m != nil {
-		return m.BlockHash
+		return m.Files
 	}
 	return nil
 }
 
-type NewDirectoryContract struct {
-	ParentDir []byte     `protobuf:"bytes,1,opt,name=parent_dir,json=parentDir,proto3" json:"parent_dir,omitempty"`
-	Dir       *Directory `protobuf:"bytes,3,opt,name=dir" json:"dir,omitempty"`
+func (m *DeletedFiles) GetReleased() []*FileMeta {
+	if m != nil {
+		return m.Released
+	}
+	return nil
 }
 
-func (m *NewDirectoryContract) Reset()                    { *m = NewDirectoryContract{} }
-func (m *NewDirectoryContract) String() string            { return proto.CompactTextString(m) }
-func (*NewDirectoryContract) ProtoMessage()               {}
-func (*NewDirectoryContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+type MoveItemContract struct {
+	SrcDir     []byte `protobuf:"bytes,1,opt,name=src_dir,json=srcDir,proto3" json:"src_dir,omitempty"`
+	Key        []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	DstDir     []byte `protobuf:"bytes,3,opt,name=dst_dir,json=dstDir,proto3" json:"dst_dir,omitempty"`
+	Name       string `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
+	ClientTime uint64 `protobuf:"varint,5,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
 
-func (m *NewDirectoryContract) GetParentDir() []byte {
+func (m *MoveItemContract) Reset()                    { *m = MoveItemContract{} }
+func (m *MoveItemContract) String() string            { return proto.CompactTextString(m) }
+func (*MoveItemContract) ProtoMessage()               {}
+func (*MoveItemContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *MoveItemContract) GetSrcDir() []byte {
 	if m != nil {
-		return m.ParentDir
+		return m.SrcDir
 	}
 	return nil
 }
 
-func (m *NewDirectoryContract) GetDir() *Directory {
+func (m *MoveItemContract) GetKey() []byte {
 	if m != nil {
-		return m.Dir
+		return m.Key
 	}
 	return nil
 }
 
-type AcquireFileWriteLockContract struct {
-	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+func (m *MoveItemContract) GetDstDir() []byte {
+	if m != nil {
+		return m.DstDir
+	}
+	return nil
 }
 
-func (m *AcquireFileWriteLockContract) Reset()                    { *m = AcquireFileWriteLockContract{} }
-func (m *AcquireFileWriteLockContract) String() string            { return proto.CompactTextString(m) }
-func (*AcquireFileWriteLockContract) ProtoMessage()               {}
-func (*AcquireFileWriteLockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (m *MoveItemContract) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
 
-func (m *AcquireFileWriteLockContract) GetKey() []byte {
+func (m *MoveItemContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type Snapshot struct {
+	Key       []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Volume    []byte `protobuf:"bytes,2,opt,name=volume,proto3" json:"volume,omitempty"`
+	Name      string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	RootDir   []byte `protobuf:"bytes,4,opt,name=root_dir,json=rootDir,proto3" json:"root_dir,omitempty"`
+	CreatedAt uint64 `protobuf:"varint,5,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *Snapshot) Reset()                    { *m = Snapshot{} }
+func (m *Snapshot) String() string            { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()               {}
+func (*Snapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *Snapshot) GetKey() []byte {
 	if m != nil {
 		return m.Key
 	}
 	return nil
 }
 
-type ReleaseFileWriteLockContract struct {
-	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+func (m *Snapshot) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
 }
 
-func (m *ReleaseFileWriteLockContract) Reset()                    { *m = ReleaseFileWriteLockContract{} }
-func (m *ReleaseFileWriteLockContract) String() string            { return proto.CompactTextString(m) }
-func (*ReleaseFileWriteLockContract) ProtoMessage()               {}
-func (*ReleaseFileWriteLockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (m *Snapshot) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
 
-func (m *ReleaseFileWriteLockContract) GetKey() []byte {
+func (m *Snapshot) GetRootDir() []byte {
 	if m != nil {
-		return m.Key
+		return m.RootDir
 	}
 	return nil
 }
 
-type TouchFileContract struct {
-	ClientTime uint64 `protobuf:"varint,1,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+func (m *Snapshot) GetCreatedAt() uint64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type SnapshotContract struct {
+	Volume     []byte `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
 	Name       string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
-	Dir        []byte `protobuf:"bytes,3,opt,name=dir,proto3" json:"dir,omitempty"`
-	Volume     []byte `protobuf:"bytes,4,opt,name=volume,proto3" json:"volume,omitempty"`
+	ClientTime uint64 `protobuf:"varint,3,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
 }
 
-func (m *TouchFileContract) Reset()                    { *m = TouchFileContract{} }
-func (m *TouchFileContract) String() string            { return proto.CompactTextString(m) }
-func (*TouchFileContract) ProtoMessage()               {}
-func (*TouchFileContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (m *SnapshotContract) Reset()                    { *m = SnapshotContract{} }
+func (m *SnapshotContract) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotContract) ProtoMessage()               {}
+func (*SnapshotContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
-func (m *TouchFileContract) GetClientTime() uint64 {
+func (m *SnapshotContract) GetVolume() []byte {
 	if m != nil {
-		return m.ClientTime
+		return m.Volume
 	}
-	return 0
+	return nil
 }
 
-func (m *TouchFileContract) GetName() string {
+func (m *SnapshotContract) GetName() string {
 	if m != nil {
 		return m.Name
 	}
 	return ""
 }
 
-func (m *TouchFileContract) GetDir() []byte {
+func (m *SnapshotContract) GetClientTime() uint64 {
 	if m != nil {
-		return m.Dir
+		return m.ClientTime
 	}
-	return nil
+	return 0
 }
 
-func (m *TouchFileContract) GetVolume() []byte {
+type MoveKeptBlockContract struct {
+	File  []byte `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Index uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Hash  []byte `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	From  uint64 `protobuf:"varint,4,opt,name=from" json:"from,omitempty"`
+	To    uint64 `protobuf:"varint,5,opt,name=to" json:"to,omitempty"`
+}
+
+func (m *MoveKeptBlockContract) Reset()                    { *m = MoveKeptBlockContract{} }
+func (m *MoveKeptBlockContract) String() string            { return proto.CompactTextString(m) }
+func (*MoveKeptBlockContract) ProtoMessage()               {}
+func (*MoveKeptBlockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *MoveKeptBlockContract) GetFile() []byte {
 	if m != nil {
-		return m.Volume
+		return m.File
 	}
 	return nil
 }
 
-type ConfirmBlockContract struct {
-	NodeId uint64              `protobuf:"varint,1,opt,name=node_id,json=nodeId" json:"node_id,omitempty"`
-	Index  uint64              `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
-	File   []byte              `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
-	Req    *CreateBlockRequest `protobuf:"bytes,4,opt,name=req" json:"req,omitempty"`
+func (m *MoveKeptBlockContract) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
 }
 
-func (m *ConfirmBlockContract) Reset()                    { *m = ConfirmBlockContract{} }
-func (m *ConfirmBlockContract) String() string            { return proto.CompactTextString(m) }
-func (*ConfirmBlockContract) ProtoMessage()               {}
-func (*ConfirmBlockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (m *MoveKeptBlockContract) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
 
-func (m *ConfirmBlockContract) GetNodeId() uint64 {
+func (m *MoveKeptBlockContract) GetFrom() uint64 {
 	if m != nil {
-		return m.NodeId
+		return m.From
 	}
 	return 0
 }
 
-func (m *ConfirmBlockContract) GetIndex() uint64 {
+func (m *MoveKeptBlockContract) GetTo() uint64 {
 	if m != nil {
-		return m.Index
+		return m.To
 	}
 	return 0
 }
 
-func (m *ConfirmBlockContract) GetFile() []byte {
+type DeleteSnapshotContract struct {
+	Volume []byte `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *DeleteSnapshotContract) Reset()                    { *m = DeleteSnapshotContract{} }
+func (m *DeleteSnapshotContract) String() string            { return proto.CompactTextString(m) }
+func (*DeleteSnapshotContract) ProtoMessage()               {}
+func (*DeleteSnapshotContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *DeleteSnapshotContract) GetVolume() []byte {
 	if m != nil {
-		return m.File
+		return m.Volume
 	}
 	return nil
 }
 
-func (m *ConfirmBlockContract) GetReq() *CreateBlockRequest {
+func (m *DeleteSnapshotContract) GetName() string {
 	if m != nil {
-		return m.Req
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-type CommitBlockContract struct {
-	Index      uint64   `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
-	ClientTime uint64   `protobuf:"varint,2,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
-	NodeIds    []uint64 `protobuf:"varint,3,rep,packed,name=node_ids,json=nodeIds" json:"node_ids,omitempty"`
-	File       []byte   `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+type DiffRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Volume    string `protobuf:"bytes,2,opt,name=volume" json:"volume,omitempty"`
+	From      string `protobuf:"bytes,3,opt,name=from" json:"from,omitempty"`
+	To        string `protobuf:"bytes,4,opt,name=to" json:"to,omitempty"`
+	ClientId  uint64 `protobuf:"varint,5,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
-func (m *CommitBlockContract) Reset()                    { *m = CommitBlockContract{} }
-func (m *CommitBlockContract) String() string            { return proto.CompactTextString(m) }
-func (*CommitBlockContract) ProtoMessage()               {}
-func (*CommitBlockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (m *DiffRequest) Reset()                    { *m = DiffRequest{} }
+func (m *DiffRequest) String() string            { return proto.CompactTextString(m) }
+func (*DiffRequest) ProtoMessage()               {}
+func (*DiffRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
-func (m *CommitBlockContract) GetIndex() uint64 {
+func (m *DiffRequest) GetGroup() uint64 {
 	if m != nil {
-		return m.Index
+		return m.Group
 	}
 	return 0
 }
 
-func (m *CommitBlockContract) GetClientTime() uint64 {
+func (m *DiffRequest) GetVolume() string {
 	if m != nil {
-		return m.ClientTime
+		return m.Volume
+	}
+	return ""
+}
+
+func (m *DiffRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *DiffRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *DiffRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
 	}
 	return 0
 }
 
-func (m *CommitBlockContract) GetNodeIds() []uint64 {
+func (m *DiffRequest) GetSignature() []byte {
 	if m != nil {
-		return m.NodeIds
+		return m.Signature
 	}
 	return nil
 }
 
-func (m *CommitBlockContract) GetFile() []byte {
+type DiffEntry struct {
+	Change  DiffEntry_Change `protobuf:"varint,1,opt,name=change,enum=client.DiffEntry_Change" json:"change,omitempty"`
+	Path    string           `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	OldPath string           `protobuf:"bytes,3,opt,name=old_path,json=oldPath" json:"old_path,omitempty"`
+	Dir     bool             `protobuf:"varint,4,opt,name=dir" json:"dir,omitempty"`
+	File    *FileMeta        `protobuf:"bytes,5,opt,name=file" json:"file,omitempty"`
+	Blocks  []uint64         `protobuf:"varint,6,rep,packed,name=blocks" json:"blocks,omitempty"`
+	Mode    uint32           `protobuf:"varint,7,opt,name=mode" json:"mode,omitempty"`
+}
+
+func (m *DiffEntry) Reset()                    { *m = DiffEntry{} }
+func (m *DiffEntry) String() string            { return proto.CompactTextString(m) }
+func (*DiffEntry) ProtoMessage()               {}
+func (*DiffEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *DiffEntry) GetChange() DiffEntry_Change {
 	if m != nil {
-		return m.File
+		return m.Change
 	}
-	return nil
+	return DiffEntry_ADDED
 }
 
-type FileWriteLock struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Owner uint64 `protobuf:"varint,2,opt,name=owner" json:"owner,omitempty"`
-	Key   []byte `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+func (m *DiffEntry) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
 }
 
-func (m *FileWriteLock) Reset()                    { *m = FileWriteLock{} }
-func (m *FileWriteLock) String() string            { return proto.CompactTextString(m) }
-func (*FileWriteLock) ProtoMessage()               {}
-func (*FileWriteLock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (m *DiffEntry) GetOldPath() string {
+	if m != nil {
+		return m.OldPath
+	}
+	return ""
+}
 
-func (m *FileWriteLock) GetGroup() uint64 {
+func (m *DiffEntry) GetDir() bool {
 	if m != nil {
-		return m.Group
+		return m.Dir
 	}
-	return 0
+	return false
 }
 
-func (m *FileWriteLock) GetOwner() uint64 {
+func (m *DiffEntry) GetFile() *FileMeta {
 	if m != nil {
-		return m.Owner
+		return m.File
+	}
+	return nil
+}
+
+func (m *DiffEntry) GetBlocks() []uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+func (m *DiffEntry) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
 	}
 	return 0
 }
 
-func (m *FileWriteLock) GetKey() []byte {
+type DiffResponse struct {
+	Entries []*DiffEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *DiffResponse) Reset()                    { *m = DiffResponse{} }
+func (m *DiffResponse) String() string            { return proto.CompactTextString(m) }
+func (*DiffResponse) ProtoMessage()               {}
+func (*DiffResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *DiffResponse) GetEntries() []*DiffEntry {
 	if m != nil {
-		return m.Key
+		return m.Entries
 	}
 	return nil
 }
 
-type DirectoryItem struct {
-	Type DirectoryItem_ItemType `protobuf:"varint,1,opt,name=type,enum=client.DirectoryItem_ItemType" json:"type,omitempty"`
-	File *FileMeta              `protobuf:"bytes,2,opt,name=file" json:"file,omitempty"`
-	Dir  *Directory             `protobuf:"bytes,3,opt,name=dir" json:"dir,omitempty"`
+type ExportHeader struct {
+	Volume    string `protobuf:"bytes,1,opt,name=volume" json:"volume,omitempty"`
+	From      string `protobuf:"bytes,2,opt,name=from" json:"from,omitempty"`
+	To        string `protobuf:"bytes,3,opt,name=to" json:"to,omitempty"`
+	CreatedAt uint64 `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
 }
 
-func (m *DirectoryItem) Reset()                    { *m = DirectoryItem{} }
-func (m *DirectoryItem) String() string            { return proto.CompactTextString(m) }
-func (*DirectoryItem) ProtoMessage()               {}
-func (*DirectoryItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (m *ExportHeader) Reset()                    { *m = ExportHeader{} }
+func (m *ExportHeader) String() string            { return proto.CompactTextString(m) }
+func (*ExportHeader) ProtoMessage()               {}
+func (*ExportHeader) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
-func (m *DirectoryItem) GetType() DirectoryItem_ItemType {
+func (m *ExportHeader) GetVolume() string {
 	if m != nil {
-		return m.Type
+		return m.Volume
 	}
-	return DirectoryItem_FILE
+	return ""
 }
 
-func (m *DirectoryItem) GetFile() *FileMeta {
+func (m *ExportHeader) GetFrom() string {
 	if m != nil {
-		return m.File
+		return m.From
 	}
-	return nil
+	return ""
 }
 
-func (m *DirectoryItem) GetDir() *Directory {
+func (m *ExportHeader) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *ExportHeader) GetCreatedAt() uint64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type ExportRecord struct {
+	Header *ExportHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Entry  *DiffEntry    `protobuf:"bytes,2,opt,name=entry" json:"entry,omitempty"`
+	Offset uint64        `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
+	Data   []byte        `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *ExportRecord) Reset()                    { *m = ExportRecord{} }
+func (m *ExportRecord) String() string            { return proto.CompactTextString(m) }
+func (*ExportRecord) ProtoMessage()               {}
+func (*ExportRecord) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *ExportRecord) GetHeader() *ExportHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ExportRecord) GetEntry() *DiffEntry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *ExportRecord) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ExportRecord) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SetQuotaContract struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Quota *Quota `protobuf:"bytes,2,opt,name=quota" json:"quota,omitempty"`
+}
+
+func (m *SetQuotaContract) Reset()                    { *m = SetQuotaContract{} }
+func (m *SetQuotaContract) String() string            { return proto.CompactTextString(m) }
+func (*SetQuotaContract) ProtoMessage()               {}
+func (*SetQuotaContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *SetQuotaContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *SetQuotaContract) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+type StoragePolicy struct {
+	Replications uint32   `protobuf:"varint,1,opt,name=replications" json:"replications,omitempty"`
+	BlockSize    uint32   `protobuf:"varint,2,opt,name=block_size,json=blockSize" json:"block_size,omitempty"`
+	Zones        []string `protobuf:"bytes,3,rep,name=zones" json:"zones,omitempty"`
+}
+
+func (m *StoragePolicy) Reset()                    { *m = StoragePolicy{} }
+func (m *StoragePolicy) String() string            { return proto.CompactTextString(m) }
+func (*StoragePolicy) ProtoMessage()               {}
+func (*StoragePolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *StoragePolicy) GetReplications() uint32 {
+	if m != nil {
+		return m.Replications
+	}
+	return 0
+}
+
+func (m *StoragePolicy) GetBlockSize() uint32 {
+	if m != nil {
+		return m.BlockSize
+	}
+	return 0
+}
+
+func (m *StoragePolicy) GetZones() []string {
+	if m != nil {
+		return m.Zones
+	}
+	return nil
+}
+
+type SetPolicyContract struct {
+	Key    []byte         `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Policy *StoragePolicy `protobuf:"bytes,2,opt,name=policy" json:"policy,omitempty"`
+}
+
+func (m *SetPolicyContract) Reset()                    { *m = SetPolicyContract{} }
+func (m *SetPolicyContract) String() string            { return proto.CompactTextString(m) }
+func (*SetPolicyContract) ProtoMessage()               {}
+func (*SetPolicyContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *SetPolicyContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *SetPolicyContract) GetPolicy() *StoragePolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+type Volume struct {
+	Name         string            `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Key          []byte            `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Replications uint32            `protobuf:"varint,3,opt,name=replications" json:"replications,omitempty"`
+	BlockSize    uint32            `protobuf:"varint,4,opt,name=block_size,json=blockSize" json:"block_size,omitempty"`
+	RootDir      []byte            `protobuf:"bytes,5,opt,name=root_dir,json=rootDir,proto3" json:"root_dir,omitempty"`
+	Acl          []*AclEntry       `protobuf:"bytes,6,rep,name=acl" json:"acl,omitempty"`
+	Owner        uint64            `protobuf:"varint,7,opt,name=owner" json:"owner,omitempty"`
+	Versioning   *VersionRetention `protobuf:"bytes,8,opt,name=versioning" json:"versioning,omitempty"`
+	Trash        *TrashRetention   `protobuf:"bytes,9,opt,name=trash" json:"trash,omitempty"`
+}
+
+func (m *Volume) Reset()                    { *m = Volume{} }
+func (m *Volume) String() string            { return proto.CompactTextString(m) }
+func (*Volume) ProtoMessage()               {}
+func (*Volume) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *Volume) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Volume) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Volume) GetReplications() uint32 {
+	if m != nil {
+		return m.Replications
+	}
+	return 0
+}
+
+func (m *Volume) GetBlockSize() uint32 {
+	if m != nil {
+		return m.BlockSize
+	}
+	return 0
+}
+
+func (m *Volume) GetRootDir() []byte {
+	if m != nil {
+		return m.RootDir
+	}
+	return nil
+}
+
+func (m *Volume) GetAcl() []*AclEntry {
+	if m != nil {
+		return m.Acl
+	}
+	return nil
+}
+
+func (m *Volume) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+func (m *Volume) GetVersioning() *VersionRetention {
+	if m != nil {
+		return m.Versioning
+	}
+	return nil
+}
+
+func (m *Volume) GetTrash() *TrashRetention {
+	if m != nil {
+		return m.Trash
+	}
+	return nil
+}
+
+type TrashRetention struct {
+	Enabled   bool   `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
+	Retention uint64 `protobuf:"varint,2,opt,name=retention" json:"retention,omitempty"`
+}
+
+func (m *TrashRetention) Reset()                    { *m = TrashRetention{} }
+func (m *TrashRetention) String() string            { return proto.CompactTextString(m) }
+func (*TrashRetention) ProtoMessage()               {}
+func (*TrashRetention) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *TrashRetention) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *TrashRetention) GetRetention() uint64 {
+	if m != nil {
+		return m.Retention
+	}
+	return 0
+}
+
+type TrashEntry struct {
+	Key       []byte    `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Volume    []byte    `protobuf:"bytes,2,opt,name=volume,proto3" json:"volume,omitempty"`
+	Dir       []byte    `protobuf:"bytes,3,opt,name=dir,proto3" json:"dir,omitempty"`
+	Path      string    `protobuf:"bytes,4,opt,name=path" json:"path,omitempty"`
+	IsDir     bool      `protobuf:"varint,5,opt,name=is_dir,json=isDir" json:"is_dir,omitempty"`
+	DeletedAt uint64    `protobuf:"varint,6,opt,name=deleted_at,json=deletedAt" json:"deleted_at,omitempty"`
+	DeletedBy uint64    `protobuf:"varint,7,opt,name=deleted_by,json=deletedBy" json:"deleted_by,omitempty"`
+	Usage     *DirUsage `protobuf:"bytes,8,opt,name=usage" json:"usage,omitempty"`
+}
+
+func (m *TrashEntry) Reset()                    { *m = TrashEntry{} }
+func (m *TrashEntry) String() string            { return proto.CompactTextString(m) }
+func (*TrashEntry) ProtoMessage()               {}
+func (*TrashEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *TrashEntry) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *TrashEntry) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *TrashEntry) GetDir() []byte {
+	if m != nil {
+		return m.Dir
+	}
+	return nil
+}
+
+func (m *TrashEntry) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TrashEntry) GetIsDir() bool {
+	if m != nil {
+		return m.IsDir
+	}
+	return false
+}
+
+func (m *TrashEntry) GetDeletedAt() uint64 {
+	if m != nil {
+		return m.DeletedAt
+	}
+	return 0
+}
+
+func (m *TrashEntry) GetDeletedBy() uint64 {
+	if m != nil {
+		return m.DeletedBy
+	}
+	return 0
+}
+
+func (m *TrashEntry) GetUsage() *DirUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+type RestoreTrashContract struct {
+	Volume     []byte `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	Key        []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	ClientTime uint64 `protobuf:"varint,3,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *RestoreTrashContract) Reset()                    { *m = RestoreTrashContract{} }
+func (m *RestoreTrashContract) String() string            { return proto.CompactTextString(m) }
+func (*RestoreTrashContract) ProtoMessage()               {}
+func (*RestoreTrashContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *RestoreTrashContract) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *RestoreTrashContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *RestoreTrashContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type PurgeTrashContract struct {
+	Volume []byte `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	Key    []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *PurgeTrashContract) Reset()                    { *m = PurgeTrashContract{} }
+func (m *PurgeTrashContract) String() string            { return proto.CompactTextString(m) }
+func (*PurgeTrashContract) ProtoMessage()               {}
+func (*PurgeTrashContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *PurgeTrashContract) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *PurgeTrashContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type ListTrashRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Volume    []byte `protobuf:"bytes,2,opt,name=volume,proto3" json:"volume,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *ListTrashRequest) Reset()                    { *m = ListTrashRequest{} }
+func (m *ListTrashRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListTrashRequest) ProtoMessage()               {}
+func (*ListTrashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *ListTrashRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *ListTrashRequest) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *ListTrashRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ListTrashRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ListTrashResponse struct {
+	Entries []*TrashEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *ListTrashResponse) Reset()                    { *m = ListTrashResponse{} }
+func (m *ListTrashResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListTrashResponse) ProtoMessage()               {}
+func (*ListTrashResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *ListTrashResponse) GetEntries() []*TrashEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type VersionRetention struct {
+	Enabled bool   `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
+	Keep    uint32 `protobuf:"varint,2,opt,name=keep" json:"keep,omitempty"`
+	MaxAge  uint64 `protobuf:"varint,3,opt,name=max_age,json=maxAge" json:"max_age,omitempty"`
+}
+
+func (m *VersionRetention) Reset()                    { *m = VersionRetention{} }
+func (m *VersionRetention) String() string            { return proto.CompactTextString(m) }
+func (*VersionRetention) ProtoMessage()               {}
+func (*VersionRetention) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *VersionRetention) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *VersionRetention) GetKeep() uint32 {
+	if m != nil {
+		return m.Keep
+	}
+	return 0
+}
+
+func (m *VersionRetention) GetMaxAge() uint64 {
+	if m != nil {
+		return m.MaxAge
+	}
+	return 0
+}
+
+type FileVersion struct {
+	File      []byte    `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Number    uint64    `protobuf:"varint,2,opt,name=number" json:"number,omitempty"`
+	Meta      *FileMeta `protobuf:"bytes,3,opt,name=meta" json:"meta,omitempty"`
+	CreatedAt uint64    `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *FileVersion) Reset()                    { *m = FileVersion{} }
+func (m *FileVersion) String() string            { return proto.CompactTextString(m) }
+func (*FileVersion) ProtoMessage()               {}
+func (*FileVersion) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *FileVersion) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *FileVersion) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *FileVersion) GetMeta() *FileMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *FileVersion) GetCreatedAt() uint64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type CommitVersionContract struct {
+	File       []byte `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	ClientTime uint64 `protobuf:"varint,2,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *CommitVersionContract) Reset()                    { *m = CommitVersionContract{} }
+func (m *CommitVersionContract) String() string            { return proto.CompactTextString(m) }
+func (*CommitVersionContract) ProtoMessage()               {}
+func (*CommitVersionContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *CommitVersionContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *CommitVersionContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type RestoreVersionContract struct {
+	File       []byte `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Number     uint64 `protobuf:"varint,2,opt,name=number" json:"number,omitempty"`
+	ClientTime uint64 `protobuf:"varint,3,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *RestoreVersionContract) Reset()                    { *m = RestoreVersionContract{} }
+func (m *RestoreVersionContract) String() string            { return proto.CompactTextString(m) }
+func (*RestoreVersionContract) ProtoMessage()               {}
+func (*RestoreVersionContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *RestoreVersionContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *RestoreVersionContract) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *RestoreVersionContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type ListVersionsRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	File      []byte `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *ListVersionsRequest) Reset()                    { *m = ListVersionsRequest{} }
+func (m *ListVersionsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListVersionsRequest) ProtoMessage()               {}
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *ListVersionsRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *ListVersionsRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *ListVersionsRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ListVersionsRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ListVersionsResponse struct {
+	Versions []*FileVersion `protobuf:"bytes,1,rep,name=versions" json:"versions,omitempty"`
+}
+
+func (m *ListVersionsResponse) Reset()                    { *m = ListVersionsResponse{} }
+func (m *ListVersionsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListVersionsResponse) ProtoMessage()               {}
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *ListVersionsResponse) GetVersions() []*FileVersion {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+type AclEntry struct {
+	ClientId    uint64 `protobuf:"varint,1,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Group       uint64 `protobuf:"varint,2,opt,name=group" json:"group,omitempty"`
+	Permissions uint32 `protobuf:"varint,3,opt,name=permissions" json:"permissions,omitempty"`
+}
+
+func (m *AclEntry) Reset()                    { *m = AclEntry{} }
+func (m *AclEntry) String() string            { return proto.CompactTextString(m) }
+func (*AclEntry) ProtoMessage()               {}
+func (*AclEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *AclEntry) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *AclEntry) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *AclEntry) GetPermissions() uint32 {
+	if m != nil {
+		return m.Permissions
+	}
+	return 0
+}
+
+type HostStash struct {
+	HostId   uint64     `protobuf:"varint,1,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
+	Capacity uint64     `protobuf:"varint,2,opt,name=capacity" json:"capacity,omitempty"`
+	Used     uint64     `protobuf:"varint,3,opt,name=used" json:"used,omitempty"`
+	Owner    uint64     `protobuf:"varint,4,opt,name=owner" json:"owner,omitempty"`
+	Zone     string     `protobuf:"bytes,5,opt,name=zone" json:"zone,omitempty"`
+	Rack     string     `protobuf:"bytes,6,opt,name=rack" json:"rack,omitempty"`
+	Host     string     `protobuf:"bytes,7,opt,name=host" json:"host,omitempty"`
+	LastSeen uint64     `protobuf:"varint,8,opt,name=last_seen,json=lastSeen" json:"last_seen,omitempty"`
+	State    StashState `protobuf:"varint,9,opt,name=state,enum=client.StashState" json:"state,omitempty"`
+	Address  string     `protobuf:"bytes,10,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *HostStash) Reset()                    { *m = HostStash{} }
+func (m *HostStash) String() string            { return proto.CompactTextString(m) }
+func (*HostStash) ProtoMessage()               {}
+func (*HostStash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *HostStash) GetHostId() uint64 {
+	if m != nil {
+		return m.HostId
+	}
+	return 0
+}
+
+func (m *HostStash) GetCapacity() uint64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *HostStash) GetUsed() uint64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+func (m *HostStash) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+func (m *HostStash) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+func (m *HostStash) GetRack() string {
+	if m != nil {
+		return m.Rack
+	}
+	return ""
+}
+
+func (m *HostStash) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *HostStash) GetLastSeen() uint64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+func (m *HostStash) GetState() StashState {
+	if m != nil {
+		return m.State
+	}
+	return StashState_ONLINE
+}
+
+func (m *HostStash) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type OpenRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Key  []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *OpenRequest) Reset()                    { *m = OpenRequest{} }
+func (m *OpenRequest) String() string            { return proto.CompactTextString(m) }
+func (*OpenRequest) ProtoMessage()               {}
+func (*OpenRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *OpenRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *OpenRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type GetBlockRequest struct {
+	Group     uint64                 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Index     uint64                 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	File      []byte                 `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	ClientId  uint64                 `protobuf:"varint,4,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Hash      []byte                 `protobuf:"bytes,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	Snapshot  []byte                 `protobuf:"bytes,7,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Version   uint64                 `protobuf:"varint,8,opt,name=version" json:"version,omitempty"`
+	Replicate *ReplicateBlockRequest `protobuf:"bytes,9,opt,name=replicate" json:"replicate,omitempty"`
+}
+
+func (m *GetBlockRequest) Reset()                    { *m = GetBlockRequest{} }
+func (m *GetBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetBlockRequest) ProtoMessage()               {}
+func (*GetBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *GetBlockRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *GetBlockRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *GetBlockRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *GetBlockRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *GetBlockRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *GetBlockRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *GetBlockRequest) GetSnapshot() []byte {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func (m *GetBlockRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetBlockRequest) GetReplicate() *ReplicateBlockRequest {
+	if m != nil {
+		return m.Replicate
+	}
+	return nil
+}
+
+type AppendToBlockRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Index     uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Offset    uint32 `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
+	File      []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Data      []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	ClientId  uint64 `protobuf:"varint,6,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *AppendToBlockRequest) Reset()                    { *m = AppendToBlockRequest{} }
+func (m *AppendToBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*AppendToBlockRequest) ProtoMessage()               {}
+func (*AppendToBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *AppendToBlockRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *AppendToBlockRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *AppendToBlockRequest) GetOffset() uint32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *AppendToBlockRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *AppendToBlockRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *AppendToBlockRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *AppendToBlockRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type DeleteBlockRequest struct {
+	Key       []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Group     uint64 `protobuf:"varint,2,opt,name=group" json:"group,omitempty"`
+	Index     uint64 `protobuf:"varint,3,opt,name=index" json:"index,omitempty"`
+	File      []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Hash      []byte `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+	ClientId  uint64 `protobuf:"varint,6,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *DeleteBlockRequest) Reset()                    { *m = DeleteBlockRequest{} }
+func (m *DeleteBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteBlockRequest) ProtoMessage()               {}
+func (*DeleteBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *DeleteBlockRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *DeleteBlockRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *DeleteBlockRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *DeleteBlockRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *DeleteBlockRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *DeleteBlockRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *DeleteBlockRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type CreateBlockRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Index     uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	File      []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	ClientId  uint64 `protobuf:"varint,6,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+}
+
+func (m *CreateBlockRequest) Reset()                    { *m = CreateBlockRequest{} }
+func (m *CreateBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateBlockRequest) ProtoMessage()               {}
+func (*CreateBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *CreateBlockRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *CreateBlockRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *CreateBlockRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *CreateBlockRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *CreateBlockRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+type GetFileRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	File      []byte `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *GetFileRequest) Reset()                    { *m = GetFileRequest{} }
+func (m *GetFileRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetFileRequest) ProtoMessage()               {}
+func (*GetFileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *GetFileRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *GetFileRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *GetFileRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *GetFileRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type GetVolumeRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *GetVolumeRequest) Reset()                    { *m = GetVolumeRequest{} }
+func (m *GetVolumeRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetVolumeRequest) ProtoMessage()               {}
+func (*GetVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *GetVolumeRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *GetVolumeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetVolumeRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *GetVolumeRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ListVolumesRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	ClientId  uint64 `protobuf:"varint,2,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *ListVolumesRequest) Reset()                    { *m = ListVolumesRequest{} }
+func (m *ListVolumesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListVolumesRequest) ProtoMessage()               {}
+func (*ListVolumesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *ListVolumesRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *ListVolumesRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ListVolumesRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ListVolumesResponse struct {
+	Volumes []*Volume `protobuf:"bytes,1,rep,name=volumes" json:"volumes,omitempty"`
+}
+
+func (m *ListVolumesResponse) Reset()                    { *m = ListVolumesResponse{} }
+func (m *ListVolumesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListVolumesResponse) ProtoMessage()               {}
+func (*ListVolumesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *ListVolumesResponse) GetVolumes() []*Volume {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+type VolumeUsage struct {
+	Volume *Volume         `protobuf:"bytes,1,opt,name=volume" json:"volume,omitempty"`
+	Files  uint64          `protobuf:"varint,2,opt,name=files" json:"files,omitempty"`
+	Size   uint64          `protobuf:"varint,4,opt,name=size" json:"size,omitempty"`
+	Job    *ReplicationJob `protobuf:"bytes,6,opt,name=job" json:"job,omitempty"`
+	Dirs   uint64          `protobuf:"varint,7,opt,name=dirs" json:"dirs,omitempty"`
+}
+
+func (m *VolumeUsage) Reset()                    { *m = VolumeUsage{} }
+func (m *VolumeUsage) String() string            { return proto.CompactTextString(m) }
+func (*VolumeUsage) ProtoMessage()               {}
+func (*VolumeUsage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *VolumeUsage) GetVolume() *Volume {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *VolumeUsage) GetFiles() uint64 {
+	if m != nil {
+		return m.Files
+	}
+	return 0
+}
+
+func (m *VolumeUsage) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *VolumeUsage) GetJob() *ReplicationJob {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+func (m *VolumeUsage) GetDirs() uint64 {
+	if m != nil {
+		return m.Dirs
+	}
+	return 0
+}
+
+type ReplicationJob struct {
+	Volume       []byte `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	Replications uint32 `protobuf:"varint,2,opt,name=replications" json:"replications,omitempty"`
+	Cursor       []byte `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Processed    uint64 `protobuf:"varint,4,opt,name=processed" json:"processed,omitempty"`
+	Total        uint64 `protobuf:"varint,5,opt,name=total" json:"total,omitempty"`
+	Done         bool   `protobuf:"varint,6,opt,name=done" json:"done,omitempty"`
+}
+
+func (m *ReplicationJob) Reset()                    { *m = ReplicationJob{} }
+func (m *ReplicationJob) String() string            { return proto.CompactTextString(m) }
+func (*ReplicationJob) ProtoMessage()               {}
+func (*ReplicationJob) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *ReplicationJob) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *ReplicationJob) GetReplications() uint32 {
+	if m != nil {
+		return m.Replications
+	}
+	return 0
+}
+
+func (m *ReplicationJob) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
+func (m *ReplicationJob) GetProcessed() uint64 {
+	if m != nil {
+		return m.Processed
+	}
+	return 0
+}
+
+func (m *ReplicationJob) GetTotal() uint64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *ReplicationJob) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+type UpdateVolumeContract struct {
+	Key          []byte            `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Replications uint32            `protobuf:"varint,2,opt,name=replications" json:"replications,omitempty"`
+	BlockSize    uint32            `protobuf:"varint,3,opt,name=block_size,json=blockSize" json:"block_size,omitempty"`
+	Versioning   *VersionRetention `protobuf:"bytes,4,opt,name=versioning" json:"versioning,omitempty"`
+	Trash        *TrashRetention   `protobuf:"bytes,5,opt,name=trash" json:"trash,omitempty"`
+}
+
+func (m *UpdateVolumeContract) Reset()                    { *m = UpdateVolumeContract{} }
+func (m *UpdateVolumeContract) String() string            { return proto.CompactTextString(m) }
+func (*UpdateVolumeContract) ProtoMessage()               {}
+func (*UpdateVolumeContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *UpdateVolumeContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *UpdateVolumeContract) GetReplications() uint32 {
+	if m != nil {
+		return m.Replications
+	}
+	return 0
+}
+
+func (m *UpdateVolumeContract) GetBlockSize() uint32 {
+	if m != nil {
+		return m.BlockSize
+	}
+	return 0
+}
+
+func (m *UpdateVolumeContract) GetVersioning() *VersionRetention {
+	if m != nil {
+		return m.Versioning
+	}
+	return nil
+}
+
+func (m *UpdateVolumeContract) GetTrash() *TrashRetention {
+	if m != nil {
+		return m.Trash
+	}
+	return nil
+}
+
+type DeleteVolumeContract struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *DeleteVolumeContract) Reset()                    { *m = DeleteVolumeContract{} }
+func (m *DeleteVolumeContract) String() string            { return proto.CompactTextString(m) }
+func (*DeleteVolumeContract) ProtoMessage()               {}
+func (*DeleteVolumeContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *DeleteVolumeContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type PrincipalList struct {
+	Ids []uint64 `protobuf:"varint,1,rep,packed,name=ids" json:"ids,omitempty"`
+}
+
+func (m *PrincipalList) Reset()                    { *m = PrincipalList{} }
+func (m *PrincipalList) String() string            { return proto.CompactTextString(m) }
+func (*PrincipalList) ProtoMessage()               {}
+func (*PrincipalList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *PrincipalList) GetIds() []uint64 {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type GetDirectoryRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Key       []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *GetDirectoryRequest) Reset()                    { *m = GetDirectoryRequest{} }
+func (m *GetDirectoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetDirectoryRequest) ProtoMessage()               {}
+func (*GetDirectoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *GetDirectoryRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *GetDirectoryRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *GetDirectoryRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *GetDirectoryRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type BlockStashSuggestionRequest struct {
+	Group    uint64   `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Num      uint32   `protobuf:"varint,2,opt,name=num" json:"num,omitempty"`
+	Existing []uint64 `protobuf:"varint,3,rep,packed,name=existing" json:"existing,omitempty"`
+	Zones    []string `protobuf:"bytes,4,rep,name=zones" json:"zones,omitempty"`
+}
+
+func (m *BlockStashSuggestionRequest) Reset()                    { *m = BlockStashSuggestionRequest{} }
+func (m *BlockStashSuggestionRequest) String() string            { return proto.CompactTextString(m) }
+func (*BlockStashSuggestionRequest) ProtoMessage()               {}
+func (*BlockStashSuggestionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *BlockStashSuggestionRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *BlockStashSuggestionRequest) GetNum() uint32 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *BlockStashSuggestionRequest) GetExisting() []uint64 {
+	if m != nil {
+		return m.Existing
+	}
+	return nil
+}
+
+func (m *BlockStashSuggestionRequest) GetZones() []string {
+	if m != nil {
+		return m.Zones
+	}
+	return nil
+}
+
+type BlockStashSuggestion struct {
+	Nodes []*HostStash `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+}
+
+func (m *BlockStashSuggestion) Reset()                    { *m = BlockStashSuggestion{} }
+func (m *BlockStashSuggestion) String() string            { return proto.CompactTextString(m) }
+func (*BlockStashSuggestion) ProtoMessage()               {}
+func (*BlockStashSuggestion) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *BlockStashSuggestion) GetNodes() []*HostStash {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type ReplicateBlockRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Index     uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	File      []byte `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	Source    uint64 `protobuf:"varint,4,opt,name=source" json:"source,omitempty"`
+	Hash      []byte `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+	ClientId  uint64 `protobuf:"varint,6,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	Kept      bool   `protobuf:"varint,8,opt,name=kept" json:"kept,omitempty"`
+}
+
+func (m *ReplicateBlockRequest) Reset()                    { *m = ReplicateBlockRequest{} }
+func (m *ReplicateBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReplicateBlockRequest) ProtoMessage()               {}
+func (*ReplicateBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *ReplicateBlockRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *ReplicateBlockRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ReplicateBlockRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *ReplicateBlockRequest) GetSource() uint64 {
+	if m != nil {
+		return m.Source
+	}
+	return 0
+}
+
+func (m *ReplicateBlockRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *ReplicateBlockRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ReplicateBlockRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ReplicateBlockRequest) GetKept() bool {
+	if m != nil {
+		return m.Kept
+	}
+	return false
+}
+
+type WriteResult struct {
+	Succeed   bool   `protobuf:"varint,1,opt,name=succeed" json:"succeed,omitempty"`
+	Remains   uint64 `protobuf:"varint,2,opt,name=remains" json:"remains,omitempty"`
+	BlockHash []byte `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+}
+
+func (m *WriteResult) Reset()                    { *m = WriteResult{} }
+func (m *WriteResult) String() string            { return proto.CompactTextString(m) }
+func (*WriteResult) ProtoMessage()               {}
+func (*WriteResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *WriteResult) GetSucceed() bool {
+	if m != nil {
+		return m.Succeed
+	}
+	return false
+}
+
+func (m *WriteResult) GetRemains() uint64 {
+	if m != nil {
+		return m.Remains
+	}
+	return 0
+}
+
+func (m *WriteResult) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+type NewDirectoryContract struct {
+	ParentDir  []byte     `protobuf:"bytes,1,opt,name=parent_dir,json=parentDir,proto3" json:"parent_dir,omitempty"`
+	Dir        *Directory `protobuf:"bytes,3,opt,name=dir" json:"dir,omitempty"`
+	ClientTime uint64     `protobuf:"varint,4,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *NewDirectoryContract) Reset()                    { *m = NewDirectoryContract{} }
+func (m *NewDirectoryContract) String() string            { return proto.CompactTextString(m) }
+func (*NewDirectoryContract) ProtoMessage()               {}
+func (*NewDirectoryContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+func (m *NewDirectoryContract) GetParentDir() []byte {
+	if m != nil {
+		return m.ParentDir
+	}
+	return nil
+}
+
+func (m *NewDirectoryContract) GetDir() *Directory {
+	if m != nil {
+		return m.Dir
+	}
+	return nil
+}
+
+func (m *NewDirectoryContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type AcquireFileWriteLockContract struct {
+	Key        []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ClientTime uint64 `protobuf:"varint,2,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *AcquireFileWriteLockContract) Reset()                    { *m = AcquireFileWriteLockContract{} }
+func (m *AcquireFileWriteLockContract) String() string            { return proto.CompactTextString(m) }
+func (*AcquireFileWriteLockContract) ProtoMessage()               {}
+func (*AcquireFileWriteLockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *AcquireFileWriteLockContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *AcquireFileWriteLockContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type ReleaseFileWriteLockContract struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *ReleaseFileWriteLockContract) Reset()                    { *m = ReleaseFileWriteLockContract{} }
+func (m *ReleaseFileWriteLockContract) String() string            { return proto.CompactTextString(m) }
+func (*ReleaseFileWriteLockContract) ProtoMessage()               {}
+func (*ReleaseFileWriteLockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *ReleaseFileWriteLockContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type TouchFileContract struct {
+	ClientTime uint64         `protobuf:"varint,1,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+	Name       string         `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Dir        []byte         `protobuf:"bytes,3,opt,name=dir,proto3" json:"dir,omitempty"`
+	Volume     []byte         `protobuf:"bytes,4,opt,name=volume,proto3" json:"volume,omitempty"`
+	Mode       uint32         `protobuf:"varint,5,opt,name=mode" json:"mode,omitempty"`
+	Policy     *StoragePolicy `protobuf:"bytes,6,opt,name=policy" json:"policy,omitempty"`
+}
+
+func (m *TouchFileContract) Reset()                    { *m = TouchFileContract{} }
+func (m *TouchFileContract) String() string            { return proto.CompactTextString(m) }
+func (*TouchFileContract) ProtoMessage()               {}
+func (*TouchFileContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *TouchFileContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+func (m *TouchFileContract) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TouchFileContract) GetDir() []byte {
+	if m != nil {
+		return m.Dir
+	}
+	return nil
+}
+
+func (m *TouchFileContract) GetVolume() []byte {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *TouchFileContract) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *TouchFileContract) GetPolicy() *StoragePolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+type ConfirmBlockContract struct {
+	NodeId uint64              `protobuf:"varint,1,opt,name=node_id,json=nodeId" json:"node_id,omitempty"`
+	Index  uint64              `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	File   []byte              `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	Req    *CreateBlockRequest `protobuf:"bytes,4,opt,name=req" json:"req,omitempty"`
+}
+
+func (m *ConfirmBlockContract) Reset()                    { *m = ConfirmBlockContract{} }
+func (m *ConfirmBlockContract) String() string            { return proto.CompactTextString(m) }
+func (*ConfirmBlockContract) ProtoMessage()               {}
+func (*ConfirmBlockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *ConfirmBlockContract) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *ConfirmBlockContract) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ConfirmBlockContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *ConfirmBlockContract) GetReq() *CreateBlockRequest {
+	if m != nil {
+		return m.Req
+	}
+	return nil
+}
+
+type CommitBlockContract struct {
+	Index      uint64   `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	ClientTime uint64   `protobuf:"varint,2,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+	NodeIds    []uint64 `protobuf:"varint,3,rep,packed,name=node_ids,json=nodeIds" json:"node_ids,omitempty"`
+	File       []byte   `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Hash       []byte   `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *CommitBlockContract) Reset()                    { *m = CommitBlockContract{} }
+func (m *CommitBlockContract) String() string            { return proto.CompactTextString(m) }
+func (*CommitBlockContract) ProtoMessage()               {}
+func (*CommitBlockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *CommitBlockContract) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *CommitBlockContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+func (m *CommitBlockContract) GetNodeIds() []uint64 {
+	if m != nil {
+		return m.NodeIds
+	}
+	return nil
+}
+
+func (m *CommitBlockContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *CommitBlockContract) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type UpdateBlockHashContract struct {
+	File       []byte `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Index      uint64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Hash       []byte `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	ClientTime uint64 `protobuf:"varint,4,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *UpdateBlockHashContract) Reset()                    { *m = UpdateBlockHashContract{} }
+func (m *UpdateBlockHashContract) String() string            { return proto.CompactTextString(m) }
+func (*UpdateBlockHashContract) ProtoMessage()               {}
+func (*UpdateBlockHashContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *UpdateBlockHashContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *UpdateBlockHashContract) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *UpdateBlockHashContract) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *UpdateBlockHashContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type TruncateFileContract struct {
+	File       []byte `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Blocks     uint64 `protobuf:"varint,2,opt,name=blocks" json:"blocks,omitempty"`
+	ClientTime uint64 `protobuf:"varint,3,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *TruncateFileContract) Reset()                    { *m = TruncateFileContract{} }
+func (m *TruncateFileContract) String() string            { return proto.CompactTextString(m) }
+func (*TruncateFileContract) ProtoMessage()               {}
+func (*TruncateFileContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *TruncateFileContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *TruncateFileContract) GetBlocks() uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+func (m *TruncateFileContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type AccessFileContract struct {
+	File       []byte `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	ClientTime uint64 `protobuf:"varint,2,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *AccessFileContract) Reset()                    { *m = AccessFileContract{} }
+func (m *AccessFileContract) String() string            { return proto.CompactTextString(m) }
+func (*AccessFileContract) ProtoMessage()               {}
+func (*AccessFileContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *AccessFileContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *AccessFileContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type ChtimesContract struct {
+	Key          []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Dir          bool   `protobuf:"varint,2,opt,name=dir" json:"dir,omitempty"`
+	AccessedAt   uint64 `protobuf:"varint,3,opt,name=accessed_at,json=accessedAt" json:"accessed_at,omitempty"`
+	LastModified uint64 `protobuf:"varint,4,opt,name=last_modified,json=lastModified" json:"last_modified,omitempty"`
+	ClientTime   uint64 `protobuf:"varint,5,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *ChtimesContract) Reset()                    { *m = ChtimesContract{} }
+func (m *ChtimesContract) String() string            { return proto.CompactTextString(m) }
+func (*ChtimesContract) ProtoMessage()               {}
+func (*ChtimesContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *ChtimesContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ChtimesContract) GetDir() bool {
+	if m != nil {
+		return m.Dir
+	}
+	return false
+}
+
+func (m *ChtimesContract) GetAccessedAt() uint64 {
+	if m != nil {
+		return m.AccessedAt
+	}
+	return 0
+}
+
+func (m *ChtimesContract) GetLastModified() uint64 {
+	if m != nil {
+		return m.LastModified
+	}
+	return 0
+}
+
+func (m *ChtimesContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type ReplaceBlockReplicasContract struct {
+	File     []byte   `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Index    uint64   `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	OldHosts []uint64 `protobuf:"varint,3,rep,packed,name=old_hosts,json=oldHosts" json:"old_hosts,omitempty"`
+	NewHosts []uint64 `protobuf:"varint,4,rep,packed,name=new_hosts,json=newHosts" json:"new_hosts,omitempty"`
+}
+
+func (m *ReplaceBlockReplicasContract) Reset()                    { *m = ReplaceBlockReplicasContract{} }
+func (m *ReplaceBlockReplicasContract) String() string            { return proto.CompactTextString(m) }
+func (*ReplaceBlockReplicasContract) ProtoMessage()               {}
+func (*ReplaceBlockReplicasContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *ReplaceBlockReplicasContract) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *ReplaceBlockReplicasContract) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ReplaceBlockReplicasContract) GetOldHosts() []uint64 {
+	if m != nil {
+		return m.OldHosts
+	}
+	return nil
+}
+
+func (m *ReplaceBlockReplicasContract) GetNewHosts() []uint64 {
+	if m != nil {
+		return m.NewHosts
+	}
+	return nil
+}
+
+type ReportReplicasRequest struct {
+	Group     uint64   `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	File      []byte   `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Index     uint64   `protobuf:"varint,3,opt,name=index" json:"index,omitempty"`
+	Hash      []byte   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Hosts     []uint64 `protobuf:"varint,5,rep,packed,name=hosts" json:"hosts,omitempty"`
+	ClientId  uint64   `protobuf:"varint,6,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte   `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *ReportReplicasRequest) Reset()                    { *m = ReportReplicasRequest{} }
+func (m *ReportReplicasRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReportReplicasRequest) ProtoMessage()               {}
+func (*ReportReplicasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+func (m *ReportReplicasRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *ReportReplicasRequest) GetFile() []byte {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *ReportReplicasRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ReportReplicasRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *ReportReplicasRequest) GetHosts() []uint64 {
+	if m != nil {
+		return m.Hosts
+	}
+	return nil
+}
+
+func (m *ReportReplicasRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ReportReplicasRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type StashHeartbeatContract struct {
+	HostId     uint64 `protobuf:"varint,1,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
+	Used       uint64 `protobuf:"varint,2,opt,name=used" json:"used,omitempty"`
+	ClientTime uint64 `protobuf:"varint,3,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+}
+
+func (m *StashHeartbeatContract) Reset()                    { *m = StashHeartbeatContract{} }
+func (m *StashHeartbeatContract) String() string            { return proto.CompactTextString(m) }
+func (*StashHeartbeatContract) ProtoMessage()               {}
+func (*StashHeartbeatContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+func (m *StashHeartbeatContract) GetHostId() uint64 {
+	if m != nil {
+		return m.HostId
+	}
+	return 0
+}
+
+func (m *StashHeartbeatContract) GetUsed() uint64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+func (m *StashHeartbeatContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+type SetStashStateContract struct {
+	HostId uint64     `protobuf:"varint,1,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
+	State  StashState `protobuf:"varint,2,opt,name=state,enum=client.StashState" json:"state,omitempty"`
+}
+
+func (m *SetStashStateContract) Reset()                    { *m = SetStashStateContract{} }
+func (m *SetStashStateContract) String() string            { return proto.CompactTextString(m) }
+func (*SetStashStateContract) ProtoMessage()               {}
+func (*SetStashStateContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+
+func (m *SetStashStateContract) GetHostId() uint64 {
+	if m != nil {
+		return m.HostId
+	}
+	return 0
+}
+
+func (m *SetStashStateContract) GetState() StashState {
+	if m != nil {
+		return m.State
+	}
+	return StashState_ONLINE
+}
+
+type DeregStashContract struct {
+	HostId uint64 `protobuf:"varint,1,opt,name=host_id,json=hostId" json:"host_id,omitempty"`
+}
+
+func (m *DeregStashContract) Reset()                    { *m = DeregStashContract{} }
+func (m *DeregStashContract) String() string            { return proto.CompactTextString(m) }
+func (*DeregStashContract) ProtoMessage()               {}
+func (*DeregStashContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+
+func (m *DeregStashContract) GetHostId() uint64 {
+	if m != nil {
+		return m.HostId
+	}
+	return 0
+}
+
+type FileWriteLock struct {
+	Group   uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Owner   uint64 `protobuf:"varint,2,opt,name=owner" json:"owner,omitempty"`
+	Key     []byte `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	Expires uint64 `protobuf:"varint,5,opt,name=expires" json:"expires,omitempty"`
+}
+
+func (m *FileWriteLock) Reset()                    { *m = FileWriteLock{} }
+func (m *FileWriteLock) String() string            { return proto.CompactTextString(m) }
+func (*FileWriteLock) ProtoMessage()               {}
+func (*FileWriteLock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+func (m *FileWriteLock) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *FileWriteLock) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+func (m *FileWriteLock) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *FileWriteLock) GetExpires() uint64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+type AdvisoryLockHolder struct {
+	Owner   uint64   `protobuf:"varint,1,opt,name=owner" json:"owner,omitempty"`
+	Mode    LockMode `protobuf:"varint,2,opt,name=mode,enum=client.LockMode" json:"mode,omitempty"`
+	Expires uint64   `protobuf:"varint,3,opt,name=expires" json:"expires,omitempty"`
+}
+
+func (m *AdvisoryLockHolder) Reset()                    { *m = AdvisoryLockHolder{} }
+func (m *AdvisoryLockHolder) String() string            { return proto.CompactTextString(m) }
+func (*AdvisoryLockHolder) ProtoMessage()               {}
+func (*AdvisoryLockHolder) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+func (m *AdvisoryLockHolder) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+func (m *AdvisoryLockHolder) GetMode() LockMode {
+	if m != nil {
+		return m.Mode
+	}
+	return LockMode_SHARED
+}
+
+func (m *AdvisoryLockHolder) GetExpires() uint64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+type AdvisoryLock struct {
+	Key     []byte                `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Holders []*AdvisoryLockHolder `protobuf:"bytes,2,rep,name=holders" json:"holders,omitempty"`
+	Waiters []*AdvisoryLockHolder `protobuf:"bytes,3,rep,name=waiters" json:"waiters,omitempty"`
+}
+
+func (m *AdvisoryLock) Reset()                    { *m = AdvisoryLock{} }
+func (m *AdvisoryLock) String() string            { return proto.CompactTextString(m) }
+func (*AdvisoryLock) ProtoMessage()               {}
+func (*AdvisoryLock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *AdvisoryLock) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *AdvisoryLock) GetHolders() []*AdvisoryLockHolder {
+	if m != nil {
+		return m.Holders
+	}
+	return nil
+}
+
+func (m *AdvisoryLock) GetWaiters() []*AdvisoryLockHolder {
+	if m != nil {
+		return m.Waiters
+	}
+	return nil
+}
+
+type LockContract struct {
+	Key        []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Mode       LockMode `protobuf:"varint,2,opt,name=mode,enum=client.LockMode" json:"mode,omitempty"`
+	Ttl        uint64   `protobuf:"varint,3,opt,name=ttl" json:"ttl,omitempty"`
+	ClientTime uint64   `protobuf:"varint,4,opt,name=client_time,json=clientTime" json:"client_time,omitempty"`
+	Wait       bool     `protobuf:"varint,5,opt,name=wait" json:"wait,omitempty"`
+}
+
+func (m *LockContract) Reset()                    { *m = LockContract{} }
+func (m *LockContract) String() string            { return proto.CompactTextString(m) }
+func (*LockContract) ProtoMessage()               {}
+func (*LockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+
+func (m *LockContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *LockContract) GetMode() LockMode {
+	if m != nil {
+		return m.Mode
+	}
+	return LockMode_SHARED
+}
+
+func (m *LockContract) GetTtl() uint64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *LockContract) GetClientTime() uint64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+func (m *LockContract) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+type UnlockContract struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *UnlockContract) Reset()                    { *m = UnlockContract{} }
+func (m *UnlockContract) String() string            { return proto.CompactTextString(m) }
+func (*UnlockContract) ProtoMessage()               {}
+func (*UnlockContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+func (m *UnlockContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type DirectoryItem struct {
+	Type DirectoryItem_ItemType `protobuf:"varint,1,opt,name=type,enum=client.DirectoryItem_ItemType" json:"type,omitempty"`
+	File *FileMeta              `protobuf:"bytes,2,opt,name=file" json:"file,omitempty"`
+	Dir  *Directory             `protobuf:"bytes,3,opt,name=dir" json:"dir,omitempty"`
+}
+
+func (m *DirectoryItem) Reset()                    { *m = DirectoryItem{} }
+func (m *DirectoryItem) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryItem) ProtoMessage()               {}
+func (*DirectoryItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+func (m *DirectoryItem) GetType() DirectoryItem_ItemType {
+	if m != nil {
+		return m.Type
+	}
+	return DirectoryItem_FILE
+}
+
+func (m *DirectoryItem) GetFile() *FileMeta {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *DirectoryItem) GetDir() *Directory {
+	if m != nil {
+		return m.Dir
+	}
+	return nil
+}
+
+type ListDirectoryResponse struct {
+	Name     string           `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Key      []byte           `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Volume   *Volume          `protobuf:"bytes,3,opt,name=volume" json:"volume,omitempty"`
+	Items    []*DirectoryItem `protobuf:"bytes,4,rep,name=items" json:"items,omitempty"`
+	Dir      *Directory       `protobuf:"bytes,5,opt,name=dir" json:"dir,omitempty"`
+	Snapshot *Snapshot        `protobuf:"bytes,6,opt,name=snapshot" json:"snapshot,omitempty"`
+}
+
+func (m *ListDirectoryResponse) Reset()                    { *m = ListDirectoryResponse{} }
+func (m *ListDirectoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListDirectoryResponse) ProtoMessage()               {}
+func (*ListDirectoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+func (m *ListDirectoryResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListDirectoryResponse) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ListDirectoryResponse) GetVolume() *Volume {
+	if m != nil {
+		return m.Volume
+	}
+	return nil
+}
+
+func (m *ListDirectoryResponse) GetItems() []*DirectoryItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *ListDirectoryResponse) GetDir() *Directory {
+	if m != nil {
+		return m.Dir
+	}
+	return nil
+}
+
+func (m *ListDirectoryResponse) GetSnapshot() *Snapshot {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+type ListDirectoryRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Path      string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Xattrs    bool   `protobuf:"varint,5,opt,name=xattrs" json:"xattrs,omitempty"`
+}
+
+func (m *ListDirectoryRequest) Reset()                    { *m = ListDirectoryRequest{} }
+func (m *ListDirectoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListDirectoryRequest) ProtoMessage()               {}
+func (*ListDirectoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+func (m *ListDirectoryRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *ListDirectoryRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ListDirectoryRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ListDirectoryRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ListDirectoryRequest) GetXattrs() bool {
+	if m != nil {
+		return m.Xattrs
+	}
+	return false
+}
+
+type StatRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Path      string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	ClientId  uint64 `protobuf:"varint,3,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Xattrs    bool   `protobuf:"varint,5,opt,name=xattrs" json:"xattrs,omitempty"`
+}
+
+func (m *StatRequest) Reset()                    { *m = StatRequest{} }
+func (m *StatRequest) String() string            { return proto.CompactTextString(m) }
+func (*StatRequest) ProtoMessage()               {}
+func (*StatRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+
+func (m *StatRequest) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+func (m *StatRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *StatRequest) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *StatRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *StatRequest) GetXattrs() bool {
+	if m != nil {
+		return m.Xattrs
+	}
+	return false
+}
+
+type ChmodContract struct {
+	Key  []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Dir  bool   `protobuf:"varint,2,opt,name=dir" json:"dir,omitempty"`
+	Mode uint32 `protobuf:"varint,3,opt,name=mode" json:"mode,omitempty"`
+}
+
+func (m *ChmodContract) Reset()                    { *m = ChmodContract{} }
+func (m *ChmodContract) String() string            { return proto.CompactTextString(m) }
+func (*ChmodContract) ProtoMessage()               {}
+func (*ChmodContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+func (m *ChmodContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ChmodContract) GetDir() bool {
+	if m != nil {
+		return m.Dir
+	}
+	return false
+}
+
+func (m *ChmodContract) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+type ChownContract struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Dir   bool   `protobuf:"varint,2,opt,name=dir" json:"dir,omitempty"`
+	Owner uint64 `protobuf:"varint,3,opt,name=owner" json:"owner,omitempty"`
+	Group uint64 `protobuf:"varint,4,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *ChownContract) Reset()                    { *m = ChownContract{} }
+func (m *ChownContract) String() string            { return proto.CompactTextString(m) }
+func (*ChownContract) ProtoMessage()               {}
+func (*ChownContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *ChownContract) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ChownContract) GetDir() bool {
+	if m != nil {
+		return m.Dir
+	}
+	return false
+}
+
+func (m *ChownContract) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+func (m *ChownContract) GetGroup() uint64 {
+	if m != nil {
+		return m.Group
+	}
+	return 0
+}
+
+type UserGroup struct {
+	Id      uint64   `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Owner   uint64   `protobuf:"varint,2,opt,name=owner" json:"owner,omitempty"`
+	Members []uint64 `protobuf:"varint,3,rep,packed,name=members" json:"members,omitempty"`
+	Name    string   `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *UserGroup) Reset()                    { *m = UserGroup{} }
+func (m *UserGroup) String() string            { return proto.CompactTextString(m) }
+func (*UserGroup) ProtoMessage()               {}
+func (*UserGroup) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+
+func (m *UserGroup) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *UserGroup) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+func (m *UserGroup) GetMembers() []uint64 {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *UserGroup) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetUserGroupRequest struct {
+	Group     uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
+	Id        uint64 `protobuf:"varint,2,opt,name=id" json:"id,omitempty"`
+	Name      string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	ClientId  uint64 `protobuf:"varint,4,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *GetUserGroupRequest) Reset()                    { *m = GetUserGroupRequest{} }
+func (m *GetUserGroupRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetUserGroupRequest) ProtoMessage()               {}
+func (*GetUserGroupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+func (m *GetUserGroupRequest) GetGroup() uint64 {
 	if m != nil {
-		return m.Dir
+		return m.Group
 	}
-	return nil
+	return 0
 }
 
-type ListDirectoryResponse struct {
-	Name   string           `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Key    []byte           `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Volume *Volume          `protobuf:"bytes,3,opt,name=volume" json:"volume,omitempty"`
-	Items  []*DirectoryItem `protobuf:"bytes,4,rep,name=items" json:"items,omitempty"`
+func (m *GetUserGroupRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
 }
 
-func (m *ListDirectoryResponse) Reset()                    { *m = ListDirectoryResponse{} }
-func (m *ListDirectoryResponse) String() string            { return proto.CompactTextString(m) }
-func (*ListDirectoryResponse) ProtoMessage()               {}
-func (*ListDirectoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
-
-func (m *ListDirectoryResponse) GetName() string {
+func (m *GetUserGroupRequest) GetName() string {
 	if m != nil {
 		return m.Name
 	}
 	return ""
 }
 
-func (m *ListDirectoryResponse) GetKey() []byte {
+func (m *GetUserGroupRequest) GetClientId() uint64 {
 	if m != nil {
-		return m.Key
+		return m.ClientId
 	}
-	return nil
+	return 0
 }
 
-func (m *ListDirectoryResponse) GetVolume() *Volume {
+func (m *GetUserGroupRequest) GetSignature() []byte {
 	if m != nil {
-		return m.Volume
+		return m.Signature
 	}
 	return nil
 }
 
-func (m *ListDirectoryResponse) GetItems() []*DirectoryItem {
+type SetAclContract struct {
+	Key    []byte      `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Volume bool        `protobuf:"varint,2,opt,name=volume" json:"volume,omitempty"`
+	Acl    []*AclEntry `protobuf:"bytes,3,rep,name=acl" json:"acl,omitempty"`
+}
+
+func (m *SetAclContract) Reset()                    { *m = SetAclContract{} }
+func (m *SetAclContract) String() string            { return proto.CompactTextString(m) }
+func (*SetAclContract) ProtoMessage()               {}
+func (*SetAclContract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *SetAclContract) GetKey() []byte {
 	if m != nil {
-		return m.Items
+		return m.Key
 	}
 	return nil
 }
 
-type ListDirectoryRequest struct {
-	Group uint64 `protobuf:"varint,1,opt,name=group" json:"group,omitempty"`
-	Path  string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
-}
-
-func (m *ListDirectoryRequest) Reset()                    { *m = ListDirectoryRequest{} }
-func (m *ListDirectoryRequest) String() string            { return proto.CompactTextString(m) }
-func (*ListDirectoryRequest) ProtoMessage()               {}
-func (*ListDirectoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
-
-func (m *ListDirectoryRequest) GetGroup() uint64 {
+func (m *SetAclContract) GetVolume() bool {
 	if m != nil {
-		return m.Group
+		return m.Volume
 	}
-	return 0
+	return false
 }
 
-func (m *ListDirectoryRequest) GetPath() string {
+func (m *SetAclContract) GetAcl() []*AclEntry {
 	if m != nil {
-		return m.Path
+		return m.Acl
 	}
-	return ""
+	return nil
 }
 
 type Nothing struct {
@@ -950,14 +3759,46 @@ type Nothing struct {
 func (m *Nothing) Reset()                    { *m = Nothing{} }
 func (m *Nothing) String() string            { return proto.CompactTextString(m) }
 func (*Nothing) ProtoMessage()               {}
-func (*Nothing) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*Nothing) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
 
 func init() {
 	proto.RegisterType((*BlockData)(nil), "client.BlockData")
 	proto.RegisterType((*Block)(nil), "client.Block")
 	proto.RegisterType((*FileMeta)(nil), "client.FileMeta")
+	proto.RegisterType((*Xattr)(nil), "client.Xattr")
+	proto.RegisterType((*SetXattrContract)(nil), "client.SetXattrContract")
 	proto.RegisterType((*Directory)(nil), "client.Directory")
+	proto.RegisterType((*Quota)(nil), "client.Quota")
+	proto.RegisterType((*DirUsage)(nil), "client.DirUsage")
+	proto.RegisterType((*DeleteItemContract)(nil), "client.DeleteItemContract")
+	proto.RegisterType((*DeletedFiles)(nil), "client.DeletedFiles")
+	proto.RegisterType((*MoveItemContract)(nil), "client.MoveItemContract")
+	proto.RegisterType((*Snapshot)(nil), "client.Snapshot")
+	proto.RegisterType((*SnapshotContract)(nil), "client.SnapshotContract")
+	proto.RegisterType((*MoveKeptBlockContract)(nil), "client.MoveKeptBlockContract")
+	proto.RegisterType((*DeleteSnapshotContract)(nil), "client.DeleteSnapshotContract")
+	proto.RegisterType((*DiffRequest)(nil), "client.DiffRequest")
+	proto.RegisterType((*DiffEntry)(nil), "client.DiffEntry")
+	proto.RegisterType((*DiffResponse)(nil), "client.DiffResponse")
+	proto.RegisterType((*ExportHeader)(nil), "client.ExportHeader")
+	proto.RegisterType((*ExportRecord)(nil), "client.ExportRecord")
+	proto.RegisterType((*SetQuotaContract)(nil), "client.SetQuotaContract")
+	proto.RegisterType((*StoragePolicy)(nil), "client.StoragePolicy")
+	proto.RegisterType((*SetPolicyContract)(nil), "client.SetPolicyContract")
 	proto.RegisterType((*Volume)(nil), "client.Volume")
+	proto.RegisterType((*TrashRetention)(nil), "client.TrashRetention")
+	proto.RegisterType((*TrashEntry)(nil), "client.TrashEntry")
+	proto.RegisterType((*RestoreTrashContract)(nil), "client.RestoreTrashContract")
+	proto.RegisterType((*PurgeTrashContract)(nil), "client.PurgeTrashContract")
+	proto.RegisterType((*ListTrashRequest)(nil), "client.ListTrashRequest")
+	proto.RegisterType((*ListTrashResponse)(nil), "client.ListTrashResponse")
+	proto.RegisterType((*VersionRetention)(nil), "client.VersionRetention")
+	proto.RegisterType((*FileVersion)(nil), "client.FileVersion")
+	proto.RegisterType((*CommitVersionContract)(nil), "client.CommitVersionContract")
+	proto.RegisterType((*RestoreVersionContract)(nil), "client.RestoreVersionContract")
+	proto.RegisterType((*ListVersionsRequest)(nil), "client.ListVersionsRequest")
+	proto.RegisterType((*ListVersionsResponse)(nil), "client.ListVersionsResponse")
+	proto.RegisterType((*AclEntry)(nil), "client.AclEntry")
 	proto.RegisterType((*HostStash)(nil), "client.HostStash")
 	proto.RegisterType((*OpenRequest)(nil), "client.OpenRequest")
 	proto.RegisterType((*GetBlockRequest)(nil), "client.GetBlockRequest")
@@ -966,9 +3807,17 @@ func init() {
 	proto.RegisterType((*CreateBlockRequest)(nil), "client.CreateBlockRequest")
 	proto.RegisterType((*GetFileRequest)(nil), "client.GetFileRequest")
 	proto.RegisterType((*GetVolumeRequest)(nil), "client.GetVolumeRequest")
+	proto.RegisterType((*ListVolumesRequest)(nil), "client.ListVolumesRequest")
+	proto.RegisterType((*ListVolumesResponse)(nil), "client.ListVolumesResponse")
+	proto.RegisterType((*VolumeUsage)(nil), "client.VolumeUsage")
+	proto.RegisterType((*ReplicationJob)(nil), "client.ReplicationJob")
+	proto.RegisterType((*UpdateVolumeContract)(nil), "client.UpdateVolumeContract")
+	proto.RegisterType((*DeleteVolumeContract)(nil), "client.DeleteVolumeContract")
+	proto.RegisterType((*PrincipalList)(nil), "client.PrincipalList")
 	proto.RegisterType((*GetDirectoryRequest)(nil), "client.GetDirectoryRequest")
 	proto.RegisterType((*BlockStashSuggestionRequest)(nil), "client.BlockStashSuggestionRequest")
 	proto.RegisterType((*BlockStashSuggestion)(nil), "client.BlockStashSuggestion")
+	proto.RegisterType((*ReplicateBlockRequest)(nil), "client.ReplicateBlockRequest")
 	proto.RegisterType((*WriteResult)(nil), "client.WriteResult")
 	proto.RegisterType((*NewDirectoryContract)(nil), "client.NewDirectoryContract")
 	proto.RegisterType((*AcquireFileWriteLockContract)(nil), "client.AcquireFileWriteLockContract")
@@ -976,11 +3825,33 @@ func init() {
 	proto.RegisterType((*TouchFileContract)(nil), "client.TouchFileContract")
 	proto.RegisterType((*ConfirmBlockContract)(nil), "client.ConfirmBlockContract")
 	proto.RegisterType((*CommitBlockContract)(nil), "client.CommitBlockContract")
+	proto.RegisterType((*UpdateBlockHashContract)(nil), "client.UpdateBlockHashContract")
+	proto.RegisterType((*TruncateFileContract)(nil), "client.TruncateFileContract")
+	proto.RegisterType((*AccessFileContract)(nil), "client.AccessFileContract")
+	proto.RegisterType((*ChtimesContract)(nil), "client.ChtimesContract")
+	proto.RegisterType((*ReplaceBlockReplicasContract)(nil), "client.ReplaceBlockReplicasContract")
+	proto.RegisterType((*ReportReplicasRequest)(nil), "client.ReportReplicasRequest")
+	proto.RegisterType((*StashHeartbeatContract)(nil), "client.StashHeartbeatContract")
+	proto.RegisterType((*SetStashStateContract)(nil), "client.SetStashStateContract")
+	proto.RegisterType((*DeregStashContract)(nil), "client.DeregStashContract")
 	proto.RegisterType((*FileWriteLock)(nil), "client.FileWriteLock")
+	proto.RegisterType((*AdvisoryLockHolder)(nil), "client.AdvisoryLockHolder")
+	proto.RegisterType((*AdvisoryLock)(nil), "client.AdvisoryLock")
+	proto.RegisterType((*LockContract)(nil), "client.LockContract")
+	proto.RegisterType((*UnlockContract)(nil), "client.UnlockContract")
 	proto.RegisterType((*DirectoryItem)(nil), "client.DirectoryItem")
 	proto.RegisterType((*ListDirectoryResponse)(nil), "client.ListDirectoryResponse")
 	proto.RegisterType((*ListDirectoryRequest)(nil), "client.ListDirectoryRequest")
+	proto.RegisterType((*StatRequest)(nil), "client.StatRequest")
+	proto.RegisterType((*ChmodContract)(nil), "client.ChmodContract")
+	proto.RegisterType((*ChownContract)(nil), "client.ChownContract")
+	proto.RegisterType((*UserGroup)(nil), "client.UserGroup")
+	proto.RegisterType((*GetUserGroupRequest)(nil), "client.GetUserGroupRequest")
+	proto.RegisterType((*SetAclContract)(nil), "client.SetAclContract")
 	proto.RegisterType((*Nothing)(nil), "client.Nothing")
+	proto.RegisterEnum("client.StashState", StashState_name, StashState_value)
+	proto.RegisterEnum("client.LockMode", LockMode_name, LockMode_value)
+	proto.RegisterEnum("client.DiffEntry_Change", DiffEntry_Change_name, DiffEntry_Change_value)
 	proto.RegisterEnum("client.DirectoryItem_ItemType", DirectoryItem_ItemType_name, DirectoryItem_ItemType_value)
 }
 
@@ -1005,6 +3876,16 @@ type PCFSClient interface {
 	CreateBlock(ctx context.Context, in *CreateBlockRequest, opts ...grpc.CallOption) (*WriteResult, error)
 	DeleteBlock(ctx context.Context, in *DeleteBlockRequest, opts ...grpc.CallOption) (*WriteResult, error)
 	SuggestBlockStash(ctx context.Context, in *BlockStashSuggestionRequest, opts ...grpc.CallOption) (*BlockStashSuggestion, error)
+	ReplicateBlock(ctx context.Context, in *ReplicateBlockRequest, opts ...grpc.CallOption) (*WriteResult, error)
+	GetFileWriteLock(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*FileWriteLock, error)
+	ListVolumes(ctx context.Context, in *ListVolumesRequest, opts ...grpc.CallOption) (*ListVolumesResponse, error)
+	GetVolumeUsage(ctx context.Context, in *GetVolumeRequest, opts ...grpc.CallOption) (*VolumeUsage, error)
+	DiffSnapshots(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*DirectoryItem, error)
+	GetUserGroup(ctx context.Context, in *GetUserGroupRequest, opts ...grpc.CallOption) (*UserGroup, error)
+	ReportReplicas(ctx context.Context, in *ReportReplicasRequest, opts ...grpc.CallOption) (*WriteResult, error)
 }
 
 type pCFSClient struct {
@@ -1105,6 +3986,96 @@ func (c *pCFSClient) SuggestBlockStash(ctx context.Context, in *BlockStashSugges
 	return out, nil
 }
 
+func (c *pCFSClient) ReplicateBlock(ctx context.Context, in *ReplicateBlockRequest, opts ...grpc.CallOption) (*WriteResult, error) {
+	out := new(WriteResult)
+	err := grpc.Invoke(ctx, "/client.PCFS/ReplicateBlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) GetFileWriteLock(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*FileWriteLock, error) {
+	out := new(FileWriteLock)
+	err := grpc.Invoke(ctx, "/client.PCFS/GetFileWriteLock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) ListVolumes(ctx context.Context, in *ListVolumesRequest, opts ...grpc.CallOption) (*ListVolumesResponse, error) {
+	out := new(ListVolumesResponse)
+	err := grpc.Invoke(ctx, "/client.PCFS/ListVolumes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) GetVolumeUsage(ctx context.Context, in *GetVolumeRequest, opts ...grpc.CallOption) (*VolumeUsage, error) {
+	out := new(VolumeUsage)
+	err := grpc.Invoke(ctx, "/client.PCFS/GetVolumeUsage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) DiffSnapshots(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	out := new(DiffResponse)
+	err := grpc.Invoke(ctx, "/client.PCFS/DiffSnapshots", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	out := new(ListVersionsResponse)
+	err := grpc.Invoke(ctx, "/client.PCFS/ListVersions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	out := new(ListTrashResponse)
+	err := grpc.Invoke(ctx, "/client.PCFS/ListTrash", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*DirectoryItem, error) {
+	out := new(DirectoryItem)
+	err := grpc.Invoke(ctx, "/client.PCFS/Stat", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) GetUserGroup(ctx context.Context, in *GetUserGroupRequest, opts ...grpc.CallOption) (*UserGroup, error) {
+	out := new(UserGroup)
+	err := grpc.Invoke(ctx, "/client.PCFS/GetUserGroup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pCFSClient) ReportReplicas(ctx context.Context, in *ReportReplicasRequest, opts ...grpc.CallOption) (*WriteResult, error) {
+	out := new(WriteResult)
+	err := grpc.Invoke(ctx, "/client.PCFS/ReportReplicas", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for PCFS service
 
 type PCFSServer interface {
@@ -1118,6 +4089,16 @@ type PCFSServer interface {
 	CreateBlock(context.Context, *CreateBlockRequest) (*WriteResult, error)
 	DeleteBlock(context.Context, *DeleteBlockRequest) (*WriteResult, error)
 	SuggestBlockStash(context.Context, *BlockStashSuggestionRequest) (*BlockStashSuggestion, error)
+	ReplicateBlock(context.Context, *ReplicateBlockRequest) (*WriteResult, error)
+	GetFileWriteLock(context.Context, *GetFileRequest) (*FileWriteLock, error)
+	ListVolumes(context.Context, *ListVolumesRequest) (*ListVolumesResponse, error)
+	GetVolumeUsage(context.Context, *GetVolumeRequest) (*VolumeUsage, error)
+	DiffSnapshots(context.Context, *DiffRequest) (*DiffResponse, error)
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	Stat(context.Context, *StatRequest) (*DirectoryItem, error)
+	GetUserGroup(context.Context, *GetUserGroupRequest) (*UserGroup, error)
+	ReportReplicas(context.Context, *ReportReplicasRequest) (*WriteResult, error)
 }
 
 func RegisterPCFSServer(s *grpc.Server, srv PCFSServer) {
@@ -1304,6 +4285,186 @@ func _PCFS_SuggestBlockStash_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PCFS_ReplicateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicateBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).ReplicateBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/ReplicateBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).ReplicateBlock(ctx, req.(*ReplicateBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_GetFileWriteLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).GetFileWriteLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/GetFileWriteLock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).GetFileWriteLock(ctx, req.(*GetFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_ListVolumes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVolumesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).ListVolumes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/ListVolumes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).ListVolumes(ctx, req.(*ListVolumesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_GetVolumeUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).GetVolumeUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/GetVolumeUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).GetVolumeUsage(ctx, req.(*GetVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_DiffSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).DiffSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/DiffSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).DiffSnapshots(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/ListVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/ListTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/Stat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).Stat(ctx, req.(*StatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_GetUserGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).GetUserGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/GetUserGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).GetUserGroup(ctx, req.(*GetUserGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PCFS_ReportReplicas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportReplicasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PCFSServer).ReportReplicas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.PCFS/ReportReplicas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PCFSServer).ReportReplicas(ctx, req.(*ReportReplicasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PCFS_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "client.PCFS",
 	HandlerType: (*PCFSServer)(nil),
@@ -1348,6 +4509,46 @@ var _PCFS_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SuggestBlockStash",
 			Handler:    _PCFS_SuggestBlockStash_Handler,
 		},
+		{
+			MethodName: "ReplicateBlock",
+			Handler:    _PCFS_ReplicateBlock_Handler,
+		},
+		{
+			MethodName: "GetFileWriteLock",
+			Handler:    _PCFS_GetFileWriteLock_Handler,
+		},
+		{
+			MethodName: "ListVolumes",
+			Handler:    _PCFS_ListVolumes_Handler,
+		},
+		{
+			MethodName: "GetVolumeUsage",
+			Handler:    _PCFS_GetVolumeUsage_Handler,
+		},
+		{
+			MethodName: "DiffSnapshots",
+			Handler:    _PCFS_DiffSnapshots_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _PCFS_ListVersions_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _PCFS_ListTrash_Handler,
+		},
+		{
+			MethodName: "Stat",
+			Handler:    _PCFS_Stat_Handler,
+		},
+		{
+			MethodName: "GetUserGroup",
+			Handler:    _PCFS_GetUserGroup_Handler,
+		},
+		{
+			MethodName: "ReportReplicas",
+			Handler:    _PCFS_ReportReplicas_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/api.proto",
@@ -1356,81 +4557,230 @@ var _PCFS_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("proto/api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1201 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xdb, 0x6e, 0x23, 0x45,
-	0x13, 0xf6, 0xd8, 0xe3, 0x53, 0xd9, 0xde, 0x4d, 0x7a, 0xbd, 0xbb, 0xb3, 0xde, 0xe4, 0xff, 0xad,
-	0x0e, 0x2c, 0x91, 0x40, 0x61, 0xe5, 0x20, 0x71, 0x10, 0x88, 0x84, 0x78, 0x93, 0x8d, 0x94, 0x04,
-	0x98, 0x44, 0x80, 0xb8, 0x31, 0xbd, 0xe3, 0x4e, 0xdc, 0xc4, 0x9e, 0x9e, 0x4c, 0xb7, 0x59, 0x02,
-	0x77, 0xdc, 0x70, 0xc3, 0x15, 0x4f, 0xc0, 0x0b, 0xf0, 0x2a, 0x3c, 0x13, 0xea, 0xc3, 0x78, 0xc6,
-	0x87, 0x24, 0x06, 0x6e, 0xac, 0xae, 0x9a, 0xee, 0xea, 0xaa, 0xfa, 0xbe, 0xaa, 0x6a, 0xc3, 0xfd,
-	0x28, 0xe6, 0x92, 0xbf, 0x4b, 0x22, 0xb6, 0xa5, 0x57, 0xa8, 0x14, 0x0c, 0x19, 0x0d, 0x25, 0x16,
-	0x50, 0xfd, 0x6c, 0xc8, 0x83, 0xcb, 0x2e, 0x91, 0x04, 0x35, 0xa1, 0x78, 0x11, 0xf3, 0x71, 0xe4,
-	0x39, 0x6d, 0x67, 0xd3, 0xf5, 0x8d, 0xa0, 0xb4, 0x2c, 0xec, 0xd3, 0x1f, 0xbd, 0xbc, 0xd1, 0x6a,
-	0x01, 0x21, 0x70, 0x25, 0x61, 0x43, 0xaf, 0xd0, 0x76, 0x36, 0x1b, 0xbe, 0x5e, 0x2b, 0xdd, 0x39,
-	0x1b, 0x52, 0xcf, 0x6d, 0x3b, 0x9b, 0x75, 0x5f, 0xaf, 0x95, 0xae, 0x4f, 0x24, 0xf1, 0x8a, 0x46,
-	0xa7, 0xd6, 0x78, 0x1b, 0x8a, 0xfa, 0xd2, 0xd4, 0xb4, 0x93, 0x35, 0xdd, 0x84, 0xe2, 0x80, 0x0b,
-	0x29, 0xbc, 0x7c, 0xbb, 0xa0, 0xb4, 0x5a, 0xc0, 0x7f, 0x39, 0x50, 0xd9, 0x67, 0x43, 0x7a, 0x4c,
-	0x25, 0x51, 0x56, 0x43, 0x32, 0xa2, 0xfa, 0x5c, 0xd5, 0xd7, 0x6b, 0xa5, 0x13, 0xec, 0x27, 0x6a,
-	0xdd, 0xd4, 0x6b, 0xb4, 0x01, 0x8d, 0x21, 0x11, 0xb2, 0x37, 0xe2, 0x7d, 0x76, 0xce, 0x68, 0x5f,
-	0xbb, 0xeb, 0xfa, 0x75, 0xa5, 0x3c, 0xb6, 0x3a, 0xb4, 0x0e, 0x10, 0xc4, 0x94, 0x48, 0xda, 0xef,
-	0x11, 0xa9, 0x9d, 0x77, 0xfd, 0xaa, 0xd5, 0xec, 0x4a, 0xf5, 0xf9, 0x95, 0xf2, 0xb6, 0xa7, 0xad,
-	0x97, 0x74, 0xbc, 0x55, 0xad, 0x39, 0x55, 0x57, 0xac, 0x40, 0xe1, 0x92, 0x5e, 0x7b, 0x65, 0x1d,
-	0x9f, 0x5a, 0xa2, 0x37, 0xa1, 0xa4, 0x3f, 0x0b, 0xaf, 0xd2, 0x2e, 0x6c, 0xd6, 0x3a, 0x8d, 0x2d,
-	0x93, 0xec, 0x2d, 0x1d, 0xb4, 0x6f, 0x3f, 0xe2, 0x03, 0xa8, 0x76, 0x59, 0x4c, 0x03, 0xc9, 0xe3,
-	0xeb, 0x85, 0x01, 0x59, 0xcb, 0xf9, 0xd4, 0x72, 0x13, 0x8a, 0x2a, 0xa9, 0xc2, 0x2b, 0xb4, 0x0b,
-	0x9b, 0x75, 0xdf, 0x08, 0xf8, 0x37, 0x07, 0x4a, 0x5f, 0xf1, 0xe1, 0xd8, 0xe4, 0x60, 0x09, 0x33,
-	0x18, 0xea, 0x31, 0x8d, 0x86, 0x2c, 0x20, 0x92, 0xf1, 0x50, 0x58, 0x0c, 0xa7, 0x74, 0x33, 0x51,
-	0xbb, 0xb3, 0x51, 0x3f, 0x81, 0x4a, 0xcc, 0xb9, 0xec, 0xf5, 0x59, 0x6c, 0xa1, 0x2d, 0x2b, 0xb9,
-	0xcb, 0x62, 0xfc, 0x3d, 0x54, 0x5f, 0x72, 0x21, 0x4f, 0x25, 0x11, 0x03, 0xf4, 0x18, 0xca, 0x0a,
-	0xbe, 0x1e, 0xeb, 0x5b, 0x8c, 0x4b, 0x4a, 0x3c, 0xec, 0xa3, 0x16, 0x54, 0x02, 0x12, 0x91, 0x80,
-	0xc9, 0x6b, 0x8b, 0xd8, 0x44, 0x56, 0x51, 0x8c, 0xc5, 0x04, 0x2c, 0xbd, 0x56, 0xa1, 0xf3, 0xd7,
-	0x21, 0x8d, 0x2d, 0x3e, 0x46, 0xc0, 0xdb, 0x50, 0xfb, 0x3c, 0xa2, 0xa1, 0x4f, 0xaf, 0xc6, 0x54,
-	0xc8, 0xe5, 0xc2, 0xc7, 0x5f, 0xc2, 0xfd, 0x03, 0x2a, 0x0d, 0x18, 0xf6, 0xe0, 0x3f, 0x64, 0xbe,
-	0x66, 0x79, 0x21, 0x65, 0x39, 0xfe, 0xc5, 0x81, 0xe6, 0x6e, 0x14, 0xd1, 0xb0, 0x7f, 0xc6, 0xff,
-	0xb5, 0xe1, 0x47, 0x50, 0xe2, 0xe7, 0xe7, 0x82, 0x4a, 0x0b, 0x88, 0x95, 0x96, 0x2e, 0xab, 0x67,
-	0x80, 0xba, 0x74, 0x48, 0x25, 0x9d, 0xf2, 0xc0, 0xc6, 0xef, 0xa4, 0xf1, 0xc7, 0x80, 0xf6, 0x34,
-	0xbb, 0xff, 0x73, 0x0a, 0xb2, 0x1e, 0xad, 0x41, 0x55, 0xb0, 0x8b, 0x90, 0xc8, 0x71, 0x4c, 0xad,
-	0x5b, 0xa9, 0x02, 0x7f, 0x04, 0xf7, 0x0e, 0xa8, 0x54, 0xf5, 0x7b, 0xfb, 0x7d, 0x89, 0xe5, 0x7c,
-	0x26, 0xb9, 0x1f, 0xc3, 0xca, 0x01, 0x95, 0x86, 0xe1, 0x77, 0x9e, 0xd6, 0xf8, 0xe7, 0x53, 0xfc,
-	0xf1, 0x27, 0xf0, 0xe0, 0x80, 0xca, 0x49, 0xa5, 0xdd, 0x6e, 0x60, 0x9e, 0x2c, 0x2f, 0xe0, 0xa9,
-	0x4e, 0x93, 0xa6, 0xf3, 0xe9, 0xf8, 0xe2, 0x82, 0x0a, 0x55, 0x20, 0x77, 0x9a, 0x09, 0xc7, 0x23,
-	0x6d, 0xa6, 0xe1, 0xab, 0x25, 0xfe, 0x14, 0x9a, 0x8b, 0xcc, 0xa0, 0xb7, 0xa0, 0x18, 0xf2, 0x3e,
-	0x15, 0x9e, 0xa3, 0x5b, 0xc5, 0x6a, 0xd2, 0x2a, 0x26, 0x15, 0xe4, 0x9b, 0xef, 0xf8, 0x3b, 0xa8,
-	0x7d, 0x1d, 0x33, 0x49, 0x7d, 0x2a, 0xc6, 0x43, 0x89, 0x3c, 0x28, 0x8b, 0x71, 0x10, 0x50, 0x6a,
-	0xea, 0xaa, 0xe2, 0x27, 0xa2, 0xfa, 0x12, 0xd3, 0x11, 0x61, 0xa1, 0xb0, 0x98, 0x25, 0x62, 0x5a,
-	0xd2, 0x03, 0x22, 0x06, 0x96, 0xbe, 0xa6, 0xa4, 0x5f, 0x12, 0x31, 0xc0, 0xdf, 0x42, 0xf3, 0x84,
-	0xbe, 0x9e, 0x24, 0x6a, 0x8f, 0x87, 0x32, 0x26, 0x81, 0xee, 0x7f, 0x11, 0x89, 0x69, 0x68, 0x8a,
-	0xdd, 0xf0, 0xa8, 0x6a, 0x34, 0x5d, 0x16, 0xa3, 0x0d, 0x28, 0x28, 0xbd, 0x32, 0x97, 0xf1, 0x3f,
-	0xcd, 0xb7, 0xfa, 0x8a, 0x9f, 0xc3, 0xda, 0x6e, 0x70, 0x35, 0x66, 0x31, 0x55, 0x14, 0xd0, 0x81,
-	0x1c, 0xf1, 0xe0, 0x72, 0x72, 0xc7, 0x3c, 0x49, 0x9f, 0xc3, 0x9a, 0x4f, 0x87, 0x94, 0x88, 0xa5,
-	0x4f, 0xc4, 0xb0, 0x7a, 0xc6, 0xc7, 0xc1, 0x40, 0xed, 0x9f, 0x6c, 0xfb, 0x3f, 0xd4, 0x8c, 0x47,
-	0x3d, 0xc9, 0x6c, 0x63, 0x70, 0x7d, 0x30, 0xaa, 0x33, 0x96, 0xe9, 0x98, 0xf9, 0xe9, 0x96, 0x91,
-	0x84, 0x54, 0xd7, 0xfe, 0xab, 0xd2, 0xfc, 0x41, 0xf3, 0xcf, 0x52, 0xde, 0x4a, 0xf8, 0x57, 0x07,
-	0x9a, 0x7b, 0x3c, 0x3c, 0x67, 0xf1, 0x48, 0xc3, 0x3b, 0xb9, 0xf7, 0x31, 0x94, 0x15, 0x6e, 0x99,
-	0xbe, 0xa7, 0xc4, 0xc3, 0xfe, 0xf2, 0x3d, 0x05, 0xbd, 0x03, 0x85, 0x98, 0x5e, 0xe9, 0x0b, 0x6b,
-	0x9d, 0x56, 0x92, 0xd8, 0xf9, 0xca, 0xf5, 0xd5, 0x36, 0xfc, 0x33, 0x3c, 0xd8, 0xe3, 0xa3, 0x11,
-	0x93, 0xd3, 0x7e, 0x2c, 0x9e, 0xb0, 0x33, 0x59, 0xc9, 0xcf, 0x65, 0xe5, 0x09, 0x54, 0xac, 0xfb,
-	0x66, 0xd6, 0xb8, 0x7e, 0xd9, 0xf8, 0x2f, 0x16, 0xd5, 0x3e, 0x3e, 0x86, 0xc6, 0x14, 0x4a, 0x37,
-	0x37, 0x13, 0xd3, 0xc3, 0xf3, 0x99, 0x1e, 0x9e, 0x20, 0xe9, 0xa6, 0x48, 0xfe, 0xe9, 0x40, 0x63,
-	0x42, 0xa0, 0x43, 0x49, 0x47, 0xa8, 0x03, 0xae, 0xbc, 0x8e, 0x0c, 0x7e, 0xf7, 0x3a, 0xff, 0x9b,
-	0x63, 0x99, 0xda, 0xb4, 0xa5, 0x7e, 0xce, 0xae, 0x23, 0xea, 0xeb, 0xbd, 0xe8, 0x8d, 0x4c, 0x2b,
-	0xa9, 0x75, 0x56, 0x92, 0x33, 0xc9, 0x1b, 0xc2, 0x66, 0x79, 0x29, 0xfa, 0xae, 0x43, 0x25, 0x31,
-	0x8e, 0x2a, 0xe0, 0xee, 0x1f, 0x1e, 0xbd, 0x58, 0xc9, 0xa1, 0x32, 0x14, 0xba, 0x87, 0xfe, 0x8a,
-	0x83, 0x7f, 0x77, 0xe0, 0xe1, 0x11, 0x13, 0xd9, 0x26, 0x23, 0x22, 0x1e, 0x8a, 0x65, 0xe7, 0xf1,
-	0xb3, 0x09, 0xbb, 0x8c, 0x1b, 0xf7, 0x12, 0x37, 0x6c, 0xcf, 0xb3, 0x5f, 0xd1, 0xdb, 0x50, 0x64,
-	0x92, 0x8e, 0x84, 0xe7, 0xea, 0x66, 0xf1, 0x70, 0x61, 0x1a, 0x7c, 0xb3, 0x07, 0xef, 0x40, 0x73,
-	0xc6, 0xa7, 0x3b, 0x3a, 0x67, 0x44, 0xe4, 0x20, 0x29, 0x03, 0xb5, 0xc6, 0x55, 0x28, 0x9f, 0x70,
-	0x39, 0x60, 0xe1, 0x45, 0xe7, 0x8f, 0x22, 0xb8, 0x5f, 0xec, 0xed, 0x9f, 0xa2, 0x0f, 0xa0, 0x92,
-	0xcc, 0x4e, 0xf4, 0x38, 0xb9, 0x7f, 0x66, 0x9a, 0xb6, 0x56, 0xa7, 0x1e, 0x3c, 0xea, 0x69, 0x89,
-	0x73, 0xe8, 0x3d, 0xa8, 0x9c, 0x26, 0x27, 0xe7, 0x37, 0xb4, 0x1e, 0x24, 0xaa, 0x4c, 0x97, 0xc3,
-	0x39, 0xf4, 0x21, 0xd4, 0xec, 0xdc, 0xd0, 0xef, 0xbe, 0x47, 0x99, 0x2b, 0x33, 0xc3, 0xa4, 0x35,
-	0x87, 0x2e, 0xce, 0xa1, 0xf7, 0xa1, 0x3a, 0x19, 0x1b, 0xc8, 0xcb, 0x1c, 0x9c, 0x9a, 0x24, 0xad,
-	0x99, 0x64, 0xe3, 0x1c, 0xda, 0x81, 0x7a, 0x76, 0x62, 0xa0, 0xa7, 0x99, 0xb3, 0xb3, 0xe9, 0x6c,
-	0xcd, 0x53, 0x06, 0xe7, 0xd0, 0x09, 0x34, 0xa6, 0x72, 0x8f, 0xd6, 0x92, 0x5d, 0x8b, 0x20, 0x69,
-	0xad, 0xdf, 0xf0, 0xd5, 0x90, 0x08, 0xe7, 0x50, 0x17, 0x1a, 0x53, 0xaf, 0x8b, 0xd4, 0xde, 0xa2,
-	0x47, 0xc7, 0x4d, 0xb9, 0xdc, 0x81, 0x5a, 0xa6, 0x7b, 0xa0, 0x5b, 0x5a, 0xca, 0x2d, 0x16, 0x32,
-	0x2f, 0x8c, 0xd4, 0xc2, 0xfc, 0xb3, 0xe3, 0x26, 0x0b, 0xdf, 0xc0, 0xaa, 0x9d, 0x7e, 0xe9, 0x38,
-	0x44, 0x1b, 0x53, 0x74, 0x58, 0x3c, 0x69, 0x5b, 0x6b, 0xb7, 0x6d, 0xc2, 0xb9, 0x57, 0x25, 0xfd,
-	0xc7, 0x66, 0xfb, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4a, 0x8a, 0x06, 0x32, 0xeb, 0x0c, 0x00,
-	0x00,
+	// 3600 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x3b, 0x4d, 0x73, 0x1b, 0xc7,
+	0xb1, 0x5c, 0xec, 0xe2, 0xab, 0x41, 0x52, 0xd0, 0x0a, 0xa4, 0x60, 0x8a, 0x7a, 0x8f, 0x6f, 0x64,
+	0xfb, 0xb1, 0x6c, 0x59, 0xf6, 0x93, 0x5d, 0xf5, 0x9c, 0x72, 0x2a, 0x36, 0x44, 0x40, 0x24, 0x6d,
+	0x91, 0x92, 0x17, 0x94, 0xe2, 0xe4, 0x10, 0x66, 0x89, 0x1d, 0x92, 0x6b, 0x01, 0xbb, 0xd0, 0xee,
+	0x40, 0x22, 0xed, 0xaa, 0x1c, 0x72, 0x71, 0x55, 0x12, 0x57, 0x2a, 0xe5, 0xca, 0x25, 0x95, 0xfc,
+	0x83, 0xc4, 0xb7, 0xdc, 0x92, 0x1f, 0x90, 0x6b, 0x72, 0xca, 0x31, 0x87, 0x54, 0xe5, 0x67, 0xa4,
+	0x7a, 0x66, 0x76, 0x77, 0x76, 0xb1, 0xf8, 0x90, 0xe8, 0xa4, 0x72, 0x41, 0xcd, 0x4c, 0xcf, 0x4e,
+	0x77, 0x4f, 0xf7, 0xf4, 0xd7, 0x0c, 0xe0, 0xd2, 0x30, 0xf0, 0x99, 0xff, 0xa6, 0x3d, 0x74, 0x6f,
+	0xf1, 0x96, 0x59, 0xea, 0xf5, 0x5d, 0xea, 0x31, 0xf2, 0xb5, 0x06, 0xd5, 0x3b, 0x7d, 0xbf, 0xf7,
+	0xb8, 0x6d, 0x33, 0xdb, 0x6c, 0x40, 0xf1, 0x24, 0xf0, 0x47, 0xc3, 0xa6, 0xb6, 0xa1, 0x6d, 0x1a,
+	0x96, 0xe8, 0xe0, 0xa8, 0xeb, 0x39, 0xf4, 0xac, 0x59, 0x10, 0xa3, 0xbc, 0x63, 0x9a, 0x60, 0x30,
+	0xdb, 0xed, 0x37, 0xf5, 0x0d, 0x6d, 0x73, 0xc9, 0xe2, 0x6d, 0x1c, 0x3b, 0x76, 0xfb, 0xb4, 0x69,
+	0x6c, 0x68, 0x9b, 0x8b, 0x16, 0x6f, 0xe3, 0x98, 0x63, 0x33, 0xbb, 0x59, 0x14, 0x63, 0xd8, 0x36,
+	0xaf, 0x41, 0x55, 0xe0, 0x3f, 0x74, 0x9d, 0x66, 0x89, 0xaf, 0x5a, 0x11, 0x03, 0xbb, 0x8e, 0xb9,
+	0x0e, 0xd5, 0xd0, 0x3d, 0xf1, 0x6c, 0x36, 0x0a, 0x68, 0xb3, 0xcc, 0xbf, 0x4a, 0x06, 0xc8, 0x36,
+	0x14, 0x39, 0xbd, 0x09, 0x55, 0x9a, 0x4a, 0x55, 0x03, 0x8a, 0xa7, 0x7e, 0xc8, 0xc2, 0x66, 0x61,
+	0x43, 0xc7, 0x51, 0xde, 0x41, 0x1a, 0x4e, 0xed, 0xf0, 0x94, 0xd3, 0xba, 0x68, 0xf1, 0x36, 0xf9,
+	0x87, 0x0e, 0x95, 0xbb, 0x6e, 0x9f, 0xee, 0x51, 0x66, 0xe3, 0x04, 0xcf, 0x1e, 0x50, 0xbe, 0x56,
+	0xd5, 0xe2, 0x6d, 0x1c, 0x0b, 0xdd, 0xcf, 0xa8, 0xe4, 0x9a, 0xb7, 0xcd, 0x1b, 0xb0, 0xd4, 0xb7,
+	0x43, 0x76, 0x38, 0xf0, 0x1d, 0xf7, 0xd8, 0xa5, 0x0e, 0x5f, 0xd1, 0xb0, 0x16, 0x71, 0x70, 0x4f,
+	0x8e, 0x99, 0xd7, 0x01, 0x7a, 0x01, 0xb5, 0x19, 0x75, 0x0e, 0x6d, 0xc6, 0xf7, 0xc2, 0xb0, 0xaa,
+	0x72, 0xa4, 0xc5, 0x10, 0x7c, 0x84, 0x1c, 0x1c, 0xf2, 0xd5, 0x4b, 0x7c, 0xfb, 0xaa, 0x7c, 0xa4,
+	0x8b, 0x28, 0xea, 0xa0, 0x3f, 0xa6, 0xe7, 0x92, 0x71, 0x6c, 0x9a, 0xaf, 0x40, 0x89, 0x83, 0xc3,
+	0x66, 0x65, 0x43, 0xdf, 0xac, 0xdd, 0x5e, 0xba, 0x25, 0xf6, 0xea, 0x16, 0xdf, 0x08, 0x4b, 0x02,
+	0xcd, 0x55, 0x28, 0x3d, 0xf5, 0xfb, 0xa3, 0x01, 0x6d, 0x56, 0xf9, 0xb7, 0xb2, 0x87, 0x0b, 0x3a,
+	0x6e, 0xd0, 0x04, 0xb1, 0xa0, 0xe3, 0x06, 0xb8, 0x49, 0xfe, 0x33, 0x8f, 0x06, 0xcd, 0x9a, 0xd8,
+	0x3a, 0xde, 0x49, 0x84, 0xbf, 0xa8, 0x0a, 0xdf, 0x04, 0x63, 0xe0, 0x3b, 0xb4, 0xb9, 0x24, 0xc4,
+	0x8c, 0x6d, 0xf3, 0x0d, 0x28, 0x0d, 0xfd, 0xbe, 0xdb, 0x3b, 0x6f, 0x2e, 0x6f, 0x68, 0x9b, 0xb5,
+	0xdb, 0x2b, 0x11, 0x41, 0x5d, 0xe6, 0x07, 0xf6, 0x09, 0x7d, 0xc0, 0x81, 0x96, 0x9c, 0x64, 0x36,
+	0xa1, 0xfc, 0x94, 0x06, 0xa1, 0xeb, 0x7b, 0xcd, 0x4b, 0x7c, 0xe9, 0xa8, 0x8b, 0x9c, 0x9d, 0xd9,
+	0x8c, 0x05, 0x61, 0xb3, 0x9e, 0xe6, 0xec, 0x13, 0x1c, 0xb5, 0x24, 0x90, 0x6f, 0xe8, 0xa9, 0xed,
+	0x9d, 0x88, 0x0d, 0xbd, 0x2c, 0x37, 0x54, 0x8c, 0xb4, 0x98, 0xf9, 0xdf, 0x50, 0xb3, 0x7b, 0x3d,
+	0x1a, 0x86, 0x02, 0x6e, 0x72, 0x38, 0x44, 0x43, 0x2d, 0x46, 0xfe, 0x0f, 0x8a, 0x7c, 0xc1, 0x5c,
+	0x31, 0x37, 0xa0, 0xf8, 0xd4, 0xee, 0x8f, 0x84, 0x9c, 0x17, 0x2d, 0xd1, 0x21, 0x67, 0x50, 0xef,
+	0x52, 0xc6, 0xbf, 0xda, 0xf2, 0x3d, 0x16, 0xd8, 0x3d, 0x16, 0x49, 0x46, 0x4b, 0x24, 0x23, 0xb7,
+	0x16, 0xbf, 0xac, 0x88, 0xad, 0x8d, 0x30, 0xe8, 0x79, 0x18, 0x0c, 0x05, 0x03, 0x8a, 0x2b, 0xa0,
+	0x03, 0xff, 0x29, 0xe5, 0x27, 0xa3, 0x62, 0xc9, 0x1e, 0xf9, 0x9b, 0x0e, 0xd5, 0xb6, 0x1b, 0xd0,
+	0x1e, 0xf3, 0x83, 0xf3, 0x5c, 0x8a, 0x25, 0x1d, 0x85, 0x84, 0x8e, 0x06, 0x14, 0xf1, 0xac, 0x85,
+	0x4d, 0x7d, 0x43, 0x47, 0x0c, 0xbc, 0x93, 0x88, 0xd9, 0xc8, 0x15, 0x73, 0x31, 0x4f, 0xcc, 0x25,
+	0x45, 0xcc, 0x04, 0x74, 0xbb, 0xd7, 0x6f, 0x96, 0xb9, 0x68, 0xea, 0x91, 0x68, 0x5a, 0xbd, 0x7e,
+	0xc7, 0x63, 0xc1, 0xb9, 0x85, 0x40, 0xe4, 0x62, 0x68, 0x07, 0xd4, 0x63, 0xcd, 0x8a, 0x50, 0x3a,
+	0xd1, 0x9b, 0xa8, 0x8c, 0x89, 0xea, 0xc0, 0x3c, 0xaa, 0x73, 0x03, 0x8a, 0x4f, 0x46, 0x3e, 0xb3,
+	0xb9, 0xa6, 0x2a, 0xfa, 0xf1, 0x31, 0x0e, 0x5a, 0x02, 0x66, 0xbe, 0x0a, 0xc5, 0x51, 0x68, 0x9f,
+	0x50, 0xae, 0xb8, 0x0a, 0xa5, 0x6d, 0x37, 0x78, 0x88, 0xe3, 0x96, 0x00, 0x2b, 0xda, 0xb6, 0x34,
+	0x4d, 0xdb, 0xc6, 0xce, 0xf8, 0xf2, 0xcc, 0x33, 0x7e, 0x29, 0xe7, 0x8c, 0x2b, 0x1a, 0x5b, 0xcf,
+	0x68, 0x2c, 0x79, 0x1b, 0x8a, 0x9c, 0x03, 0x14, 0xc6, 0xd1, 0x39, 0xa3, 0x61, 0x64, 0xc4, 0x78,
+	0x27, 0x11, 0xa7, 0x34, 0xb8, 0xbc, 0x43, 0x3e, 0x84, 0x4a, 0xc4, 0xd1, 0xf3, 0x7c, 0xc7, 0x0d,
+	0xb0, 0x1b, 0x84, 0xd2, 0x54, 0xf1, 0x36, 0xf9, 0xa5, 0x06, 0x66, 0x9b, 0xf6, 0x29, 0xa3, 0xbb,
+	0x8c, 0x0e, 0x54, 0x0d, 0x47, 0x7d, 0xd6, 0x12, 0x53, 0x31, 0xae, 0x6b, 0xeb, 0x50, 0x0d, 0x68,
+	0x6f, 0x14, 0x84, 0xee, 0x53, 0xa1, 0xe6, 0x15, 0x2b, 0x19, 0x40, 0xe8, 0x90, 0x06, 0x03, 0xdb,
+	0x43, 0x95, 0x30, 0x04, 0x34, 0x1e, 0xc0, 0x93, 0x2a, 0xed, 0x3e, 0x73, 0x07, 0x54, 0x6a, 0x20,
+	0x88, 0xa1, 0x03, 0x77, 0x40, 0x89, 0x03, 0x8b, 0x82, 0x2c, 0xe7, 0x2e, 0xa7, 0xfd, 0xd5, 0x88,
+	0x23, 0x2d, 0xad, 0x84, 0x91, 0xe1, 0x8e, 0x78, 0xbc, 0x09, 0x95, 0x80, 0xf6, 0xa9, 0x1d, 0x52,
+	0xa7, 0x59, 0x98, 0x30, 0x35, 0x9e, 0x41, 0x7e, 0xaa, 0x41, 0x7d, 0xcf, 0x7f, 0x9a, 0xe6, 0xfd,
+	0x2a, 0x94, 0xc3, 0xa0, 0x77, 0x98, 0xf0, 0x5f, 0x0a, 0x83, 0x5e, 0x3b, 0x77, 0x0b, 0xae, 0x42,
+	0xd9, 0x09, 0x19, 0x9f, 0x2a, 0x3c, 0x4a, 0xc9, 0x09, 0x59, 0x5b, 0x39, 0xfd, 0x86, 0x72, 0x5a,
+	0x67, 0xf2, 0xfc, 0x63, 0x0d, 0x2a, 0x5d, 0xcf, 0x1e, 0x86, 0xa7, 0x7e, 0x9e, 0x8d, 0x49, 0x4e,
+	0x52, 0x21, 0x75, 0x92, 0xf2, 0x2c, 0xcd, 0x4b, 0x50, 0x09, 0x7c, 0x5f, 0x50, 0x26, 0x8c, 0x4d,
+	0x19, 0xfb, 0x48, 0x5a, 0x5a, 0x61, 0x8b, 0x19, 0x85, 0x25, 0x87, 0x50, 0x8f, 0x68, 0x88, 0x77,
+	0x24, 0xc1, 0xac, 0xe5, 0x62, 0x2e, 0x4c, 0xe6, 0x52, 0x1f, 0xe3, 0xf2, 0x1c, 0x56, 0x70, 0xcb,
+	0x3f, 0xa2, 0x43, 0xc6, 0xdd, 0x56, 0x8c, 0x25, 0x8a, 0x19, 0x34, 0x25, 0x66, 0x98, 0x18, 0x71,
+	0x64, 0xbd, 0x38, 0xff, 0x3a, 0xf0, 0x07, 0xd2, 0xc4, 0xf1, 0xb6, 0xb9, 0x0c, 0x05, 0xe6, 0x4b,
+	0x16, 0x0b, 0xcc, 0x27, 0x6d, 0x58, 0x15, 0x4a, 0x75, 0x11, 0x0e, 0xc9, 0xaf, 0x34, 0xa8, 0xb5,
+	0xdd, 0xe3, 0x63, 0x8b, 0x3e, 0x19, 0xd1, 0x90, 0x4d, 0x88, 0x95, 0xd2, 0xd2, 0xaa, 0xaa, 0x2b,
+	0x72, 0x3a, 0xa5, 0xb4, 0x14, 0x3a, 0x85, 0xae, 0x14, 0x98, 0x9f, 0x8e, 0x8a, 0x8a, 0xd3, 0xa2,
+	0xa2, 0x52, 0x36, 0x2a, 0xfa, 0xb2, 0x80, 0x4e, 0xe3, 0xf8, 0x98, 0x5b, 0x66, 0xf3, 0x2d, 0x28,
+	0x09, 0x5b, 0xc3, 0x69, 0x5b, 0xbe, 0xdd, 0x4c, 0x2c, 0xa2, 0x9c, 0x72, 0x6b, 0x8b, 0xc3, 0x2d,
+	0x39, 0x0f, 0xc9, 0x1b, 0xda, 0xec, 0x34, 0x62, 0x18, 0xdb, 0xa8, 0x4c, 0x7e, 0xdf, 0x39, 0xe4,
+	0xe3, 0x82, 0xec, 0xb2, 0xdf, 0x77, 0x1e, 0x20, 0x48, 0xda, 0x09, 0x23, 0xf1, 0x7b, 0x2f, 0x4b,
+	0x29, 0x16, 0x37, 0xb4, 0xdc, 0xc3, 0x27, 0xe4, 0xba, 0x1a, 0x47, 0x32, 0x25, 0x1e, 0x9e, 0xc9,
+	0x5e, 0xec, 0x7d, 0xca, 0x89, 0xf7, 0x21, 0xef, 0x41, 0x49, 0x10, 0x69, 0x56, 0xa1, 0xd8, 0x6a,
+	0xb7, 0x3b, 0xed, 0xfa, 0x82, 0x59, 0x83, 0xb2, 0xd5, 0xd9, 0xbb, 0xff, 0xa8, 0xd3, 0xae, 0x6b,
+	0xe6, 0x22, 0x54, 0xf6, 0xee, 0xb7, 0x77, 0xef, 0xee, 0x76, 0xda, 0xf5, 0x82, 0x00, 0xed, 0xb7,
+	0xf6, 0x3a, 0xed, 0xba, 0x4e, 0xde, 0x83, 0x45, 0x21, 0xab, 0x70, 0xe8, 0x7b, 0x21, 0x35, 0x5f,
+	0x87, 0x32, 0xf5, 0x58, 0xe0, 0xc6, 0x96, 0xe4, 0xf2, 0xd8, 0x96, 0x58, 0xd1, 0x0c, 0xe2, 0xc2,
+	0x62, 0xe7, 0x6c, 0xe8, 0x07, 0x6c, 0x87, 0xda, 0x0e, 0x0d, 0x32, 0x5a, 0x32, 0x2e, 0xd3, 0xc2,
+	0x98, 0x4c, 0xf5, 0x58, 0xa6, 0xd3, 0x63, 0x41, 0xf2, 0x0b, 0x2d, 0xc2, 0x65, 0xd1, 0x9e, 0x1f,
+	0x38, 0xe6, 0x4d, 0x28, 0x9d, 0x72, 0xac, 0x1c, 0x57, 0xed, 0x76, 0x23, 0xa2, 0x53, 0xa5, 0xc8,
+	0x92, 0x73, 0xcc, 0xff, 0x85, 0x22, 0x12, 0x2d, 0x8c, 0x53, 0x2e, 0x53, 0x02, 0x8e, 0x2c, 0xf8,
+	0xc7, 0xc7, 0x21, 0x65, 0xf2, 0x64, 0xca, 0x5e, 0x1c, 0x9c, 0x1b, 0x49, 0x70, 0x4e, 0x76, 0x79,
+	0xe8, 0xc3, 0xfd, 0xd3, 0x94, 0xd0, 0x27, 0xf6, 0xcc, 0x85, 0xc9, 0x9e, 0x99, 0x9c, 0xc2, 0x52,
+	0xca, 0xaf, 0x9b, 0x04, 0x16, 0x03, 0x3a, 0xec, 0xbb, 0x3d, 0x9b, 0xb9, 0xbe, 0x27, 0xdc, 0xd7,
+	0x92, 0x95, 0x1a, 0xcb, 0xc4, 0xc7, 0x85, 0x6c, 0x7c, 0xdc, 0x80, 0xe2, 0x67, 0xbe, 0x27, 0x63,
+	0x9d, 0xaa, 0x25, 0x3a, 0xe4, 0x00, 0x2e, 0x77, 0x29, 0x13, 0x58, 0xa6, 0x50, 0x9d, 0x84, 0x1f,
+	0x85, 0x39, 0xc2, 0x0f, 0xf2, 0x75, 0x01, 0x4a, 0x8f, 0xd2, 0x26, 0x61, 0x7a, 0x20, 0x96, 0xe5,
+	0x4f, 0x9f, 0xc9, 0x9f, 0x91, 0xe5, 0x4f, 0xb5, 0xe1, 0xc5, 0xb4, 0x0d, 0x97, 0x01, 0x59, 0x69,
+	0x5a, 0x40, 0x16, 0x07, 0x7d, 0x65, 0x35, 0xe8, 0x7b, 0x17, 0x40, 0xc6, 0xdc, 0xae, 0x77, 0xc2,
+	0x43, 0xb5, 0x5a, 0x62, 0x15, 0x1e, 0x09, 0x88, 0x45, 0x19, 0xf5, 0x90, 0x44, 0x4b, 0x99, 0x6b,
+	0xde, 0x84, 0x22, 0x0b, 0xd0, 0xea, 0x56, 0xf9, 0x47, 0xab, 0xd1, 0x47, 0x07, 0x38, 0x98, 0x7c,
+	0x22, 0x26, 0x91, 0x1d, 0x58, 0x4e, 0x03, 0x30, 0xf8, 0xa7, 0x9e, 0x7d, 0xd4, 0xa7, 0x0e, 0xdf,
+	0xba, 0x8a, 0x15, 0x75, 0x45, 0x20, 0x21, 0xa7, 0x49, 0x43, 0x9f, 0x0c, 0x90, 0xbf, 0x6a, 0x00,
+	0x7c, 0x29, 0x61, 0xd2, 0xe6, 0xf7, 0x8b, 0xd2, 0x36, 0xe9, 0x49, 0x0c, 0x13, 0x19, 0x37, 0x43,
+	0x31, 0x6e, 0x2b, 0x50, 0x72, 0xc3, 0x78, 0x8f, 0x2b, 0x56, 0xd1, 0x0d, 0xa5, 0x97, 0x74, 0x44,
+	0xfc, 0x81, 0xc7, 0x55, 0x64, 0xa6, 0x55, 0x39, 0x22, 0xc2, 0xba, 0x08, 0x7c, 0x74, 0xde, 0x2c,
+	0xa7, 0xc0, 0x77, 0xce, 0x93, 0x40, 0xb4, 0x32, 0x35, 0x10, 0x25, 0x36, 0x34, 0x2c, 0x1a, 0x32,
+	0x3f, 0xa0, 0x9c, 0xc3, 0x99, 0xee, 0x68, 0x5c, 0xcf, 0x66, 0xba, 0xdb, 0xef, 0x80, 0xf9, 0x60,
+	0x14, 0x9c, 0xbc, 0x28, 0x02, 0xf2, 0x39, 0xd4, 0xef, 0xb9, 0x21, 0x93, 0xc2, 0x9c, 0xdf, 0xe3,
+	0x25, 0x6b, 0xa6, 0xbc, 0x99, 0x3e, 0xcd, 0x9b, 0x19, 0x59, 0x6f, 0xd6, 0x82, 0xcb, 0x0a, 0x72,
+	0x69, 0xc2, 0x6f, 0x66, 0x4d, 0xb8, 0x99, 0x52, 0xc5, 0x8c, 0x0d, 0xff, 0x1e, 0xd4, 0xb3, 0x6a,
+	0x3d, 0x45, 0x15, 0x4d, 0x30, 0x1e, 0x53, 0x3a, 0x94, 0xc6, 0x86, 0xb7, 0x31, 0xc8, 0x1b, 0xd8,
+	0x67, 0x87, 0x28, 0x4e, 0x69, 0x33, 0x07, 0xf6, 0x59, 0xeb, 0x84, 0x92, 0x1f, 0x41, 0x0d, 0xdd,
+	0x9a, 0x5c, 0x3e, 0x37, 0x7e, 0x59, 0x85, 0x92, 0x37, 0x1a, 0x1c, 0xd1, 0x40, 0xea, 0xb5, 0xec,
+	0xa1, 0x97, 0x1c, 0x50, 0x66, 0x37, 0xf5, 0xb4, 0x7e, 0x24, 0x5e, 0x12, 0xa1, 0xb3, 0x7c, 0xc6,
+	0x3d, 0x58, 0xd9, 0xf2, 0x07, 0x03, 0x97, 0x49, 0x0a, 0xa6, 0x46, 0x52, 0x19, 0x45, 0x29, 0x8c,
+	0x29, 0x0a, 0x85, 0x55, 0xa9, 0x8b, 0xf3, 0x2c, 0x37, 0x89, 0xb1, 0x99, 0xfa, 0xf8, 0x19, 0x5c,
+	0x41, 0x91, 0x4a, 0x1c, 0xe1, 0x74, 0x95, 0x8a, 0x30, 0x17, 0x14, 0xcc, 0x17, 0x50, 0xa7, 0x6d,
+	0x68, 0xa4, 0x71, 0x4b, 0x8d, 0x7a, 0x13, 0x2a, 0xd2, 0xd0, 0x45, 0x2a, 0x75, 0x45, 0x95, 0x48,
+	0xa4, 0x3f, 0xf1, 0x24, 0x72, 0x08, 0x95, 0xc8, 0xd8, 0xa6, 0xe9, 0xd1, 0x32, 0xf4, 0xc4, 0x6c,
+	0x15, 0x54, 0xb6, 0x36, 0xa0, 0x86, 0xa9, 0x90, 0x1b, 0x86, 0x8a, 0x6f, 0x50, 0x87, 0xc8, 0x17,
+	0x05, 0xa8, 0xee, 0xf8, 0x21, 0xeb, 0x32, 0x8c, 0x6d, 0xaf, 0x42, 0x19, 0xcb, 0x57, 0x09, 0x82,
+	0x12, 0x76, 0x77, 0x1d, 0x73, 0x0d, 0x2a, 0x3d, 0x7b, 0x68, 0xf7, 0x5c, 0x76, 0x2e, 0x31, 0xc4,
+	0x7d, 0xdc, 0xbb, 0x51, 0x18, 0x17, 0xa6, 0x78, 0x7b, 0x42, 0x21, 0xc0, 0x04, 0x03, 0x7d, 0x27,
+	0x37, 0x80, 0x55, 0x8b, 0xb7, 0x71, 0x2c, 0xb0, 0x7b, 0x8f, 0xb9, 0xe5, 0xab, 0x5a, 0xbc, 0x8d,
+	0x63, 0x88, 0x97, 0x9b, 0xbb, 0xaa, 0xc5, 0xdb, 0xc8, 0x3d, 0xcf, 0x91, 0x43, 0x4a, 0x3d, 0x6e,
+	0xed, 0x0c, 0xab, 0x82, 0x03, 0x5d, 0x4a, 0x3d, 0x73, 0x13, 0x8a, 0x21, 0xb3, 0x99, 0x48, 0xfd,
+	0x97, 0x93, 0x73, 0xca, 0xb9, 0xea, 0x22, 0xc4, 0x12, 0x13, 0xf0, 0x44, 0xda, 0x8e, 0x13, 0xd0,
+	0x30, 0xe4, 0xe5, 0x80, 0xaa, 0x15, 0x75, 0xc9, 0xdb, 0x50, 0xbb, 0x3f, 0xa4, 0x5e, 0xa4, 0x27,
+	0x73, 0x79, 0x5f, 0xf2, 0x55, 0x01, 0x2e, 0x6d, 0x53, 0x91, 0x5f, 0x4c, 0xd7, 0xb0, 0x89, 0x09,
+	0x06, 0xd7, 0x3b, 0x7d, 0x92, 0xde, 0x19, 0xd3, 0xf4, 0xae, 0x98, 0xd1, 0xbb, 0x38, 0x5f, 0x29,
+	0x29, 0xf9, 0xca, 0x1a, 0x54, 0x42, 0x99, 0x85, 0xc8, 0x12, 0x5f, 0xdc, 0x57, 0xeb, 0x64, 0x95,
+	0x74, 0x9d, 0xec, 0x3d, 0xa8, 0x46, 0x21, 0x04, 0x95, 0x8e, 0xf8, 0x7a, 0xb4, 0xab, 0x56, 0x04,
+	0x50, 0xd9, 0xb6, 0x92, 0xf9, 0xe4, 0x8f, 0x1a, 0x34, 0x5a, 0xc3, 0x21, 0xf5, 0x9c, 0x03, 0xff,
+	0x85, 0xb7, 0x26, 0x1d, 0x40, 0x2e, 0xa9, 0x01, 0xe4, 0xbf, 0xba, 0xe2, 0xfb, 0xfb, 0xb8, 0x56,
+	0x91, 0xa2, 0x7e, 0x3c, 0x22, 0xc8, 0x3f, 0x75, 0x31, 0x3f, 0x7a, 0x9e, 0xa8, 0x33, 0x74, 0x73,
+	0x79, 0x15, 0x15, 0x79, 0x5d, 0x80, 0xee, 0x9f, 0x6b, 0x60, 0x6e, 0x71, 0xab, 0x7d, 0x61, 0x85,
+	0x54, 0xa9, 0x9c, 0xae, 0x73, 0xd3, 0xe8, 0x25, 0x23, 0x58, 0xde, 0xa6, 0x0c, 0x6d, 0xdb, 0xbf,
+	0xd5, 0xfe, 0x3e, 0x83, 0xfa, 0x36, 0x65, 0x22, 0x8e, 0x9e, 0x89, 0x78, 0xac, 0xb2, 0x70, 0x01,
+	0xc4, 0x14, 0x4c, 0x6e, 0xf8, 0x39, 0xe6, 0x19, 0x3e, 0x27, 0x85, 0xa6, 0x30, 0x0d, 0x8d, 0x9e,
+	0x45, 0xf3, 0x3e, 0x5c, 0x49, 0xa1, 0x91, 0xee, 0x65, 0x13, 0xca, 0x22, 0x14, 0x8a, 0xbc, 0xcb,
+	0x72, 0x1c, 0x70, 0x8b, 0xad, 0x88, 0xc0, 0xe4, 0x37, 0x1a, 0xd4, 0xc4, 0x98, 0xa8, 0xee, 0xbd,
+	0x9a, 0x0a, 0xd3, 0xc6, 0x3f, 0x94, 0xd0, 0xc9, 0xf5, 0xbe, 0x38, 0xb3, 0x88, 0xee, 0x2d, 0x36,
+	0x41, 0xff, 0xd4, 0x3f, 0x6a, 0x96, 0xd2, 0x31, 0xbc, 0x95, 0xa4, 0x25, 0x1f, 0xfa, 0x47, 0x16,
+	0x4e, 0x89, 0xab, 0x85, 0x65, 0xa5, 0x5a, 0xf8, 0x5b, 0x0d, 0x96, 0xd3, 0x73, 0x27, 0x46, 0x92,
+	0xd9, 0x04, 0xa8, 0x90, 0x93, 0x00, 0xad, 0x42, 0x09, 0xcb, 0x85, 0x7e, 0x5c, 0x3d, 0x13, 0x3d,
+	0x5e, 0x3b, 0x0c, 0x7c, 0x51, 0xb5, 0x8f, 0xc2, 0x9e, 0x78, 0x00, 0x99, 0x65, 0x3e, 0xb3, 0xfb,
+	0x51, 0xdd, 0x9a, 0x77, 0x38, 0xb9, 0xe8, 0xc4, 0x4a, 0x3c, 0xa0, 0xe3, 0x6d, 0xf2, 0x17, 0x0d,
+	0x1a, 0x0f, 0x87, 0x8e, 0xcd, 0xa8, 0xd8, 0xaf, 0x29, 0xf9, 0xe0, 0x3c, 0xe4, 0xa6, 0xf3, 0x35,
+	0x3d, 0x9b, 0xaf, 0xa5, 0x53, 0x2b, 0xe3, 0x45, 0x52, 0xab, 0xe2, 0x3c, 0xa9, 0xd5, 0x26, 0x34,
+	0x84, 0x15, 0x9c, 0xc5, 0x14, 0xf9, 0x1f, 0x58, 0x7a, 0x10, 0xb8, 0x5e, 0xcf, 0x1d, 0xda, 0x7d,
+	0x54, 0x4c, 0x9c, 0xe2, 0x3a, 0x42, 0x0b, 0x0d, 0x0b, 0x9b, 0xe4, 0x0c, 0xae, 0x6c, 0x53, 0x16,
+	0x5f, 0x33, 0x4c, 0x3f, 0x1a, 0xe3, 0xe9, 0xc7, 0x85, 0x8c, 0xc1, 0x35, 0x6e, 0x0e, 0x45, 0x30,
+	0x30, 0x3a, 0x39, 0xa1, 0x21, 0xe7, 0x72, 0x16, 0x05, 0xde, 0x68, 0x20, 0xa5, 0x83, 0x4d, 0xf4,
+	0xa3, 0xf4, 0xcc, 0x0d, 0x19, 0xee, 0xb9, 0xce, 0xf9, 0x8a, 0xfb, 0x49, 0x85, 0xc0, 0x50, 0x2b,
+	0x04, 0xef, 0x43, 0x23, 0x0f, 0x31, 0xd6, 0x50, 0x3c, 0xdf, 0x19, 0x2f, 0x0c, 0xc5, 0x71, 0x98,
+	0x25, 0xe0, 0xe4, 0xcf, 0x1a, 0xac, 0xe4, 0x3a, 0xdb, 0x0b, 0xc7, 0x18, 0xab, 0x50, 0x0a, 0xfd,
+	0x51, 0xd0, 0x8b, 0xce, 0xac, 0xec, 0x7d, 0xc3, 0x0e, 0x49, 0x64, 0x39, 0x43, 0x71, 0x53, 0x53,
+	0xb1, 0x78, 0x9b, 0xfc, 0x10, 0x6a, 0xdf, 0x0d, 0x5c, 0x46, 0x2d, 0x1a, 0x8e, 0xfa, 0x3c, 0x04,
+	0x09, 0x47, 0xbd, 0x1e, 0x4d, 0x52, 0x24, 0xd9, 0x45, 0x48, 0x40, 0x07, 0xb6, 0xeb, 0x45, 0xd6,
+	0x26, 0xea, 0x26, 0xe7, 0x43, 0x29, 0xce, 0x8a, 0xf3, 0xb1, 0x83, 0x7a, 0xfb, 0x39, 0x34, 0xf6,
+	0xe9, 0xb3, 0x58, 0xd5, 0x62, 0xbd, 0xbd, 0x0e, 0x20, 0xee, 0x8a, 0x94, 0x92, 0x7b, 0x55, 0x8c,
+	0x60, 0x26, 0x7e, 0x23, 0x49, 0xe3, 0x53, 0x85, 0xad, 0x48, 0x63, 0x11, 0x9a, 0x4d, 0x3b, 0x8c,
+	0xb1, 0xb4, 0xe3, 0x63, 0x58, 0x6f, 0xf5, 0x9e, 0x8c, 0xdc, 0x80, 0xa2, 0xd7, 0xe3, 0x9c, 0xde,
+	0x53, 0x8b, 0xcf, 0xe3, 0x16, 0x61, 0x66, 0xc2, 0xf4, 0x16, 0xac, 0x5b, 0xe2, 0x22, 0x61, 0xce,
+	0x25, 0x31, 0x80, 0xb9, 0x7c, 0xe0, 0x8f, 0x7a, 0xa7, 0xf8, 0x41, 0x3c, 0x2f, 0x83, 0x48, 0xcb,
+	0x22, 0xca, 0x75, 0x86, 0xe3, 0xc5, 0x8d, 0xc4, 0x10, 0x1b, 0xd9, 0x12, 0x36, 0x2f, 0xa9, 0x16,
+	0x73, 0xef, 0x6d, 0x4b, 0xf3, 0x54, 0xbf, 0xbe, 0xd0, 0xa0, 0xb1, 0xe5, 0x7b, 0xc7, 0x6e, 0x30,
+	0x48, 0x97, 0xec, 0xaf, 0x42, 0x19, 0x8f, 0x84, 0x92, 0x98, 0x60, 0x57, 0xe4, 0x3d, 0x73, 0xaa,
+	0xfc, 0x4d, 0xd0, 0x03, 0xfa, 0x44, 0x9a, 0xcb, 0xb5, 0x88, 0x8e, 0xf1, 0x70, 0xc9, 0xc2, 0x69,
+	0x18, 0x4a, 0x5d, 0x11, 0x39, 0x6f, 0x9a, 0x90, 0xfc, 0x37, 0x00, 0xb3, 0x44, 0x88, 0x25, 0x36,
+	0x49, 0x7f, 0x28, 0x8d, 0x47, 0x59, 0x30, 0x10, 0xce, 0x1b, 0x17, 0x92, 0x33, 0xb8, 0x2a, 0x5c,
+	0xcc, 0x9d, 0x48, 0xd1, 0xbf, 0xa1, 0x0b, 0x8d, 0x99, 0x2a, 0xdd, 0x83, 0xc6, 0x41, 0x30, 0xf2,
+	0xd0, 0x08, 0xa5, 0xf4, 0x69, 0x42, 0xba, 0x2e, 0xeb, 0xed, 0x32, 0x5d, 0x17, 0xbd, 0xd9, 0xe9,
+	0xfa, 0x2e, 0x98, 0x2d, 0x7e, 0x7f, 0x3e, 0x13, 0xc5, 0xcc, 0xf3, 0xf2, 0x6b, 0x0d, 0x2e, 0x6d,
+	0x9d, 0x22, 0x30, 0x7c, 0xae, 0x9b, 0xf4, 0xcc, 0xad, 0xbe, 0x9e, 0xbd, 0xd5, 0x1f, 0xbf, 0xa7,
+	0x35, 0x72, 0xee, 0x69, 0xe7, 0xb9, 0x7d, 0x5b, 0x47, 0xab, 0x6e, 0xf7, 0x22, 0xbd, 0xe3, 0x16,
+	0x3e, 0x7c, 0x01, 0x71, 0x5e, 0x83, 0x2a, 0x5e, 0x98, 0x88, 0xf7, 0x27, 0xd2, 0x29, 0xf9, 0x7d,
+	0x07, 0x1d, 0x49, 0x88, 0x40, 0x8f, 0x3e, 0x93, 0x40, 0x43, 0x00, 0x3d, 0xfa, 0x8c, 0x03, 0xc9,
+	0x1f, 0x84, 0x6b, 0xe1, 0xd7, 0x00, 0x02, 0xfd, 0xf3, 0x07, 0xe8, 0x13, 0xf3, 0x1c, 0xae, 0x62,
+	0x86, 0xa2, 0x62, 0xf1, 0x1b, 0x99, 0xa2, 0xfa, 0x46, 0xe6, 0x02, 0x99, 0xce, 0x31, 0xac, 0x72,
+	0x4f, 0xb9, 0x43, 0xed, 0x80, 0x1d, 0x51, 0x9b, 0xa9, 0x96, 0x22, 0xbf, 0x84, 0x11, 0x95, 0x29,
+	0x0a, 0x4a, 0x99, 0x62, 0xa6, 0x56, 0x7e, 0x1f, 0x56, 0xba, 0x94, 0x25, 0x65, 0x84, 0xd9, 0x68,
+	0xe2, 0x52, 0x44, 0x61, 0x46, 0x29, 0x82, 0xbc, 0x81, 0x49, 0x66, 0x40, 0x4f, 0x38, 0x64, 0xe6,
+	0xc2, 0xe4, 0x04, 0x96, 0x52, 0xe6, 0x7f, 0x72, 0x0c, 0x20, 0x2a, 0x2f, 0x05, 0xb5, 0xf2, 0x22,
+	0xd5, 0xdf, 0x48, 0xd4, 0x1f, 0x4b, 0x93, 0x67, 0x43, 0x37, 0xa0, 0xa1, 0x54, 0xd1, 0xa8, 0x4b,
+	0x3e, 0x05, 0xb3, 0xe5, 0x3c, 0x75, 0x43, 0x3f, 0x38, 0x47, 0x3c, 0x3b, 0x7e, 0xdf, 0xa1, 0xca,
+	0x0b, 0x1e, 0x4d, 0x5d, 0xf7, 0x65, 0x69, 0xf3, 0x05, 0xb3, 0x71, 0x79, 0x11, 0xbf, 0xdb, 0xf3,
+	0x1d, 0x2a, 0xbd, 0x80, 0x82, 0x4b, 0x4f, 0xe3, 0xfa, 0x99, 0x06, 0x8b, 0x2a, 0xb2, 0x9c, 0x73,
+	0xfa, 0x0e, 0x6e, 0x08, 0x92, 0x10, 0xca, 0x7b, 0xf6, 0xd8, 0x76, 0x8f, 0x53, 0x69, 0x45, 0x53,
+	0xf1, 0xab, 0x67, 0xb6, 0xcb, 0x68, 0x20, 0xce, 0xc5, 0x8c, 0xaf, 0xe4, 0x54, 0xf2, 0xa5, 0x06,
+	0x8b, 0x33, 0xbc, 0xf5, 0x7c, 0x1c, 0xd7, 0x41, 0x67, 0xac, 0x2f, 0xb9, 0xc5, 0xe6, 0x4c, 0x2b,
+	0x8b, 0xfa, 0x89, 0x64, 0xc8, 0xdb, 0x01, 0xde, 0x26, 0x04, 0x96, 0x1f, 0x7a, 0xfd, 0xe9, 0xbe,
+	0xfe, 0x77, 0x1a, 0x2c, 0xc5, 0x41, 0x0a, 0xbe, 0x2f, 0x30, 0x6f, 0x83, 0xc1, 0xce, 0x87, 0xd1,
+	0x55, 0xec, 0x7f, 0x8d, 0x45, 0x32, 0x38, 0xe9, 0x16, 0xfe, 0x1c, 0x9c, 0x0f, 0xa9, 0xc5, 0xe7,
+	0xc6, 0xb7, 0xa9, 0x85, 0xa9, 0xb7, 0xa9, 0xf3, 0x84, 0x48, 0xe4, 0x3a, 0x54, 0xa2, 0xc5, 0xcd,
+	0x0a, 0x18, 0x77, 0x77, 0xef, 0x75, 0xea, 0x0b, 0x66, 0x19, 0xf4, 0xf6, 0xae, 0x55, 0xd7, 0xc8,
+	0xdf, 0x35, 0x58, 0xc1, 0x1c, 0x21, 0xf9, 0x2a, 0x4a, 0x5f, 0xe7, 0xbb, 0xf0, 0x4a, 0x52, 0x55,
+	0x7d, 0x6a, 0xaa, 0xfa, 0x3a, 0x14, 0x5d, 0x46, 0x07, 0xc2, 0xf4, 0x29, 0x91, 0x47, 0x6a, 0x1b,
+	0x2c, 0x31, 0x27, 0x62, 0xac, 0x38, 0x35, 0xf6, 0xbb, 0xa9, 0x54, 0xd2, 0x4a, 0xe9, 0x7d, 0x8a,
+	0xee, 0xf9, 0x93, 0xda, 0x1a, 0xf9, 0x4a, 0x13, 0x45, 0xe0, 0x39, 0x53, 0x9e, 0xbc, 0xfb, 0xf0,
+	0x17, 0x4f, 0x7a, 0xd0, 0x0f, 0xcb, 0x97, 0x47, 0xf2, 0xad, 0x97, 0xe8, 0x91, 0x9f, 0x68, 0x50,
+	0x43, 0x2b, 0xf4, 0x1f, 0x41, 0xcc, 0x36, 0x2c, 0x6d, 0x9d, 0x0e, 0x7c, 0xe7, 0x79, 0xdf, 0xbb,
+	0xf1, 0x03, 0xa8, 0x2b, 0x37, 0xf7, 0x87, 0xb8, 0x90, 0xff, 0xcc, 0x7b, 0xae, 0x85, 0x62, 0x8b,
+	0xa6, 0xe7, 0x3e, 0x56, 0x33, 0x94, 0x0d, 0x21, 0x87, 0x50, 0x7d, 0x18, 0xd2, 0x60, 0x1b, 0x3b,
+	0x78, 0xe3, 0x1e, 0x5b, 0xe7, 0x82, 0xeb, 0x4c, 0x30, 0xb9, 0x4d, 0x28, 0x0f, 0xe8, 0xe0, 0x28,
+	0xb2, 0x40, 0x86, 0x15, 0x75, 0xf3, 0xde, 0xec, 0xa0, 0x5c, 0x30, 0x3f, 0x8e, 0x91, 0x4c, 0x97,
+	0x8f, 0xa0, 0xa0, 0x10, 0x53, 0x90, 0xf7, 0x32, 0xe7, 0xc5, 0xcb, 0xc8, 0xe4, 0x07, 0xb0, 0xdc,
+	0xa5, 0xac, 0xd5, 0xeb, 0x4f, 0xd9, 0xcf, 0xf4, 0x25, 0x5c, 0x45, 0x29, 0xc7, 0xf0, 0x1b, 0x63,
+	0x7d, 0xca, 0x8d, 0x31, 0xa9, 0x42, 0x79, 0xdf, 0x67, 0xa7, 0xae, 0x77, 0xf2, 0xda, 0x3e, 0x40,
+	0xe2, 0x19, 0x4d, 0x80, 0xd2, 0xfd, 0xfd, 0x7b, 0xbb, 0xfb, 0x1d, 0xf1, 0xf0, 0xa2, 0xfb, 0xb0,
+	0xfb, 0xa0, 0xb3, 0x75, 0x50, 0xd7, 0xd0, 0x8e, 0xb4, 0x3b, 0x2d, 0x7c, 0x74, 0x71, 0x09, 0x6a,
+	0x7b, 0xad, 0xdd, 0xfd, 0x83, 0xce, 0x7e, 0x6b, 0x7f, 0xab, 0x53, 0xd7, 0xf1, 0x4d, 0x46, 0xdb,
+	0x6a, 0xed, 0xee, 0xef, 0xee, 0x6f, 0xd7, 0x8d, 0xd7, 0x5e, 0x81, 0x4a, 0x64, 0x8a, 0x71, 0xb5,
+	0xee, 0x4e, 0xcb, 0xe2, 0xcf, 0x38, 0x96, 0xa0, 0xda, 0xf9, 0x64, 0xeb, 0xde, 0xc3, 0xee, 0xee,
+	0xa3, 0x4e, 0x5d, 0xbb, 0xfd, 0x27, 0x00, 0xe3, 0xc1, 0xd6, 0xdd, 0xae, 0xf9, 0x2e, 0x54, 0xa2,
+	0xfa, 0xbd, 0x79, 0x35, 0xa2, 0x36, 0x53, 0xd1, 0x5f, 0xbb, 0x9c, 0x7a, 0xfe, 0x8a, 0xef, 0x96,
+	0xc9, 0x82, 0xf9, 0x0e, 0x54, 0xba, 0xd1, 0x97, 0xe3, 0x13, 0xd6, 0xe2, 0x8b, 0x1d, 0x25, 0xd9,
+	0x25, 0x0b, 0xe6, 0xb7, 0xa0, 0x26, 0x0b, 0xa2, 0xfc, 0x15, 0xf0, 0xaa, 0x82, 0x52, 0xa9, 0x92,
+	0xae, 0x8d, 0x19, 0x60, 0xb2, 0x60, 0xfe, 0x3f, 0x54, 0xe3, 0xa2, 0xa6, 0xd9, 0x54, 0x3e, 0x4c,
+	0xd5, 0x39, 0xd7, 0x32, 0xf6, 0x90, 0x2c, 0x98, 0x1f, 0xc0, 0xa2, 0x5a, 0x7a, 0x31, 0xaf, 0x29,
+	0xdf, 0x66, 0xad, 0xd3, 0xda, 0xb8, 0xf1, 0x23, 0x0b, 0xe6, 0x3e, 0x2c, 0xa5, 0x4c, 0x99, 0xb9,
+	0x1e, 0xfb, 0xbd, 0x1c, 0x0b, 0xb7, 0x76, 0x7d, 0x02, 0x54, 0xd8, 0x79, 0xb2, 0x60, 0xb6, 0x61,
+	0x29, 0x75, 0x3f, 0x90, 0xac, 0x97, 0x77, 0x6d, 0x30, 0x69, 0x2f, 0x3f, 0x80, 0x9a, 0x92, 0xbe,
+	0x99, 0x53, 0x72, 0xba, 0x29, 0x2b, 0x28, 0x75, 0xfe, 0x64, 0x85, 0xf1, 0xe2, 0xff, 0xa4, 0x15,
+	0x3e, 0x81, 0xcb, 0xb2, 0xb2, 0x93, 0x94, 0x7a, 0xcc, 0x1b, 0x29, 0x75, 0xc8, 0xaf, 0x3b, 0xad,
+	0xad, 0x4f, 0x9b, 0x44, 0x16, 0xcc, 0xbb, 0x49, 0x05, 0x54, 0x92, 0x37, 0xfd, 0x02, 0x66, 0x12,
+	0x85, 0x5b, 0xbc, 0x16, 0x9e, 0x0e, 0x1d, 0x27, 0xa9, 0xdd, 0x8a, 0xaa, 0x76, 0xf1, 0x74, 0xb2,
+	0x60, 0xee, 0x40, 0x4d, 0x29, 0x38, 0x27, 0x1b, 0x35, 0x5e, 0xec, 0x5e, 0xbb, 0x96, 0x0b, 0x8b,
+	0x45, 0xdf, 0xe2, 0x37, 0x02, 0x6a, 0xed, 0x79, 0xb2, 0x2a, 0x5f, 0x49, 0xab, 0x32, 0x9f, 0x4e,
+	0x16, 0xcc, 0x6f, 0x63, 0xc0, 0x73, 0x7c, 0x1c, 0xf9, 0xdc, 0xd0, 0xbc, 0xa2, 0xbe, 0x42, 0x8a,
+	0x3e, 0x6e, 0xa4, 0x07, 0x63, 0x02, 0x3e, 0x82, 0x45, 0xf5, 0x6e, 0xd6, 0x4c, 0xd3, 0x9b, 0xbe,
+	0x2d, 0x5e, 0x5b, 0xcf, 0x07, 0xc6, 0x8b, 0xdd, 0x81, 0x6a, 0xfc, 0x6e, 0x20, 0x61, 0x24, 0xfb,
+	0x8e, 0x61, 0xed, 0xa5, 0x1c, 0x48, 0xbc, 0xc6, 0x3b, 0x60, 0xa0, 0xf5, 0x4b, 0xb8, 0x50, 0xfc,
+	0xf3, 0x5a, 0x7e, 0xd8, 0x12, 0x1f, 0xea, 0xc4, 0x29, 0xa9, 0x87, 0x3a, 0xeb, 0x45, 0x92, 0x43,
+	0x1d, 0x43, 0x62, 0x05, 0x53, 0x32, 0xc0, 0x94, 0x82, 0x8d, 0x67, 0x86, 0x13, 0x14, 0xec, 0xa8,
+	0xc4, 0xff, 0xdf, 0xf1, 0xf6, 0x3f, 0x07, 0x00, 0xa0, 0xf7, 0xfa, 0xc6, 0xf2, 0x31, 0x00, 0x00,
 }
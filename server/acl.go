@@ -0,0 +1,140 @@
+package server
+
+import (
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// ACL entries grant permissions on a volume or a directory subtree to a client or a user group
+// Entries are inherited by everything below, the effective grants are the union along the path to the volume
+// An access is allowed when either mode bits or ACL grants allow it
+// Admin implies all other permissions and allows editing ACLs
+
+const (
+	ACL_READ  = uint32(1)
+	ACL_WRITE = uint32(2)
+	ACL_LIST  = uint32(4)
+	ACL_ADMIN = uint32(8)
+)
+
+// directories are not nested deeper than this, it stops walking broken parent chains
+const maxDirDepth = 1024
+
+func matchAcl(txn *badger.Txn, group uint64, acl []*pb.AclEntry, clientId uint64) uint32 {
+	var grants uint32
+	for _, entry := range acl {
+		if (entry.ClientId != 0 && entry.ClientId == clientId) || inUserGroup(txn, group, entry.Group, clientId) {
+			grants |= entry.Permissions
+		}
+	}
+	return grants
+}
+
+// directories created before ACLs existed have no parent, inheritance stops at them
+func AclGrants(txn *badger.Txn, group uint64, dirKey []byte, clientId uint64) uint32 {
+	var grants uint32
+	var volumeKey []byte
+	key := dirKey
+	for depth := 0; len(key) > 0 && depth < maxDirDepth; depth++ {
+		dir, err := GetDirectory(txn, group, key)
+		if err != nil {
+			break
+		}
+		grants |= matchAcl(txn, group, dir.Acl, clientId)
+		if len(dir.Parent) == 0 {
+			// root dir of a volume shares the key with the volume
+			volumeKey = dir.Volume
+			if len(volumeKey) == 0 {
+				volumeKey = dir.Key
+			}
+			break
+		}
+		key = dir.Parent
+	}
+	if len(volumeKey) > 0 {
+		if volume, err := GetVolume(txn, group, volumeKey); err == nil {
+			grants |= matchAcl(txn, group, volume.Acl, clientId)
+		}
+	}
+	if grants&ACL_ADMIN != 0 {
+		grants = ACL_READ | ACL_WRITE | ACL_LIST | ACL_ADMIN
+	}
+	return grants
+}
+
+// mode permissions wanted on a directory mapped to ACL permissions, traversal is granted by any of them
+func dirAclAllows(grants uint32, want uint32) bool {
+	if want&PERM_READ != 0 && grants&ACL_LIST == 0 {
+		return false
+	}
+	if want&PERM_WRITE != 0 && grants&ACL_WRITE == 0 {
+		return false
+	}
+	if want&PERM_EXEC != 0 && grants&(ACL_LIST|ACL_READ|ACL_WRITE) == 0 {
+		return false
+	}
+	return true
+}
+
+func fileAclAllows(grants uint32, want uint32) bool {
+	if want&PERM_READ != 0 && grants&ACL_READ == 0 {
+		return false
+	}
+	if want&(PERM_WRITE|PERM_EXEC) != 0 && grants&ACL_WRITE == 0 {
+		return false
+	}
+	return true
+}
+
+func canAdminDir(txn *badger.Txn, group uint64, dir *pb.Directory, clientId uint64) bool {
+	return dir.Owner == clientId || AclGrants(txn, group, dir.Key, clientId)&ACL_ADMIN != 0
+}
+
+func (s *PCFSServer) smSetAcl(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.SetAclContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode set acl contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if contract.Volume {
+			volume, err := GetVolume(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			rootDir, err := GetDirectory(txn, group, volume.RootDir)
+			if err != nil {
+				return err
+			}
+			if !canAdminDir(txn, group, rootDir, clientId) {
+				return errors.New("only owner or admin can change volume acl")
+			}
+			volume.Acl = contract.Acl
+			return SetVolume(txn, group, volume)
+		}
+		dir, err := GetDirectory(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if !canAdminDir(txn, group, dir, clientId) {
+			return errors.New("only owner or admin can change dir acl")
+		}
+		dir.Acl = contract.Acl
+		if dir.ChangedAt, err = LogClock(txn, group); err != nil {
+			return err
+		}
+		return SetDirectory(txn, group, dir)
+	}); err == nil {
+		log.Println("acl set")
+		return []byte{1}
+	} else {
+		log.Println("cannot set acl:", err)
+		return []byte{0}
+	}
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Block RPCs are signed by the writer with it's BFTRaft node key
+// The signature covers the encoded request with the signature field left empty
+// Contracts are already signed by BFTRaft, their caller is entry.Command.ClientId
+// With mutual TLS, the client certificate must belong to the node the request claims to be from
+
+func signData(req proto.Message, signature *[]byte) ([]byte, error) {
+	orig := *signature
+	*signature = nil
+	data, err := proto.Marshal(req)
+	*signature = orig
+	return data, err
+}
+
+func (s *PCFSServer) SignRequest(req proto.Message, signature *[]byte) error {
+	data, err := signData(req, signature)
+	if err != nil {
+		return err
+	}
+	*signature = utils.Sign(s.BFTRaft.PrivateKey, data)
+	return nil
+}
+
+func (s *PCFSServer) VerifyRequest(clientId uint64, req proto.Message, signature *[]byte) error {
+	if len(*signature) == 0 {
+		return errors.New("request not signed")
+	}
+	host := s.BFTRaft.GetHostNTXN(clientId)
+	if host == nil {
+		return errors.New(fmt.Sprint("unknown client: ", clientId))
+	}
+	publicKey, err := utils.ParsePublicKey(host.PublicKey)
+	if err != nil {
+		return err
+	}
+	data, err := signData(req, signature)
+	if err != nil {
+		return err
+	}
+	return utils.VerifySign(publicKey, *signature, data)
+}
+
+// stash nodes have no file meta data, they ask the group who is holding the write lease
+func (s *PCFSServer) majorityWriteLock(group uint64, file []byte) *pb.FileWriteLock {
+	lockI := s.GroupMajorityResponse(group, func(client pb.PCFSClient) (interface{}, []byte) {
+		lock, err := client.GetFileWriteLock(context.Background(), &pb.GetFileRequest{
+			Group: group,
+			File:  file,
+		})
+		if err != nil {
+			return nil, []byte{}
+		}
+		feature, _ := proto.Marshal(lock)
+		return lock, feature
+	})
+	if lockI == nil {
+		return nil
+	}
+	return lockI.(*pb.FileWriteLock)
+}
+
+func (s *PCFSServer) CheckWriteLease(group uint64, file []byte, clientId uint64) error {
+	lock := s.majorityWriteLock(group, file)
+	if lock == nil {
+		return errors.New("file is not locked for writing")
+	}
+	if lock.Owner != clientId {
+		return errors.New("client does not hold the write lease")
+	}
+	return nil
+}
+
+// someone else may be writing the file, it's blocks can change under the caller
+func (s *PCFSServer) WriteLeasedByOther(group uint64, file []byte, clientId uint64) bool {
+	lock := s.majorityWriteLock(group, file)
+	return lock != nil && lock.Owner != clientId
+}
+
+// replica repair may push a block without the lease, if it's content is what file meta data recorded
+func (s *PCFSServer) matchesRecordedHash(block *pb.BlockData) bool {
+	meta, err := s.GetMajorityFileMeta(block.Group, block.File)
+	if err != nil || block.Index >= uint64(len(meta.Blocks)) {
+		return false
+	}
+	expected := meta.Blocks[block.Index].Hash
+	hash, _ := utils.SHA1Hash(block.Data)
+	return len(expected) > 0 && bytes.Equal(expected, hash)
+}
+
+func (s *PCFSServer) authorizeBlockWrite(ctx context.Context, block *pb.BlockData) error {
+	if err := s.CheckPeer(ctx, block.ClientId); err != nil {
+		log.Println("rejected block write from", block.ClientId, ":", err)
+		return err
+	}
+	if err := s.VerifyRequest(block.ClientId, block, &block.Signature); err != nil {
+		log.Println("rejected unsigned or forged block write from", block.ClientId, ":", err)
+		return err
+	}
+	if err := s.CheckWriteLease(block.Group, block.File, block.ClientId); err != nil {
+		if s.matchesRecordedHash(block) {
+			return nil
+		}
+		log.Println("rejected block write without lease from", block.ClientId, ":", err)
+		return err
+	}
+	return nil
+}
+
+// identity of the caller of a read RPC, from the client certificate with mutual TLS or from the request signature
+func (s *PCFSServer) CallerIdentity(ctx context.Context, clientId uint64, req proto.Message, signature *[]byte) (uint64, error) {
+	if err := s.CheckPeer(ctx, clientId); err != nil {
+		return 0, err
+	}
+	if _, verified := PeerIdentity(ctx); !verified {
+		if err := s.VerifyRequest(clientId, req, signature); err != nil {
+			return 0, err
+		}
+	}
+	return clientId, nil
+}
+
+// replicas are pulled on a signed request of the stash leader, or of a stash holding the block
+// the pulling stash passes the request on, so the source lets it read the block
+func (s *PCFSServer) checkReplication(req *pb.ReplicateBlockRequest, group uint64, file []byte, index uint64, hosts []uint64) error {
+	if req == nil || req.Group != group || req.Index != index || !bytes.Equal(req.File, file) {
+		return errors.New("replication request not match the block")
+	}
+	if err := s.VerifyRequest(req.ClientId, req, &req.Signature); err != nil {
+		return err
+	}
+	if req.ClientId == s.StashLeader() || (containsHost(hosts, req.ClientId) && s.isStash(req.ClientId)) {
+		return nil
+	}
+	// the writer restoring a version has listed hosts put back the content they kept aside
+	if req.Source == s.BFTRaft.Id && containsHost(hosts, req.Source) && s.CheckWriteLease(group, file, req.ClientId) == nil {
+		return nil
+	}
+	return errors.New("replication not requested by the stash leader, a holder of the block or it's writer")
+}
+
+// kept copies are only moved by the stash holding them, to a stash while snapshots or versions have the content
+func (s *PCFSServer) checkKeptReplication(req *pb.ReplicateBlockRequest) error {
+	if err := s.VerifyRequest(req.ClientId, req, &req.Signature); err != nil {
+		return err
+	}
+	if req.ClientId != req.Source || !s.isStash(req.ClientId) {
+		return errors.New("kept copy not replicated by it's holder")
+	}
+	return s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		if refs, err := BlockRefs(txn, req.Group, req.File, req.Index, req.Hash); err != nil {
+			return err
+		} else if refs == 0 {
+			return errors.New("content is not kept")
+		}
+		return nil
+	})
+}
+
+// the node itself and the stash leader can read any block, stash nodes the blocks they hold or pull
+// Drones are clients and stash nodes at the same time, other blocks need read permission on the file
+func (s *PCFSServer) checkBlockRead(req *pb.GetBlockRequest, clientId uint64) error {
+	if clientId == s.BFTRaft.Id || clientId == s.StashLeader() {
+		return nil
+	}
+	// kept copies this node moves to other stashes
+	if replicate := req.Replicate; replicate != nil && replicate.Kept {
+		if replicate.ClientId != s.BFTRaft.Id || replicate.Group != req.Group || replicate.Index != req.Index ||
+			!bytes.Equal(replicate.File, req.File) || !bytes.Equal(replicate.Hash, req.Hash) {
+			return errors.New("kept copy not moved by this node")
+		}
+		return s.VerifyRequest(replicate.ClientId, replicate, &replicate.Signature)
+	}
+	if req.Version != 0 {
+		return s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+			version, err := GetFileVersion(txn, req.Group, req.File, req.Version)
+			if err != nil {
+				return err
+			}
+			if req.Index >= uint64(len(version.Meta.Blocks)) || !bytes.Equal(version.Meta.Blocks[req.Index].Hash, req.Hash) {
+				return errors.New("block is not in the version")
+			}
+			return CheckFileAccess(txn, req.Group, version.Meta, clientId, PERM_READ)
+		})
+	}
+	if len(req.Snapshot) > 0 {
+		return s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+			file, err := GetSnapshotFile(txn, req.Group, req.Snapshot, req.File)
+			if err != nil {
+				return err
+			}
+			if req.Index >= uint64(len(file.Blocks)) || !bytes.Equal(file.Blocks[req.Index].Hash, req.Hash) {
+				return errors.New("block is not in the snapshot")
+			}
+			return CheckFileAccess(txn, req.Group, file, clientId, PERM_READ)
+		})
+	}
+	meta, err := s.GetMajorityFileMeta(req.Group, req.File)
+	if err != nil {
+		return err
+	}
+	if req.Index < uint64(len(meta.Blocks)) {
+		hosts := meta.Blocks[req.Index].Hosts
+		if containsHost(hosts, clientId) {
+			return nil
+		}
+		if req.Replicate != nil && s.checkReplication(req.Replicate, req.Group, req.File, req.Index, hosts) == nil {
+			return nil
+		}
+	}
+	return s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		return CheckFileAccess(txn, req.Group, meta, clientId, PERM_READ)
+	})
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"log"
+	"sort"
+	"time"
+)
+
+// Balancer moves blocks from over-utilized stashes to under-utilized ones
+// A stash is over-utilized when it's utilization (used / capacity) exceeds the cluster average by the threshold
+// Every move copies the block to the target, replaces the replica in the block meta data by the contract
+// and then deletes the block from the source
+// Moves never reduce the number of zones and racks replicas of the block spread across
+
+func (s *PCFSServer) StartBalancer(config FileConfig) {
+	if !config.Balancer {
+		return
+	}
+	interval := ParseDuration(config.BalanceInterval, 10*time.Minute)
+	throttle := NewThrottle(ParseBandwidth(config.BalanceBandwidth, 10*1024*1024))
+	log.Println("start balancer every", interval)
+	go func() {
+		for true {
+			time.Sleep(interval)
+			if s.IsStashLeader() {
+				if _, err := s.BalanceStashes(config.BalanceThreshold, throttle); err != nil {
+					log.Println("balance round failed:", err)
+				}
+			}
+		}
+	}()
+}
+
+func utilization(stash *pb.HostStash) float64 {
+	if stash.Capacity == 0 {
+		return 1
+	}
+	return float64(stash.Used) / float64(stash.Capacity)
+}
+
+// BalanceStashes returns the number of moved blocks
+func (s *PCFSServer) BalanceStashes(threshold float64, throttle *Throttle) (int, error) {
+	if threshold <= 0 {
+		threshold = 0.1
+	}
+	var stashes []*pb.HostStash
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		var err error
+		stashes, err = ListHostStashes(txn, STASH_GROUP)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	if len(stashes) < 2 {
+		return 0, nil
+	}
+	blocks, err := s.scanBlockReplicas()
+	if err != nil {
+		return 0, err
+	}
+	stashMap := map[uint64]*pb.HostStash{}
+	var used, capacity uint64
+	for _, stash := range stashes {
+		stashMap[stash.HostId] = stash
+		used += stash.Used
+		capacity += stash.Capacity
+	}
+	if capacity == 0 {
+		return 0, errors.New("cluster has no capacity")
+	}
+	average := float64(used) / float64(capacity)
+	hostBlocks := map[uint64][]*blockReplicas{}
+	for _, block := range blocks {
+		for _, hostId := range block.hosts {
+			hostBlocks[hostId] = append(hostBlocks[hostId], block)
+		}
+	}
+	states := s.StashStates()
+	moved := 0
+	for true {
+		sort.SliceStable(stashes, func(i, j int) bool {
+			return utilization(stashes[i]) > utilization(stashes[j])
+		})
+		source := stashes[0]
+		if utilization(source) <= average+threshold {
+			break
+		}
+		if states[source.HostId] != pb.StashState_ONLINE {
+			log.Println("over-utilized stash", source.HostId, "is not online, skip balancing")
+			break
+		}
+		if !s.moveOneBlock(source, stashes, stashMap, hostBlocks, average, states, throttle) {
+			log.Println("no block can be moved from", source.HostId)
+			break
+		}
+		moved++
+	}
+	log.Println("balance finished, moved", moved, "blocks, average utilization:", average)
+	return moved, nil
+}
+
+// try targets from the least utilized one
+func (s *PCFSServer) moveOneBlock(
+	source *pb.HostStash, stashes []*pb.HostStash, stashMap map[uint64]*pb.HostStash,
+	hostBlocks map[uint64][]*blockReplicas, average float64, states map[uint64]pb.StashState, throttle *Throttle,
+) bool {
+	for i := len(stashes) - 1; i > 0; i-- {
+		target := stashes[i]
+		if utilization(target) >= average || states[target.HostId] != pb.StashState_ONLINE {
+			continue
+		}
+		for bi, block := range hostBlocks[source.HostId] {
+			if containsHost(block.hosts, target.HostId) || target.Capacity-target.Used < uint64(block.size) ||
+				!InZones(block.zones, target) {
+				continue
+			}
+			newHosts := []uint64{}
+			before := []*pb.HostStash{}
+			after := []*pb.HostStash{}
+			for _, hostId := range block.hosts {
+				if stash, found := stashMap[hostId]; found {
+					before = append(before, stash)
+				}
+				if hostId == source.HostId {
+					newHosts = append(newHosts, target.HostId)
+					after = append(after, target)
+				} else {
+					newHosts = append(newHosts, hostId)
+					if stash, found := stashMap[hostId]; found {
+						after = append(after, stash)
+					}
+				}
+			}
+			if !KeepsSpread(before, after) {
+				continue
+			}
+			if err := s.moveBlock(block, source.HostId, target.HostId, newHosts, throttle); err != nil {
+				log.Println("cannot move block", block.index, "of file", block.file, ":", err)
+				continue
+			}
+			block.hosts = newHosts
+			hostBlocks[source.HostId] = append(hostBlocks[source.HostId][:bi], hostBlocks[source.HostId][bi+1:]...)
+			hostBlocks[target.HostId] = append(hostBlocks[target.HostId], block)
+			if source.Used > uint64(block.size) {
+				source.Used -= uint64(block.size)
+			} else {
+				source.Used = 0
+			}
+			target.Used += uint64(block.size)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PCFSServer) moveBlock(block *blockReplicas, source uint64, target uint64, newHosts []uint64, throttle *Throttle) error {
+	if err := s.copyBlock(block, source, target, throttle); err != nil {
+		return err
+	}
+	if err := s.replaceReplicas(block.file, block.index, block.hosts, newHosts); err != nil {
+		return err
+	}
+	s.deleteReplica(block, source)
+	return nil
+}
+
+// best effort, the block is not referenced by the file meta data anymore
+func (s *PCFSServer) deleteReplica(block *blockReplicas, hostId uint64) {
+	host := s.BFTRaft.GetHostNTXN(hostId)
+	if host == nil {
+		log.Println("cannot find host to delete replica:", hostId)
+		return
+	}
+	client := s.GetPeerRPC(host)
+	if client == nil {
+		return
+	}
+	req := &pb.DeleteBlockRequest{
+		Group:    STASH_GROUP,
+		Index:    block.index,
+		File:     block.file,
+		ClientId: s.BFTRaft.Id,
+	}
+	if err := s.SignRequest(req, &req.Signature); err != nil {
+		log.Println("cannot sign delete block request:", err)
+		return
+	}
+	if _, err := client.DeleteBlock(context.Background(), req); err != nil {
+		log.Println("cannot delete replica from", hostId, ":", err)
+	}
+}
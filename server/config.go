@@ -2,11 +2,51 @@ package server
 
 import (
 	"encoding/json"
+	"github.com/c2h5oh/datasize"
 	"io/ioutil"
+	"time"
 )
 
 type FileConfig struct {
 	Capacity string
+	// failure domain labels, replicas of a block are spread across them
+	Zone string
+	Rack string
+	Host string
+	// how often the stash group leader looks for under-replicated blocks, like "1m"
+	RepairInterval string
+	// bytes per second repair can copy between stash nodes, like "10MB"
+	RepairBandwidth string
+	// run the balancer in background, it can also be started by the balance command
+	Balancer         bool
+	BalanceInterval  string
+	BalanceBandwidth string
+	// allowed difference between stash utilization and the cluster average, like 0.1
+	BalanceThreshold float64
+	// how often the scrubber re-hashes local blocks, like "24h"
+	ScrubInterval string
+	// stash nodes report liveness and disk usage every heartbeat interval, like "10s"
+	// a silent stash turns suspect after suspect timeout and dead after dead timeout
+	HeartbeatInterval string
+	SuspectTimeout    string
+	DeadTimeout       string
+	// octal permission bits removed from new files and directories, like "022"
+	Umask string
+	// record access times of files read by this node, at most once a day for a file not changed since
+	Atime bool
+	// PCFS services are served with TLS when a certificate is set
+	TLS TLSConfig
+}
+
+type TLSConfig struct {
+	// address of the TLS endpoint, peers find it in the host stash
+	Address string
+	Cert    string
+	Key     string
+	// required with TLS, certificates of both ends are verified against it
+	CA string
+	// how often certificate files are checked for rotation, like "1m"
+	ReloadInterval string
 }
 
 func ReadConfigFile(path string) FileConfig {
@@ -20,3 +60,25 @@ func ReadConfigFile(path string) FileConfig {
 	}
 	return fc
 }
+
+func ParseDuration(text string, defaultValue time.Duration) time.Duration {
+	if text == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func ParseBandwidth(text string, defaultValue uint64) uint64 {
+	if text == "" {
+		return defaultValue
+	}
+	var c datasize.ByteSize
+	if err := c.UnmarshalText([]byte(text)); err != nil {
+		panic(err)
+	}
+	return c.Bytes()
+}
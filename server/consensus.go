@@ -6,18 +6,12 @@ import (
 	"github.com/PomeloCloud/BFTRaft4go/utils"
 	pb "github.com/PomeloCloud/pcfs/proto"
 	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/status"
 	"log"
 	"time"
 )
 
-func GetPeerRPC(addr string) pb.PCFSClient {
-	conn, err := utils.GetClientConn(addr)
-	if err != nil {
-		log.Println("cannot get peer rpc:", err)
-		return nil
-	}
-	return pb.NewPCFSClient(conn)
-}
+var ErrFileNotFound = errors.New("file not found")
 
 func (s *PCFSServer) GroupMajorityResponse(group uint64, f func(client pb.PCFSClient) (interface{}, []byte)) interface{} {
 	hosts := s.BFTRaft.Client.GetGroupHosts(group)
@@ -28,7 +22,7 @@ func (s *PCFSServer) GroupMajorityResponse(group uint64, f func(client pb.PCFSCl
 	}
 	clients := []pb.PCFSClient{}
 	for _, h := range *hosts {
-		c := GetPeerRPC(h.ServerAddr)
+		c := s.GetPeerRPC(h)
 		if c != nil {
 			clients = append(clients, c)
 		}
@@ -79,12 +73,22 @@ func (s *PCFSServer) GroupMajorityResponse(group uint64, f func(client pb.PCFSCl
 	return nil
 }
 
+func (s *PCFSServer) signedFileRequest(group uint64, file []byte) (*pb.GetFileRequest, error) {
+	req := &pb.GetFileRequest{
+		Group:    group,
+		File:     file,
+		ClientId: s.BFTRaft.Id,
+	}
+	return req, s.SignRequest(req, &req.Signature)
+}
+
 func (s *PCFSServer) GetMajorityFileMeta(group uint64, file []byte) (*pb.FileMeta, error) {
+	req, err := s.signedFileRequest(group, file)
+	if err != nil {
+		return nil, err
+	}
 	fileData := s.GroupMajorityResponse(group, func(client pb.PCFSClient) (interface{}, []byte) {
-		file, err := client.GetFileMeta(context.Background(), &pb.GetFileRequest{
-			Group: group,
-			File:  file,
-		})
+		file, err := client.GetFileMeta(context.Background(), req)
 		if err != nil {
 			log.Println("cannot get file meta for create block")
 			return nil, []byte{}
@@ -106,3 +110,40 @@ func (s *PCFSServer) GetMajorityFileMeta(group uint64, file []byte) (*pb.FileMet
 		return meta, nil
 	}
 }
+
+// Unlike the majority response, members which cannot answer count against it
+// the block is unreferenced when most members have no such file, or their file meta data doesn't list the host for it
+func (s *PCFSServer) BlockUnreferenced(group uint64, file []byte, index uint64, hostId uint64) bool {
+	req, err := s.signedFileRequest(group, file)
+	if err != nil {
+		return false
+	}
+	hosts := s.BFTRaft.Client.GetGroupHosts(group)
+	if hosts == nil || len(*hosts) == 0 {
+		return false
+	}
+	answers := make(chan bool, len(*hosts))
+	for _, h := range *hosts {
+		go func(c pb.PCFSClient) {
+			if c == nil {
+				answers <- false
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			meta, err := c.GetFileMeta(ctx, req)
+			if err != nil {
+				answers <- status.Convert(err).Message() == ErrFileNotFound.Error()
+				return
+			}
+			answers <- index >= uint64(len(meta.Blocks)) || !containsHost(meta.Blocks[index].Hosts, hostId)
+		}(s.GetPeerRPC(h))
+	}
+	unreferenced := 0
+	for range *hosts {
+		if <-answers {
+			unreferenced++
+		}
+	}
+	return unreferenced > len(*hosts)/2
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
@@ -10,20 +11,37 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/patrickmn/go-cache"
 	"log"
+	"strings"
+	"time"
 )
 
 // Beta group contracts
 
 const _10MB = uint32(10 * 1024 * 1024)
 const _1KB = uint32(1024)
+const LEASE_DURATION = uint64(time.Minute)
+const MAX_CLOCK_SKEW = uint64(10 * time.Second)
 
 const (
-	VOLUMES   = 1
-	DIRECTORY = 2
-	FILE_LOCK = 3
-	FILE_META = 4
-	BLOCKS    = 5
-	STASH     = 6
+	VOLUMES         = 1
+	DIRECTORY       = 2
+	FILE_LOCK       = 3
+	FILE_META       = 4
+	BLOCKS          = 5
+	STASH           = 6
+	QUARANTINE      = 7
+	LOG_CLOCK       = 8
+	USER_GROUPS     = 9
+	SETTINGS        = 10
+	JOBS            = 11
+	SNAPSHOTS       = 12
+	SNAPSHOT_DIRS   = 13
+	SNAPSHOT_FILES  = 14
+	SNAPSHOT_REFS   = 15
+	SNAPSHOT_BLOCKS = 16
+	VERSIONS        = 17
+	TRASH           = 18
+	GROUP_NAMES     = 19
 )
 
 const (
@@ -35,6 +53,36 @@ const (
 	COMMIT_BLOCK      = 15
 	REG_STASH         = 16
 	RELEASE_FILE_LOCK = 17
+	REPLACE_REPLICAS  = 18
+	UPDATE_BLOCK_HASH = 19
+	STASH_HEARTBEAT   = 20
+	SET_STASH_STATE   = 21
+	DEREG_STASH       = 22
+	LOCK              = 23
+	UNLOCK            = 24
+	CHMOD             = 25
+	CHOWN             = 26
+	SET_USER_GROUP    = 27
+	SET_ACL           = 28
+	SET_VOL_CREATORS  = 29
+	UPDATE_VOLUME     = 30
+	DELETE_VOLUME     = 31
+	JOB_PROGRESS      = 32
+	SET_POLICY        = 33
+	SET_QUOTA         = 34
+	DELETE_ITEM       = 35
+	MOVE_ITEM         = 36
+	SNAPSHOT_VOLUME   = 37
+	DELETE_SNAPSHOT   = 38
+	COMMIT_VERSION    = 39
+	RESTORE_TRASH     = 40
+	PURGE_TRASH       = 41
+	SET_XATTR         = 42
+	ACCESS_FILE       = 43
+	CHTIMES           = 44
+	MOVE_KEPT_BLOCK   = 45
+	TRUNCATE_FILE     = 46
+	RESTORE_VERSION   = 47
 )
 
 const (
@@ -50,6 +98,36 @@ func (s *PCFSServer) RegisterStorageContracts() {
 	s.BFTRaft.RegisterRaftFunc(COMMIT_BLOCK, s.smCommitBlockCreation)
 	s.BFTRaft.RegisterRaftFunc(REG_STASH, s.smRegStash)
 	s.BFTRaft.RegisterRaftFunc(RELEASE_FILE_LOCK, s.smReleaseFileWriteLock)
+	s.BFTRaft.RegisterRaftFunc(REPLACE_REPLICAS, s.smReplaceBlockReplicas)
+	s.BFTRaft.RegisterRaftFunc(UPDATE_BLOCK_HASH, s.smUpdateBlockHash)
+	s.BFTRaft.RegisterRaftFunc(STASH_HEARTBEAT, s.smStashHeartbeat)
+	s.BFTRaft.RegisterRaftFunc(SET_STASH_STATE, s.smSetStashState)
+	s.BFTRaft.RegisterRaftFunc(DEREG_STASH, s.smDeregStash)
+	s.BFTRaft.RegisterRaftFunc(LOCK, s.smLock)
+	s.BFTRaft.RegisterRaftFunc(UNLOCK, s.smUnlock)
+	s.BFTRaft.RegisterRaftFunc(CHMOD, s.smChmod)
+	s.BFTRaft.RegisterRaftFunc(CHOWN, s.smChown)
+	s.BFTRaft.RegisterRaftFunc(SET_USER_GROUP, s.smSetUserGroup)
+	s.BFTRaft.RegisterRaftFunc(SET_ACL, s.smSetAcl)
+	s.BFTRaft.RegisterRaftFunc(SET_VOL_CREATORS, s.smSetVolumeCreators)
+	s.BFTRaft.RegisterRaftFunc(UPDATE_VOLUME, s.smUpdateVolume)
+	s.BFTRaft.RegisterRaftFunc(DELETE_VOLUME, s.smDeleteVolume)
+	s.BFTRaft.RegisterRaftFunc(JOB_PROGRESS, s.smReplicationJobProgress)
+	s.BFTRaft.RegisterRaftFunc(SET_POLICY, s.smSetPolicy)
+	s.BFTRaft.RegisterRaftFunc(SET_QUOTA, s.smSetQuota)
+	s.BFTRaft.RegisterRaftFunc(DELETE_ITEM, s.smDeleteItem)
+	s.BFTRaft.RegisterRaftFunc(MOVE_ITEM, s.smMoveItem)
+	s.BFTRaft.RegisterRaftFunc(SNAPSHOT_VOLUME, s.smSnapshotVolume)
+	s.BFTRaft.RegisterRaftFunc(DELETE_SNAPSHOT, s.smDeleteSnapshot)
+	s.BFTRaft.RegisterRaftFunc(COMMIT_VERSION, s.smCommitVersion)
+	s.BFTRaft.RegisterRaftFunc(RESTORE_TRASH, s.smRestoreTrash)
+	s.BFTRaft.RegisterRaftFunc(PURGE_TRASH, s.smPurgeTrash)
+	s.BFTRaft.RegisterRaftFunc(SET_XATTR, s.smSetXattr)
+	s.BFTRaft.RegisterRaftFunc(ACCESS_FILE, s.smAccessFile)
+	s.BFTRaft.RegisterRaftFunc(CHTIMES, s.smChtimes)
+	s.BFTRaft.RegisterRaftFunc(MOVE_KEPT_BLOCK, s.smMoveKeptBlock)
+	s.BFTRaft.RegisterRaftFunc(TRUNCATE_FILE, s.smTruncateFile)
+	s.BFTRaft.RegisterRaftFunc(RESTORE_VERSION, s.smRestoreVersion)
 }
 
 func (s *PCFSServer) smRegStash(arg *[]byte, entry *rpb.LogEntry) []byte {
@@ -57,17 +135,36 @@ func (s *PCFSServer) smRegStash(arg *[]byte, entry *rpb.LogEntry) []byte {
 	hostStash := &pb.HostStash{}
 	if err := proto.Unmarshal(*arg, hostStash); err != nil {
 		log.Println("cannot decode host stash", err)
+		return []byte{0}
 	}
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
 		host := s.BFTRaft.GetHost(txn, hostStash.HostId)
 		if host == nil {
 			return errors.New("cannot found host")
 		}
+		// stash owners are trusted by heartbeats, volume creation and the log clock,
+		// so only the host registers it's stash, and only it or the owner registers it again
+		clientId := entry.Command.ClientId
 		stash, err := GetHostStash(txn, group, hostStash.HostId)
 		if err == badger.ErrKeyNotFound {
+			if clientId != hostStash.HostId {
+				return errors.New("stash not registered by it's host")
+			}
 			hostStash.Used = 0
+			hostStash.State = pb.StashState_ONLINE
+			if now, err := LogClock(txn, group); err != nil {
+				return err
+			} else if now > 0 && hostStash.LastSeen > now {
+				hostStash.LastSeen = now
+			}
 		} else if err == nil {
+			if clientId != hostStash.HostId && clientId != stash.Owner {
+				return errors.New("stash not registered by it's host or owner")
+			}
+			hostStash.Owner = stash.Owner
 			hostStash.Used = stash.Used
+			hostStash.State = stash.State
+			hostStash.LastSeen = stash.LastSeen
 		} else {
 			return err
 		}
@@ -87,24 +184,25 @@ func (s *PCFSServer) smNewVolume(arg *[]byte, entry *rpb.LogEntry) []byte {
 		log.Println("cannot decode volume:", err)
 		return []byte{0}
 	}
-	key := IdFromName(volume.Name)
-	volume.Key = key
-	if volume.Replications > 32 {
-		volume.Replications = 32
-	}
-	if volume.Replications < 5 {
-		volume.Replications = 5
+	if volume.Name == "" || strings.ContainsAny(volume.Name, ":/") {
+		log.Println("invalid volume name:", volume.Name)
+		return []byte{0}
 	}
-	if volume.BlockSize < _1KB {
-		volume.BlockSize = _1KB
+	volume.Owner = entry.Command.ClientId
+	key := VolumeKey(volume.Owner, volume.Name)
+	volume.Key = key
+	if volume.Replications == 0 {
+		volume.Replications = DEFAULT_REPLICATIONS
 	}
-	if volume.BlockSize > _10MB {
-		volume.BlockSize = _10MB
+	if volume.BlockSize == 0 {
+		volume.BlockSize = DEFAULT_BLOCK_SIZE
 	}
 	dbKey := DBKey(group, VOLUMES, key)
 	rootDirDbKey := DBKey(group, DIRECTORY, key)
 	rootDir := &pb.Directory{
 		Key: key, Files: [][]byte{},
+		Owner: entry.Command.ClientId, Mode: DEFAULT_DIR_MODE,
+		Volume: key, Usage: &pb.DirUsage{},
 	}
 	volume.RootDir = key
 	volumeData, err := proto.Marshal(volume)
@@ -115,9 +213,15 @@ func (s *PCFSServer) smNewVolume(arg *[]byte, entry *rpb.LogEntry) []byte {
 	rootDirData, err := proto.Marshal(rootDir)
 	if err != nil {
 		log.Println("cannot encode root dir")
-		return []byte{1}
+		return []byte{0}
 	}
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if !CanCreateVolume(txn, group, volume.Owner) {
+			return errors.New("client is not allowed to create volumes")
+		}
+		if err := ValidateVolumeOptions(txn, group, volume.Replications, volume.BlockSize); err != nil {
+			return err
+		}
 		if _, err := txn.Get(dbKey); err != badger.ErrKeyNotFound {
 			return errors.New("volume existed")
 		}
@@ -127,6 +231,15 @@ func (s *PCFSServer) smNewVolume(arg *[]byte, entry *rpb.LogEntry) []byte {
 		if err := txn.Set(dbKey, volumeData, 0x00); err != nil {
 			return err
 		}
+		now, err := LogClock(txn, group)
+		if err != nil {
+			return err
+		}
+		rootDir.CreatedAt = now
+		touchDir(rootDir, now)
+		if rootDirData, err = proto.Marshal(rootDir); err != nil {
+			return err
+		}
 		if err := txn.Set(rootDirDbKey, rootDirData, 0x00); err != nil {
 			return err
 		}
@@ -149,12 +262,46 @@ func (s *PCFSServer) smNewDirectory(arg *[]byte, entry *rpb.LogEntry) []byte {
 	dir := contract.Dir
 	dir.Key, _ = utils.SHA1Hash(append(contract.ParentDir, entry.Hash...))
 	dir.Files = [][]byte{}
+	dir.Owner = entry.Command.ClientId
+	dir.Group = 0
+	dir.Mode = modeOrDefault(dir.Mode, DEFAULT_DIR_MODE)
+	dir.Acl = nil
+	dir.Parent = contract.ParentDir
 	newDirToken := append([]byte{1}, dir.Key...)
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
 		parentDir, err := GetDirectory(txn, group, contract.ParentDir)
 		if err != nil {
 			return err
 		}
+		if err := CheckDirAccess(txn, group, parentDir, entry.Command.ClientId, PERM_WRITE|PERM_EXEC); err != nil {
+			return err
+		}
+		if reservedName(parentDir, dir.Name) {
+			return errors.New("name " + dir.Name + " is reserved")
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		dir.CreatedAt = now
+		touchDir(dir, now)
+		touchDir(parentDir, now)
+		dir.Volume = parentDir.Volume
+		if contract.Dir.Policy != nil {
+			volume, err := GetVolume(txn, group, dir.Volume)
+			if err != nil {
+				return err
+			}
+			if err := ValidatePolicy(txn, group, volume, contract.Dir.Policy); err != nil {
+				return err
+			}
+		}
+		dir.Policy = MergePolicy(parentDir.Policy, contract.Dir.Policy)
+		dir.Quota = nil
+		dir.Usage = &pb.DirUsage{}
+		if err := ChargeQuota(txn, group, parentDir, 0, 0, 1); err != nil {
+			return err
+		}
 		parentDir.Files = append(parentDir.Files, newDirToken)
 		if err := SetDirectory(txn, group, dir); err != nil {
 			return err
@@ -168,7 +315,7 @@ func (s *PCFSServer) smNewDirectory(arg *[]byte, entry *rpb.LogEntry) []byte {
 		return []byte{1}
 	} else {
 		log.Println("cannot create dir:", err)
-		return []byte{0}
+		return quotaResult(err)
 	}
 }
 
@@ -180,24 +327,32 @@ func (s *PCFSServer) smAcquireFileWriteLock(arg *[]byte, entry *rpb.LogEntry) []
 		return []byte{0}
 	}
 	newLock := &pb.FileWriteLock{
-		Group: group, Key: contract.Key,
+		Group: group, Key: contract.Key, Owner: entry.Command.ClientId,
 	}
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
-		if _, err := GetFile(txn, group, contract.Key); err != nil {
+		if file, err := GetFile(txn, group, contract.Key); err != nil {
+			return err
+		} else if err := CheckFileAccess(txn, group, file, entry.Command.ClientId, PERM_WRITE); err != nil {
 			return err
 		}
-		if lock, err := GetWriteLock(txn, group, contract.Key); err == badger.ErrKeyNotFound {
-			SetWriteLock(txn, group, newLock)
-		} else if err == nil {
-			if lock.Owner != entry.Command.ClientId {
+		now, err := AdvanceLogClock(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		newLock.Expires = addSaturating(now, LEASE_DURATION)
+		if lock, err := GetWriteLock(txn, group, contract.Key); err == nil {
+			// the same owner renews it's lease, others can only take over an expired one
+			if lock.Owner != entry.Command.ClientId && lock.Expires > now {
 				return errors.New("lock already acquired")
-			} else {
-				SetWriteLock(txn, group, newLock)
 			}
-		} else {
+		} else if err != badger.ErrKeyNotFound {
 			return err
 		}
-		return nil
+		// blocks are overwritten under the lease, snapshots must have the file before
+		if err := freezeFile(txn, group, contract.Key); err != nil {
+			return err
+		}
+		return SetWriteLock(txn, group, newLock)
 	}); err == nil {
 		log.Println("file lock acuqired")
 		return []byte{1}
@@ -218,6 +373,9 @@ func (s *PCFSServer) smReleaseFileWriteLock(arg *[]byte, entry *rpb.LogEntry) []
 		if lock, err := GetWriteLock(txn, group, contract.Key); err == badger.ErrKeyNotFound {
 			return errors.New("cannot find the lock")
 		} else if err == nil {
+			if lock.Owner != entry.Command.ClientId {
+				return errors.New("lock not released by owner")
+			}
 			return s.ReleaseWriteLock(txn, group, lock)
 		} else {
 			return err
@@ -241,26 +399,56 @@ func (s *PCFSServer) smTouchFile(arg *[]byte, entry *rpb.LogEntry) []byte {
 	}
 	fileKey := FileKey(contract.Volume, contract.Dir, entry.Index)
 	file := &pb.FileMeta{
-		Name:         contract.Name,
-		Size:         0,
-		LastModified: contract.ClientTime,
-		CreatedAt:    contract.ClientTime,
-		Key:          fileKey,
-		Blocks:       []*pb.Block{},
+		Name:   contract.Name,
+		Size:   0,
+		Key:    fileKey,
+		Blocks: []*pb.Block{},
+		Volume: contract.Volume,
+		Dir:    contract.Dir,
+		Owner:  entry.Command.ClientId,
+		Mode:   modeOrDefault(contract.Mode, DEFAULT_FILE_MODE),
 	}
 	dirToken := append([]byte{byte(pb.DirectoryItem_FILE)}, file.Key...)
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
-		if vol, err := GetVolume(txn, group, contract.Volume); err == nil {
-			file.BlockSize = vol.BlockSize
-		} else {
+		vol, err := GetVolume(txn, group, contract.Volume)
+		if err != nil {
 			return errors.New("cannot find volume for touch file")
 		}
+		if err := ValidatePolicy(txn, group, vol, contract.Policy); err != nil {
+			return err
+		}
+		file.BlockSize = vol.BlockSize
+		file.Policy = contract.Policy
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		file.CreatedAt = now
+		file.LastModified = now
+		file.ChangedAt = now
+		file.AccessedAt = now
 		if dir, err := GetDirectory(txn, group, contract.Dir); err == nil {
+			if err := CheckDirAccess(txn, group, dir, entry.Command.ClientId, PERM_WRITE|PERM_EXEC); err != nil {
+				return err
+			}
+			if reservedName(dir, file.Name) {
+				return errors.New("name " + file.Name + " is reserved")
+			}
+			file.Policy = MergePolicy(dir.Policy, contract.Policy)
+			if _, err := GetFile(txn, group, fileKey); err == badger.ErrKeyNotFound {
+				if err := ChargeQuota(txn, group, dir, 0, 1, 0); err != nil {
+					return err
+				}
+			}
 			dir.Files = append(dir.Files, dirToken)
+			touchDir(dir, now)
 			if err := SetDirectory(txn, group, dir); err != nil {
 				return err
 			}
 		}
+		if file.Policy != nil && file.Policy.BlockSize != 0 {
+			file.BlockSize = file.Policy.BlockSize
+		}
 		if _, err := GetFile(txn, group, fileKey); err == badger.ErrKeyNotFound {
 			SetFile(txn, group, file)
 		}
@@ -270,7 +458,7 @@ func (s *PCFSServer) smTouchFile(arg *[]byte, entry *rpb.LogEntry) []byte {
 		return []byte{1}
 	} else {
 		log.Println("cannot touch file", err)
-		return []byte{0}
+		return quotaResult(err)
 	}
 }
 
@@ -287,7 +475,25 @@ func (s *PCFSServer) smConfirmBlock(arg *[]byte, entry *rpb.LogEntry) []byte {
 		log.Println("cannode decode confirm block contract:", err)
 		return []byte{0}
 	}
-	// TODO: verify client signature
+	if contract.NodeId != entry.Command.ClientId {
+		log.Println("block not confirmed by the stash node itself")
+		return []byte{0}
+	}
+	req := contract.Req
+	if req == nil || req.Index != contract.Index || !bytes.Equal(req.File, contract.File) {
+		log.Println("confirm block request not match")
+		return []byte{0}
+	}
+	if err := s.VerifyRequest(req.ClientId, req, &req.Signature); err != nil {
+		log.Println("cannot verify client signature of block:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		return HoldsWriteLock(txn, group, req.File, req.ClientId)
+	}); err != nil {
+		log.Println("cannot confirm block:", err)
+		return []byte{0}
+	}
 	cacheKey := fmt.Sprint(group, "-", contract.Index, "-", contract.File)
 	logI, cached := s.PendingBlocks.Get(cacheKey)
 	if !cached {
@@ -309,6 +515,12 @@ func (s *PCFSServer) smCommitBlockCreation(arg *[]byte, entry *rpb.LogEntry) []b
 		log.Println("cannode decode confirm block creation contract:", err)
 		return []byte{0}
 	}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		return HoldsWriteLock(txn, group, contract.File, entry.Command.ClientId)
+	}); err != nil {
+		log.Println("cannot commit block:", err)
+		return []byte{0}
+	}
 	cacheKey := fmt.Sprint(group, "-", contract.Index, "-", contract.File)
 	logI, cached := s.PendingBlocks.Get(cacheKey)
 	if !cached {
@@ -330,16 +542,35 @@ func (s *PCFSServer) smCommitBlockCreation(arg *[]byte, entry *rpb.LogEntry) []b
 	// remove cached
 	s.PendingBlocks.Delete(cacheKey)
 	// update file meta
-	newBlock := &pb.Block{Index: contract.Index, Hosts: hosts}
+	newBlock := &pb.Block{Index: contract.Index, Hosts: hosts, Hash: contract.Hash}
 	var fileRes *pb.FileMeta
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
 		if file, err := GetFile(txn, group, contract.File); err == nil {
+			if err := CheckFileAccess(txn, group, file, entry.Command.ClientId, PERM_WRITE); err != nil {
+				return err
+			}
 			blocks := len(file.Blocks)
 			if uint64(blocks) != contract.Index {
 				return errors.New("new block index not match next index")
 			}
+			if warning := DomainSpreadWarning(txn, group, hosts); warning != "" {
+				log.Println("block", contract.Index, "of file", contract.File, "not spread across failure domains:", warning)
+			}
+			if dir, err := GetDirectory(txn, group, file.Dir); err == nil {
+				if err := ChargeQuota(txn, group, dir, int64(file.BlockSize), 0, 0); err != nil {
+					return err
+				}
+				if err := SetDirectory(txn, group, dir); err != nil {
+					return err
+				}
+			}
+			now, err := contractTime(txn, group, contract.ClientTime)
+			if err != nil {
+				return err
+			}
 			file.Blocks = append(file.Blocks, newBlock)
-			file.LastModified = contract.ClientTime
+			file.LastModified = now
+			file.ChangedAt = now
 			file.Size = uint64(len(file.Blocks)) * uint64(file.BlockSize)
 			SetFile(txn, group, file)
 			fileRes = file
@@ -352,7 +583,272 @@ func (s *PCFSServer) smCommitBlockCreation(arg *[]byte, entry *rpb.LogEntry) []b
 		resData, _ := proto.Marshal(fileRes)
 		return resData
 	} else {
-		log.Println("failed to confirm new block")
+		log.Println("failed to confirm new block:", err)
+		return quotaResult(err)
+	}
+}
+
+// invoked by repair, balancer and drain on stash nodes after block data have been copied to the new hosts
+// old hosts must match current replicas of the block, so concurrent replacements won't overwrite each other
+// Added hosts must be registered stashes that are not dead
+func (s *PCFSServer) smReplaceBlockReplicas(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.ReplaceBlockReplicasContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode replace replicas contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if !isStashOwner(txn, group, entry.Command.ClientId) {
+			return errors.New("replicas not replaced by a stash node")
+		}
+		now, err := LogClock(txn, group)
+		if err != nil {
+			return err
+		}
+		for _, hostId := range contract.NewHosts {
+			if containsHost(contract.OldHosts, hostId) {
+				continue
+			}
+			stash, err := GetHostStash(txn, group, hostId)
+			if err != nil {
+				return errors.New(fmt.Sprint("new replica host ", hostId, " is not a stash"))
+			}
+			if s.StashStateAt(stash, time.Unix(0, int64(now))) == pb.StashState_DEAD {
+				return errors.New(fmt.Sprint("new replica host ", hostId, " is dead"))
+			}
+		}
+		file, err := GetFile(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		if contract.Index >= uint64(len(file.Blocks)) {
+			return errors.New("block index out of range")
+		}
+		block := file.Blocks[contract.Index]
+		if !sameHosts(block.Hosts, contract.OldHosts) {
+			return errors.New("block replicas changed")
+		}
+		if len(contract.NewHosts) == 0 {
+			return errors.New("cannot remove all replicas")
+		}
+		block.Hosts = contract.NewHosts
+		if warning := DomainSpreadWarning(txn, group, block.Hosts); warning != "" {
+			log.Println("block", contract.Index, "of file", contract.File, "not spread across failure domains:", warning)
+		}
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("block replicas replaced")
+		return []byte{1}
+	} else {
+		log.Println("cannot replace block replicas:", err)
+		return []byte{0}
+	}
+}
+
+func sameHosts(a []uint64, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	hosts := map[uint64]bool{}
+	for _, h := range a {
+		hosts[h] = true
+	}
+	for _, h := range b {
+		if !hosts[h] {
+			return false
+		}
+	}
+	return true
+}
+
+// invoked by client after it landed writes of a block on it's replicas
+// the hash is the expected one for all replicas, stash scrubbers check local blocks against it
+func (s *PCFSServer) smUpdateBlockHash(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.UpdateBlockHashContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode update block hash contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if err := HoldsWriteLock(txn, group, contract.File, entry.Command.ClientId); err != nil {
+			return err
+		}
+		file, err := GetFile(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		if contract.Index >= uint64(len(file.Blocks)) {
+			return errors.New("block index out of range")
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		file.Blocks[contract.Index].Hash = contract.Hash
+		file.LastModified = now
+		file.ChangedAt = now
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("block hash updated")
+		return []byte{1}
+	} else {
+		log.Println("cannot update block hash:", err)
+		return []byte{0}
+	}
+}
+
+// invoked by the writer, returns the dropped blocks for their replicas to be deleted
+func (s *PCFSServer) smTruncateFile(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.TruncateFileContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode truncate file contract:", err)
+		return []byte{0}
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if err := HoldsWriteLock(txn, group, contract.File, entry.Command.ClientId); err != nil {
+			return err
+		}
+		file, err := GetFile(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		if contract.Blocks >= uint64(len(file.Blocks)) {
+			return nil
+		}
+		dropped := file.Blocks[contract.Blocks:]
+		if dir, err := GetDirectory(txn, group, file.Dir); err == nil {
+			if err := ChargeQuota(txn, group, dir, -int64(len(dropped))*int64(file.BlockSize), 0, 0); err != nil {
+				return err
+			}
+			if err := SetDirectory(txn, group, dir); err != nil {
+				return err
+			}
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		file.Blocks = file.Blocks[:contract.Blocks]
+		file.LastModified = now
+		file.ChangedAt = now
+		file.Size = uint64(len(file.Blocks)) * uint64(file.BlockSize)
+		deleted.Files = append(deleted.Files, &pb.FileMeta{Key: file.Key, Name: file.Name, Blocks: dropped})
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("file truncated")
+		resData, _ := proto.Marshal(deleted)
+		return resData
+	} else {
+		log.Println("cannot truncate file:", err)
+		return []byte{0}
+	}
+}
+
+// invoked periodically by every stash node for it's own stash
+func (s *PCFSServer) smStashHeartbeat(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.StashHeartbeatContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode heartbeat contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		stash, err := GetHostStash(txn, group, contract.HostId)
+		if err != nil {
+			return err
+		}
+		if stash.Owner != entry.Command.ClientId {
+			return errors.New("heartbeat not sent by stash owner")
+		}
+		// a stash with it's clock ahead is seen no later than the bounded log clock
+		now, err := startLogClock(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		if now > stash.LastSeen {
+			stash.LastSeen = now
+		}
+		stash.Used = contract.Used
+		return SetHostStash(txn, group, stash)
+	}); err == nil {
+		return []byte{1}
+	} else {
+		log.Println("cannot record heartbeat:", err)
+		return []byte{0}
+	}
+}
+
+// only the states set by the owner can be stored, suspect and dead are derived from heartbeats
+func (s *PCFSServer) smSetStashState(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.SetStashStateContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode set stash state contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		stash, err := GetHostStash(txn, group, contract.HostId)
+		if err != nil {
+			return err
+		}
+		if stash.Owner != entry.Command.ClientId {
+			return errors.New("stash state not set by stash owner")
+		}
+		switch contract.State {
+		case pb.StashState_ONLINE, pb.StashState_MAINTENANCE, pb.StashState_DRAINING:
+			stash.State = contract.State
+		default:
+			return errors.New("stash state cannot be set")
+		}
+		return SetHostStash(txn, group, stash)
+	}); err == nil {
+		log.Println("stash state set to", contract.State)
+		return []byte{1}
+	} else {
+		log.Println("cannot set stash state:", err)
+		return []byte{0}
+	}
+}
+
+// a stash can only leave after it was drained and no block references it
+func (s *PCFSServer) smDeregStash(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.DeregStashContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode dereg stash contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		stash, err := GetHostStash(txn, group, contract.HostId)
+		if err != nil {
+			return err
+		}
+		if stash.Owner != entry.Command.ClientId {
+			return errors.New("stash not deregistered by stash owner")
+		}
+		if stash.State != pb.StashState_DRAINING {
+			return errors.New("stash is not draining")
+		}
+		if err := ForEachFile(txn, group, func(file *pb.FileMeta) error {
+			for _, block := range file.Blocks {
+				if containsHost(block.Hosts, contract.HostId) {
+					return errors.New("stash still holds replicas")
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return txn.Delete(DBKey(group, STASH, utils.U64Bytes(contract.HostId)))
+	}); err == nil {
+		log.Println("stash deregistered:", contract.HostId)
+		return []byte{1}
+	} else {
+		log.Println("cannot deregister stash:", err)
 		return []byte{0}
 	}
 }
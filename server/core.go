@@ -5,21 +5,35 @@ import (
 	"github.com/PomeloCloud/BFTRaft4go/utils"
 	pb "github.com/PomeloCloud/pcfs/proto"
 	"github.com/patrickmn/go-cache"
+	"google.golang.org/grpc"
 	"log"
+	"sync"
 	"time"
 )
 
 type PCFSServer struct {
-	BFTRaft       *bft.BFTRaftServer
-	PendingBlocks *cache.Cache
+	BFTRaft        *bft.BFTRaftServer
+	PendingBlocks  *cache.Cache
+	SuspectTimeout time.Duration
+	DeadTimeout    time.Duration
+	tls            *certStore
+	conns          map[string]*grpc.ClientConn
+	connsLock      sync.Mutex
 }
 
-func GetServer(bft *bft.BFTRaftServer) *PCFSServer {
+func GetServer(bft *bft.BFTRaftServer, config FileConfig) *PCFSServer {
 	fsserver := PCFSServer{
-		BFTRaft:       bft,
-		PendingBlocks: cache.New(5*time.Minute, 5*time.Minute),
+		BFTRaft:        bft,
+		PendingBlocks:  cache.New(5*time.Minute, 5*time.Minute),
+		SuspectTimeout: time.Minute,
+		DeadTimeout:    10 * time.Minute,
+		conns:          map[string]*grpc.ClientConn{},
 	}
 	log.Println("registering storage services")
-	pb.RegisterPCFSServer(utils.GetGRPCServer(bft.Opts.Address), &fsserver)
+	if config.TLS.Cert == "" {
+		pb.RegisterPCFSServer(utils.GetGRPCServer(bft.Opts.Address), &fsserver)
+	} else {
+		fsserver.ServeTLS(config.TLS)
+	}
 	return &fsserver
 }
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"log"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Items keep their keys across renames and moves, so snapshots are compared by keys
+// An item is renamed when it's name or parent changed, items under a renamed dir are not listed
+// A file is modified when hashes of it's blocks or it's mode changed, dirs only by mode
+// Renames are listed with deepest old path first, then removed items deepest first and only the top of a removed tree,
+// added items shallowest first and modified files.
+// Replaying entries in order rebuilds the newer snapshot from the older one, when renamed items are moved aside
+// as they come and put at their new paths shallowest first, before added items deeper than them.
+// Names can be swapped, items moved out of removed dirs and into added dirs that way
+
+type treeItem struct {
+	key    []byte
+	parent []byte
+	name   string
+	path   string
+	dir    bool
+	mode   uint32
+	file   *pb.FileMeta
+}
+
+func snapshotTree(txn *badger.Txn, group uint64, snapshot *pb.Snapshot) (map[string]*treeItem, error) {
+	items := map[string]*treeItem{}
+	if snapshot == nil {
+		return items, nil
+	}
+	root, err := GetSnapshotDir(txn, group, snapshot.Key, snapshot.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	var walk func(dir *pb.Directory, dirPath string) error
+	walk = func(dir *pb.Directory, dirPath string) error {
+		for _, token := range dir.Files {
+			if token[0] == byte(pb.DirectoryItem_DIR) {
+				sub, err := GetSnapshotDir(txn, group, snapshot.Key, token[1:])
+				if err != nil {
+					return err
+				}
+				item := &treeItem{key: sub.Key, parent: dir.Key, name: sub.Name, path: path.Join(dirPath, sub.Name), dir: true, mode: sub.Mode}
+				items[string(sub.Key)] = item
+				if err := walk(sub, item.path); err != nil {
+					return err
+				}
+				continue
+			}
+			file, err := GetSnapshotFile(txn, group, snapshot.Key, token[1:])
+			if err != nil {
+				return err
+			}
+			items[string(file.Key)] = &treeItem{
+				key: file.Key, parent: dir.Key, name: file.Name, path: path.Join(dirPath, file.Name), mode: file.Mode, file: file,
+			}
+		}
+		return nil
+	}
+	return items, walk(root, "")
+}
+
+// indexes of blocks in b which are new or have other content than in a
+func changedBlocks(a *pb.FileMeta, b *pb.FileMeta) []uint64 {
+	changed := []uint64{}
+	for i, block := range b.Blocks {
+		if a == nil || i >= len(a.Blocks) || !bytes.Equal(a.Blocks[i].Hash, block.Hash) {
+			changed = append(changed, uint64(i))
+		}
+	}
+	return changed
+}
+
+func pathDepth(p string) int {
+	return strings.Count(p, "/")
+}
+
+// by depth of the path, then by the path so every node answers the same for the majority vote
+func sortEntries(entries []*pb.DiffEntry, pathOf func(e *pb.DiffEntry) string, deepestFirst bool) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := pathOf(entries[i]), pathOf(entries[j])
+		if pathDepth(a) != pathDepth(b) {
+			return (pathDepth(a) > pathDepth(b)) == deepestFirst
+		}
+		return a < b
+	})
+}
+
+func DiffTrees(from map[string]*treeItem, to map[string]*treeItem) []*pb.DiffEntry {
+	removed := []*pb.DiffEntry{}
+	renamed := []*pb.DiffEntry{}
+	added := []*pb.DiffEntry{}
+	modified := []*pb.DiffEntry{}
+	for key, a := range from {
+		if _, found := to[key]; found {
+			continue
+		}
+		if parent, found := from[string(a.parent)]; found {
+			if _, kept := to[string(parent.key)]; !kept {
+				continue
+			}
+		}
+		removed = append(removed, &pb.DiffEntry{Change: pb.DiffEntry_REMOVED, Path: a.path, OldPath: a.path, Dir: a.dir, File: a.file})
+	}
+	for key, b := range to {
+		a, found := from[key]
+		if !found {
+			entry := &pb.DiffEntry{Change: pb.DiffEntry_ADDED, Path: b.path, Dir: b.dir, File: b.file, Mode: b.mode}
+			if !b.dir {
+				entry.Blocks = changedBlocks(nil, b.file)
+			}
+			added = append(added, entry)
+			continue
+		}
+		if a.name != b.name || !bytes.Equal(a.parent, b.parent) {
+			renamed = append(renamed, &pb.DiffEntry{Change: pb.DiffEntry_RENAMED, Path: b.path, OldPath: a.path, Dir: b.dir, File: b.file})
+		}
+		entry := &pb.DiffEntry{Change: pb.DiffEntry_MODIFIED, Path: b.path, Dir: b.dir, File: b.file, Mode: b.mode}
+		if !b.dir {
+			entry.Blocks = changedBlocks(a.file, b.file)
+		}
+		if len(entry.Blocks) > 0 || a.mode != b.mode || (!b.dir && a.file.Size != b.file.Size) {
+			modified = append(modified, entry)
+		}
+	}
+	sortEntries(removed, func(e *pb.DiffEntry) string { return e.Path }, true)
+	sortEntries(renamed, func(e *pb.DiffEntry) string { return e.OldPath }, true)
+	sortEntries(added, func(e *pb.DiffEntry) string { return e.Path }, false)
+	sortEntries(modified, func(e *pb.DiffEntry) string { return e.Path }, false)
+	return append(append(append(renamed, removed...), added...), modified...)
+}
+
+// readers need read permission on the volume root, like for browsing snapshots
+func (s *PCFSServer) DiffSnapshots(ctx context.Context, req *pb.DiffRequest) (*pb.DiffResponse, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected snapshot diff:", err)
+		return nil, err
+	}
+	res := &pb.DiffResponse{}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		volume, err := ResolveVolume(txn, req.Group, req.Volume, clientId)
+		if err != nil {
+			return err
+		}
+		rootDir, err := GetDirectory(txn, req.Group, volume.RootDir)
+		if err != nil {
+			return err
+		}
+		if err := CheckDirAccess(txn, req.Group, rootDir, clientId, PERM_READ|PERM_EXEC); err != nil {
+			return err
+		}
+		var from *pb.Snapshot
+		if req.From != "" {
+			if from, err = GetSnapshot(txn, req.Group, volume.Key, req.From); err != nil {
+				return errors.New("cannot find snapshot " + req.From)
+			}
+		}
+		to, err := GetSnapshot(txn, req.Group, volume.Key, req.To)
+		if err != nil {
+			return errors.New("cannot find snapshot " + req.To)
+		}
+		fromTree, err := snapshotTree(txn, req.Group, from)
+		if err != nil {
+			return err
+		}
+		toTree, err := snapshotTree(txn, req.Group, to)
+		if err != nil {
+			return err
+		}
+		res.Entries = DiffTrees(fromTree, toTree)
+		return nil
+	}); err != nil {
+		log.Println("cannot diff snapshots:", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Drain retires the stash of this node:
+//
+//	mark it DRAINING so no new block is placed here
+//	move every replica it holds to other stashes, reporting progress on the way
+//	move the copies it kept for snapshots and versions, pointing their meta data at the new stash
+//	deregister the stash after no block references it
+//
+// It can be run again after interruption, blocks moved before are not referencing this node anymore
+func (s *PCFSServer) DrainStash(throttle *Throttle) error {
+	if err := s.SetStashState(pb.StashState_DRAINING); err != nil {
+		return err
+	}
+	self := s.BFTRaft.Id
+	for true {
+		blocks, err := s.scanBlockReplicas()
+		if err != nil {
+			return err
+		}
+		hosted := []*blockReplicas{}
+		for _, block := range blocks {
+			if containsHost(block.hosts, self) {
+				hosted = append(hosted, block)
+			}
+		}
+		if len(hosted) == 0 {
+			break
+		}
+		log.Println("draining", len(hosted), "blocks")
+		moved := 0
+		for i, block := range hosted {
+			if err := s.drainBlock(block, throttle); err == nil {
+				moved++
+			} else {
+				log.Println("cannot drain block", block.index, "of file", block.file, ":", err)
+			}
+			log.Println("drain progress:", i+1, "/", len(hosted), "blocks, moved", moved)
+		}
+		if moved == 0 {
+			return errors.New("no block can be moved off this stash")
+		}
+	}
+	// moving live replicas away keeps their old contents here too
+	if err := s.drainKeptBlocks(throttle); err != nil {
+		return err
+	}
+	contractData, err := proto.Marshal(&pb.DeregStashContract{HostId: self})
+	if err != nil {
+		return err
+	}
+	res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, DEREG_STASH, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("dereg stash contract failed")
+	}
+	log.Println("stash drained and deregistered")
+	return nil
+}
+
+func (s *PCFSServer) drainBlock(block *blockReplicas, throttle *Throttle) error {
+	self := s.BFTRaft.Id
+	others := []uint64{}
+	for _, hostId := range block.hosts {
+		if hostId != self {
+			others = append(others, hostId)
+		}
+	}
+	suggestion, err := s.SuggestBlockStash(context.Background(), &pb.BlockStashSuggestionRequest{
+		Group:    STASH_GROUP,
+		Num:      1,
+		Existing: others,
+		Zones:    block.zones,
+	})
+	if err != nil {
+		return err
+	}
+	if len(suggestion.Nodes) == 0 {
+		return errors.New("no stash available for the replica")
+	}
+	target := suggestion.Nodes[0].HostId
+	newHosts := []uint64{}
+	for _, hostId := range block.hosts {
+		if hostId == self {
+			newHosts = append(newHosts, target)
+		} else {
+			newHosts = append(newHosts, hostId)
+		}
+	}
+	return s.moveBlock(block, self, target, newHosts, throttle)
+}
+
+func (s *PCFSServer) drainKeptBlocks(throttle *Throttle) error {
+	keyPrefix := DBKey(3, SNAPSHOT_BLOCKS, utils.U64Bytes(STASH_GROUP))
+	keys := [][]byte{}
+	s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		keys = prefixKeys(txn, keyPrefix)
+		return nil
+	})
+	failed := 0
+	for i, key := range keys {
+		// index, file key and content hash
+		rest := key[len(keyPrefix):]
+		index := utils.BytesU64(rest, 0)
+		file := rest[8 : len(rest)-sha1.Size]
+		hash := rest[len(rest)-sha1.Size:]
+		if err := s.drainKeptBlock(file, index, hash, throttle); err != nil {
+			log.Println("cannot drain kept block", index, "of file", file, ":", err)
+			failed++
+		}
+		log.Println("drain progress:", i+1, "/", len(keys), "kept blocks")
+	}
+	if failed > 0 {
+		return errors.New("kept blocks cannot be moved off this stash")
+	}
+	return nil
+}
+
+func (s *PCFSServer) drainKeptBlock(file []byte, index uint64, hash []byte, throttle *Throttle) error {
+	self := s.BFTRaft.Id
+	key := SnapshotBlockDBKey(STASH_GROUP, file, index, hash)
+	refs := uint64(0)
+	size := 0
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		data, err := item.Value()
+		if err != nil {
+			return err
+		}
+		size = len(data)
+		refs, err = BlockRefs(txn, STASH_GROUP, file, index, hash)
+		return err
+	}); err != nil {
+		return err
+	}
+	// copies of released contents are just dropped
+	if refs > 0 {
+		suggestion, err := s.SuggestBlockStash(context.Background(), &pb.BlockStashSuggestionRequest{
+			Group: STASH_GROUP,
+			Num:   1,
+		})
+		if err != nil {
+			return err
+		}
+		if len(suggestion.Nodes) == 0 {
+			return errors.New("no stash available for the kept block")
+		}
+		target := suggestion.Nodes[0].HostId
+		host := s.BFTRaft.GetHostNTXN(target)
+		if host == nil {
+			return errors.New("cannot find target host")
+		}
+		client := s.GetPeerRPC(host)
+		if client == nil {
+			return errors.New("cannot connect target host")
+		}
+		throttle.Wait(uint64(size))
+		req := &pb.ReplicateBlockRequest{
+			Group:    STASH_GROUP,
+			Index:    index,
+			File:     file,
+			Source:   self,
+			Hash:     hash,
+			ClientId: self,
+			Kept:     true,
+		}
+		if err := s.SignRequest(req, &req.Signature); err != nil {
+			return err
+		}
+		if res, err := client.ReplicateBlock(context.Background(), req); err != nil {
+			return err
+		} else if !res.Succeed {
+			return errors.New("replicate kept block failed")
+		}
+		contractData, err := proto.Marshal(&pb.MoveKeptBlockContract{
+			File: file, Index: index, Hash: hash, From: self, To: target,
+		})
+		if err != nil {
+			return err
+		}
+		res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, MOVE_KEPT_BLOCK, contractData)
+		if err != nil {
+			return err
+		}
+		if (*res)[0] != 1 {
+			return errors.New("move kept block contract failed")
+		}
+	}
+	return s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
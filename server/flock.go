@@ -0,0 +1,157 @@
+package server
+
+import (
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Advisory locks, flock style, on files and directories by their keys
+// Shared holders can coexist, an exclusive holder is alone
+// A request that cannot be granted fails, or joins the wait queue when it asked to wait
+// Waiters are granted in order, so shared requests cannot starve a waiting exclusive one
+// Holders and waiters expire by the log clock after their ttl, crashed clients leave nothing behind
+// Clients renew holding locks and stay in the queue by sending the same lock contract again
+// Renewing or downgrading a held lock never waits, upgrading waits behind the queue like a new lock
+// Ttl is kept in bounds, waiting clients ask again every second and a lock should not outlive it's client for long
+
+const (
+	LOCK_FAILED  = 0
+	LOCK_GRANTED = 1
+	LOCK_QUEUED  = 2
+)
+
+const (
+	MIN_LOCK_TTL = uint64(3 * time.Second)
+	MAX_LOCK_TTL = uint64(time.Hour)
+)
+
+func liveLockHolders(holders []*pb.AdvisoryLockHolder, now uint64) []*pb.AdvisoryLockHolder {
+	live := []*pb.AdvisoryLockHolder{}
+	for _, holder := range holders {
+		if holder.Expires > now {
+			live = append(live, holder)
+		}
+	}
+	return live
+}
+
+func withoutLockOwner(holders []*pb.AdvisoryLockHolder, owner uint64) []*pb.AdvisoryLockHolder {
+	rest := []*pb.AdvisoryLockHolder{}
+	for _, holder := range holders {
+		if holder.Owner != owner {
+			rest = append(rest, holder)
+		}
+	}
+	return rest
+}
+
+func lockCompatible(holders []*pb.AdvisoryLockHolder, mode pb.LockMode) bool {
+	for _, holder := range holders {
+		if mode == pb.LockMode_EXCLUSIVE || holder.Mode == pb.LockMode_EXCLUSIVE {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PCFSServer) smLock(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	owner := entry.Command.ClientId
+	contract := &pb.LockContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode lock contract:", err)
+		return []byte{LOCK_FAILED}
+	}
+	ttl := contract.Ttl
+	if ttl == 0 {
+		ttl = LEASE_DURATION
+	} else if ttl < MIN_LOCK_TTL {
+		ttl = MIN_LOCK_TTL
+	} else if ttl > MAX_LOCK_TTL {
+		ttl = MAX_LOCK_TTL
+	}
+	result := byte(LOCK_FAILED)
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		now, err := AdvanceLogClock(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		lock, err := GetAdvisoryLock(txn, group, contract.Key)
+		if err == badger.ErrKeyNotFound {
+			lock = &pb.AdvisoryLock{Key: contract.Key}
+		} else if err != nil {
+			return err
+		}
+		lock.Holders = liveLockHolders(lock.Holders, now)
+		lock.Waiters = liveLockHolders(lock.Waiters, now)
+		request := &pb.AdvisoryLockHolder{Owner: owner, Mode: contract.Mode, Expires: addSaturating(now, ttl)}
+		// locks held by the owner itself don't conflict, so it can renew, upgrade or downgrade
+		others := withoutLockOwner(lock.Holders, owner)
+		// a holder renews or downgrades without waiting, only new locks and upgrades queue behind waiters
+		holding := false
+		for _, holder := range lock.Holders {
+			if holder.Owner == owner && (holder.Mode == contract.Mode || contract.Mode == pb.LockMode_SHARED) {
+				holding = true
+			}
+		}
+		ahead := []*pb.AdvisoryLockHolder{}
+		for _, waiter := range lock.Waiters {
+			if holding || waiter.Owner == owner {
+				break
+			}
+			ahead = append(ahead, waiter)
+		}
+		if lockCompatible(others, contract.Mode) && len(ahead) == 0 {
+			lock.Holders = append(others, request)
+			lock.Waiters = withoutLockOwner(lock.Waiters, owner)
+			result = LOCK_GRANTED
+		} else if contract.Wait {
+			queued := false
+			for i, waiter := range lock.Waiters {
+				if waiter.Owner == owner {
+					lock.Waiters[i] = request
+					queued = true
+				}
+			}
+			if !queued {
+				lock.Waiters = append(lock.Waiters, request)
+			}
+			result = LOCK_QUEUED
+		}
+		return SetAdvisoryLock(txn, group, lock)
+	}); err != nil {
+		log.Println("cannot lock:", err)
+		return []byte{LOCK_FAILED}
+	}
+	return []byte{result}
+}
+
+// releases the lock held by the client and leaves the wait queue
+func (s *PCFSServer) smUnlock(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	owner := entry.Command.ClientId
+	contract := &pb.UnlockContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode unlock contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		lock, err := GetAdvisoryLock(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		lock.Holders = withoutLockOwner(lock.Holders, owner)
+		lock.Waiters = withoutLockOwner(lock.Waiters, owner)
+		return SetAdvisoryLock(txn, group, lock)
+	}); err == nil {
+		log.Println("unlocked")
+		return []byte{1}
+	} else {
+		log.Println("cannot unlock:", err)
+		return []byte{0}
+	}
+}
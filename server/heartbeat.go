@@ -0,0 +1,124 @@
+package server
+
+import (
+	"errors"
+	bft "github.com/PomeloCloud/BFTRaft4go/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Every stash node sends heartbeats with it's disk usage to the stash group
+// The reported usage is the only source of HostStash.Used, it counts what is on disk,
+// 		snapshot copies and quarantined blocks included, block contracts don't adjust it
+// State of a stash is derived from the time it was last seen:
+// 		ONLINE -> SUSPECT after suspect timeout -> DEAD after dead timeout, any heartbeat brings it back ONLINE
+// MAINTENANCE is set by the owner for planned downtime, the stash never turns suspect or dead in it
+// DRAINING is set by the owner before the stash is retired, a draining stash still turns dead when silent
+// Only online stashes receive new blocks, repair only replaces replicas on dead stashes
+
+func (s *PCFSServer) StartHeartbeat(config FileConfig) {
+	interval := ParseDuration(config.HeartbeatInterval, 10*time.Second)
+	s.SuspectTimeout = ParseDuration(config.SuspectTimeout, time.Minute)
+	s.DeadTimeout = ParseDuration(config.DeadTimeout, 10*time.Minute)
+	log.Println("start heartbeat every", interval)
+	go func() {
+		for true {
+			s.SendHeartbeat()
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func (s *PCFSServer) SendHeartbeat() {
+	contract := &pb.StashHeartbeatContract{
+		HostId:     s.BFTRaft.Id,
+		Used:       s.LocalUsage(),
+		ClientTime: uint64(time.Now().UnixNano()),
+	}
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		log.Println("cannot encode heartbeat:", err)
+		return
+	}
+	res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, STASH_HEARTBEAT, contractData)
+	if err != nil || (*res)[0] != 1 {
+		log.Println("cannot send heartbeat:", err)
+	}
+}
+
+// bytes of block data stored on this node, quarantined blocks and copies kept for snapshots included
+func (s *PCFSServer) LocalUsage() uint64 {
+	var used uint64
+	s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		for _, keyPrefix := range [][]byte{
+			bft.ComposeKeyPrefix(3, BLOCKS), bft.ComposeKeyPrefix(3, QUARANTINE), bft.ComposeKeyPrefix(3, SNAPSHOT_BLOCKS),
+		} {
+			iter := txn.NewIterator(badger.IteratorOptions{})
+			for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+				if data, err := iter.Item().Value(); err == nil {
+					used += uint64(len(data))
+				}
+			}
+			iter.Close()
+		}
+		return nil
+	})
+	return used
+}
+
+func (s *PCFSServer) StashStateAt(stash *pb.HostStash, now time.Time) pb.StashState {
+	if stash.State == pb.StashState_MAINTENANCE {
+		return stash.State
+	}
+	silence := time.Duration(uint64(now.UnixNano()) - stash.LastSeen)
+	if stash.LastSeen > uint64(now.UnixNano()) {
+		silence = 0
+	}
+	if silence > s.DeadTimeout {
+		return pb.StashState_DEAD
+	} else if stash.State == pb.StashState_DRAINING {
+		return stash.State
+	} else if silence > s.SuspectTimeout {
+		return pb.StashState_SUSPECT
+	}
+	return pb.StashState_ONLINE
+}
+
+// current states of all stashes, unknown hosts are dead
+func (s *PCFSServer) StashStates() map[uint64]pb.StashState {
+	states := map[uint64]pb.StashState{}
+	now := time.Now()
+	s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		stashes, err := ListHostStashes(txn, STASH_GROUP)
+		if err != nil {
+			return err
+		}
+		for _, stash := range stashes {
+			states[stash.HostId] = s.StashStateAt(stash, now)
+		}
+		return nil
+	})
+	return states
+}
+
+func (s *PCFSServer) SetStashState(state pb.StashState) error {
+	contract := &pb.SetStashStateContract{
+		HostId: s.BFTRaft.Id,
+		State:  state,
+	}
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, SET_STASH_STATE, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("set stash state contract failed")
+	}
+	return nil
+}
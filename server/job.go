@@ -0,0 +1,277 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	bft "github.com/PomeloCloud/BFTRaft4go/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Changing replications of a volume starts a replication job for it
+// The job walks files of the volume in key order, adds replicas to blocks that have too few
+// and removes extra ones from blocks that have too many, keeping replicas spread across failure domains
+// The stash group leader runs jobs along with repair, the cursor and progress are recorded by contracts
+// so a new leader or a restarted one continues from the last file done
+// A new change of replications restarts the job from the beginning
+
+// files processed before progress is recorded
+const jobBatch = 16
+
+func GetReplicationJob(txn *badger.Txn, group uint64, volume []byte) (*pb.ReplicationJob, error) {
+	item, err := txn.Get(DBKey(group, JOBS, volume))
+	if err != nil {
+		return nil, err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return nil, err
+	}
+	job := &pb.ReplicationJob{}
+	if err := proto.Unmarshal(value, job); err != nil {
+		log.Println("cannot decode replication job:", err)
+		return nil, err
+	}
+	return job, nil
+}
+
+func SetReplicationJob(txn *badger.Txn, group uint64, job *pb.ReplicationJob) error {
+	data, err := proto.Marshal(job)
+	if err != nil {
+		log.Println("cannot encode replication job")
+		return err
+	}
+	return txn.Set(DBKey(group, JOBS, job.Volume), data, 0x00)
+}
+
+func StartReplicationJob(txn *badger.Txn, group uint64, volume *pb.Volume) error {
+	job := &pb.ReplicationJob{
+		Volume:       volume.Key,
+		Replications: volume.Replications,
+	}
+	if err := ForEachFile(txn, group, func(file *pb.FileMeta) error {
+		if bytes.Equal(file.Volume, volume.Key) {
+			job.Total++
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	log.Println("replication job started for volume", volume.Name, "to", volume.Replications, "replicas")
+	return SetReplicationJob(txn, group, job)
+}
+
+// progress of a job that was restarted with other replications is ignored
+func (s *PCFSServer) smReplicationJobProgress(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.ReplicationJob{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode job progress contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if !isStashOwner(txn, group, entry.Command.ClientId) {
+			return errors.New("only stash nodes can run jobs")
+		}
+		job, err := GetReplicationJob(txn, group, contract.Volume)
+		if err != nil {
+			return err
+		}
+		if job.Replications != contract.Replications {
+			return errors.New("replication job changed")
+		}
+		job.Cursor = contract.Cursor
+		job.Processed = contract.Processed
+		job.Done = contract.Done
+		return SetReplicationJob(txn, group, job)
+	}); err == nil {
+		return []byte{1}
+	} else {
+		log.Println("cannot record job progress:", err)
+		return []byte{0}
+	}
+}
+
+func (s *PCFSServer) listReplicationJobs() ([]*pb.ReplicationJob, error) {
+	jobs := []*pb.ReplicationJob{}
+	err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		keyPrefix := bft.ComposeKeyPrefix(STASH_GROUP, JOBS)
+		iter := txn.NewIterator(badger.IteratorOptions{})
+		defer iter.Close()
+		for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+			data, err := iter.Item().Value()
+			if err != nil {
+				return err
+			}
+			job := &pb.ReplicationJob{}
+			if err := proto.Unmarshal(data, job); err != nil {
+				return err
+			}
+			if !job.Done {
+				jobs = append(jobs, job)
+			}
+		}
+		return nil
+	})
+	return jobs, err
+}
+
+func (s *PCFSServer) RunReplicationJobs(throttle *Throttle) {
+	jobs, err := s.listReplicationJobs()
+	if err != nil {
+		log.Println("cannot list replication jobs:", err)
+		return
+	}
+	for _, job := range jobs {
+		if err := s.runReplicationJob(job, throttle); err != nil {
+			log.Println("replication job stopped, will resume:", err)
+		}
+	}
+}
+
+// next files of the job's volume after the cursor, the scan starts at the cursor and stops with a full batch
+func jobBatchFiles(txn *badger.Txn, job *pb.ReplicationJob) ([]*pb.FileMeta, error) {
+	files := []*pb.FileMeta{}
+	keyPrefix := bft.ComposeKeyPrefix(STASH_GROUP, FILE_META)
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	for iter.Seek(DBKey(STASH_GROUP, FILE_META, job.Cursor)); iter.ValidForPrefix(keyPrefix) && len(files) < jobBatch; iter.Next() {
+		data, err := iter.Item().Value()
+		if err != nil {
+			return nil, err
+		}
+		file := &pb.FileMeta{}
+		if err := proto.Unmarshal(data, file); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(file.Volume, job.Volume) && bytes.Compare(file.Key, job.Cursor) > 0 {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func (s *PCFSServer) runReplicationJob(job *pb.ReplicationJob, throttle *Throttle) error {
+	for !job.Done {
+		var files []*pb.FileMeta
+		if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+			batch, err := jobBatchFiles(txn, job)
+			files = batch
+			return err
+		}); err != nil {
+			return err
+		}
+		states := s.StashStates()
+		for _, file := range files {
+			for _, block := range file.Blocks {
+				replicas := &blockReplicas{
+					file:         file.Key,
+					index:        block.Index,
+					size:         file.BlockSize,
+					hosts:        block.Hosts,
+					hash:         block.Hash,
+					replications: job.Replications,
+					zones:        FileZones(file),
+				}
+				// files with their own replications are not affected by the volume
+				if file.Policy != nil && file.Policy.Replications != 0 {
+					replicas.replications = file.Policy.Replications
+				}
+				if err := s.adjustReplicas(replicas, states, throttle); err != nil {
+					log.Println("cannot adjust replicas of block", block.Index, "of file", file.Key, ":", err)
+				}
+			}
+			job.Cursor = file.Key
+			job.Processed++
+		}
+		job.Done = len(files) < jobBatch
+		if err := s.recordJobProgress(job); err != nil {
+			return err
+		}
+		log.Println("replication job progress:", job.Processed, "/", job.Total, "files")
+	}
+	log.Println("replication job done to", job.Replications, "replicas")
+	return nil
+}
+
+func (s *PCFSServer) recordJobProgress(job *pb.ReplicationJob) error {
+	contractData, err := proto.Marshal(job)
+	if err != nil {
+		return err
+	}
+	res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, JOB_PROGRESS, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("job progress contract failed")
+	}
+	return nil
+}
+
+func (s *PCFSServer) adjustReplicas(block *blockReplicas, states map[uint64]pb.StashState, throttle *Throttle) error {
+	live := []uint64{}
+	for _, hostId := range block.hosts {
+		if state, found := states[hostId]; found && state != pb.StashState_DEAD {
+			live = append(live, hostId)
+		}
+	}
+	if uint32(len(live)) < block.replications {
+		if len(live) == 0 {
+			return errors.New("block lost all replicas")
+		}
+		return s.repairBlock(block, live, states, throttle)
+	}
+	if uint32(len(block.hosts)) > block.replications {
+		return s.trimBlock(block, states)
+	}
+	return nil
+}
+
+// keeps online replicas in policy zones spread across failure domains, dead and other replicas go first
+func (s *PCFSServer) trimBlock(block *blockReplicas, states map[uint64]pb.StashState) error {
+	stashMap := map[uint64]*pb.HostStash{}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		stashes, err := ListHostStashes(txn, STASH_GROUP)
+		for _, stash := range stashes {
+			stashMap[stash.HostId] = stash
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	online := []*pb.HostStash{}
+	others := []*pb.HostStash{}
+	for _, hostId := range block.hosts {
+		stash, found := stashMap[hostId]
+		if !found {
+			continue
+		}
+		if states[hostId] == pb.StashState_ONLINE && InZones(block.zones, stash) {
+			online = append(online, stash)
+		} else if states[hostId] != pb.StashState_DEAD {
+			others = append(others, stash)
+		}
+	}
+	kept := SpreadStashes(online, []*pb.HostStash{}, int(block.replications))
+	kept = append(kept, SpreadStashes(others, kept, int(block.replications)-len(kept))...)
+	if len(kept) == 0 {
+		return errors.New("no replica can be kept")
+	}
+	newHosts := []uint64{}
+	for _, stash := range kept {
+		newHosts = append(newHosts, stash.HostId)
+	}
+	if err := s.replaceReplicas(block.file, block.index, block.hosts, newHosts); err != nil {
+		return err
+	}
+	for _, hostId := range block.hosts {
+		if !containsHost(newHosts, hostId) && states[hostId] != pb.StashState_DEAD {
+			s.deleteReplica(block, hostId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"context"
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"strconv"
+)
+
+// POSIX style permission bits on files and directories, checked against the node id of the caller
+// Owner bits apply to the owner, group bits to members of the user group, others bits to everyone else
+// Directories need x to be traversed, r to be listed and w+x to create items in them
+// Setback: there is no super user, only owners can change modes and give away their items
+
+const (
+	PERM_READ  = uint32(4)
+	PERM_WRITE = uint32(2)
+	PERM_EXEC  = uint32(1)
+)
+
+const (
+	DEFAULT_FILE_MODE = uint32(0644)
+	DEFAULT_DIR_MODE  = uint32(0755)
+)
+
+func ParseUmask(text string) uint32 {
+	if text == "" {
+		return 022
+	}
+	umask, err := strconv.ParseUint(text, 8, 32)
+	if err != nil {
+		panic(err)
+	}
+	return uint32(umask)
+}
+
+func GetUserGroup(txn *badger.Txn, group uint64, id uint64) (*pb.UserGroup, error) {
+	item, err := txn.Get(DBKey(group, USER_GROUPS, utils.U64Bytes(id)))
+	if err != nil {
+		return nil, err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return nil, err
+	}
+	userGroup := &pb.UserGroup{}
+	if err := proto.Unmarshal(value, userGroup); err != nil {
+		log.Println("cannot decode user group:", err)
+		return nil, err
+	}
+	return userGroup, nil
+}
+
+func SetUserGroup(txn *badger.Txn, group uint64, userGroup *pb.UserGroup) error {
+	data, err := proto.Marshal(userGroup)
+	if err != nil {
+		log.Println("cannot encode user group")
+		return err
+	}
+	return txn.Set(DBKey(group, USER_GROUPS, utils.U64Bytes(userGroup.Id)), data, 0x00)
+}
+
+// id of the user group with the name, 0 when there is none
+func UserGroupByName(txn *badger.Txn, group uint64, name string) (uint64, error) {
+	item, err := txn.Get(DBKey(group, GROUP_NAMES, []byte(name)))
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return 0, err
+	}
+	return utils.BytesU64(value, 0), nil
+}
+
+// new groups by name take the next id after the largest one
+func nextUserGroupId(txn *badger.Txn, group uint64) (uint64, error) {
+	keyPrefix := DBKey(group, USER_GROUPS, []byte{})
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	var id uint64
+	for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+		data, err := iter.Item().Value()
+		if err != nil {
+			return 0, err
+		}
+		userGroup := &pb.UserGroup{}
+		if err := proto.Unmarshal(data, userGroup); err != nil {
+			return 0, err
+		}
+		if userGroup.Id > id {
+			id = userGroup.Id
+		}
+	}
+	return id + 1, nil
+}
+
+func inUserGroup(txn *badger.Txn, group uint64, gid uint64, clientId uint64) bool {
+	if gid == 0 {
+		return false
+	}
+	userGroup, err := GetUserGroup(txn, group, gid)
+	if err != nil {
+		return false
+	}
+	return containsHost(userGroup.Members, clientId)
+}
+
+func HasPermission(txn *badger.Txn, group uint64, owner uint64, gid uint64, mode uint32, clientId uint64, want uint32) bool {
+	var bits uint32
+	if clientId == owner {
+		bits = (mode >> 6) & 7
+	} else if inUserGroup(txn, group, gid, clientId) {
+		bits = (mode >> 3) & 7
+	} else {
+		bits = mode & 7
+	}
+	return bits&want == want
+}
+
+// items created before permissions existed have no owner, they stay open to everyone as they were
+// ACLs of the directory of the file apply to it
+func CheckFileAccess(txn *badger.Txn, group uint64, file *pb.FileMeta, clientId uint64, want uint32) error {
+	mode := file.Mode
+	if file.Owner == 0 {
+		mode = 0666
+	}
+	if !HasPermission(txn, group, file.Owner, file.Group, mode, clientId, want) &&
+		!fileAclAllows(AclGrants(txn, group, file.Dir, clientId), want) {
+		return errors.New("permission denied on file " + file.Name)
+	}
+	return nil
+}
+
+func CheckDirAccess(txn *badger.Txn, group uint64, dir *pb.Directory, clientId uint64, want uint32) error {
+	mode := dir.Mode
+	if dir.Owner == 0 {
+		mode = 0777
+	}
+	if !HasPermission(txn, group, dir.Owner, dir.Group, mode, clientId, want) &&
+		!dirAclAllows(AclGrants(txn, group, dir.Key, clientId), want) {
+		return errors.New("permission denied on dir " + dir.Name)
+	}
+	return nil
+}
+
+// modes without any bit are from clients that don't set it, give them the default
+func modeOrDefault(mode uint32, defaultMode uint32) uint32 {
+	if mode == 0 {
+		return defaultMode
+	}
+	return mode & 0777
+}
+
+func (s *PCFSServer) smChmod(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.ChmodContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode chmod contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		now, err := LogClock(txn, group)
+		if err != nil {
+			return err
+		}
+		if contract.Dir {
+			dir, err := GetDirectory(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if dir.Owner != entry.Command.ClientId {
+				return errors.New("only owner can change mode")
+			}
+			dir.Mode = contract.Mode & 0777
+			dir.ChangedAt = now
+			return SetDirectory(txn, group, dir)
+		}
+		file, err := GetFile(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if file.Owner != entry.Command.ClientId {
+			return errors.New("only owner can change mode")
+		}
+		file.Mode = contract.Mode & 0777
+		file.ChangedAt = now
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("mode changed")
+		return []byte{1}
+	} else {
+		log.Println("cannot change mode:", err)
+		return []byte{0}
+	}
+}
+
+// the owner can hand over the item, and set it's group to one the owner is a member of
+func (s *PCFSServer) smChown(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.ChownContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode chown contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		now, err := LogClock(txn, group)
+		if err != nil {
+			return err
+		}
+		if contract.Group != 0 && !inUserGroup(txn, group, contract.Group, clientId) {
+			return errors.New("owner is not a member of the group")
+		}
+		if contract.Dir {
+			dir, err := GetDirectory(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if dir.Owner != clientId {
+				return errors.New("only owner can change owner")
+			}
+			dir.Owner = contract.Owner
+			dir.Group = contract.Group
+			dir.ChangedAt = now
+			return SetDirectory(txn, group, dir)
+		}
+		file, err := GetFile(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if file.Owner != clientId {
+			return errors.New("only owner can change owner")
+		}
+		file.Owner = contract.Owner
+		file.Group = contract.Group
+		file.ChangedAt = now
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("owner changed")
+		return []byte{1}
+	} else {
+		log.Println("cannot change owner:", err)
+		return []byte{0}
+	}
+}
+
+// creates a user group owned by the caller, or updates members and name of a group it owns
+// groups are found by name when the id is 0, an empty name keeps the current one
+func (s *PCFSServer) smSetUserGroup(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.UserGroup{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode user group contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		named, err := UserGroupByName(txn, group, contract.Name)
+		if err != nil {
+			return err
+		}
+		if contract.Id == 0 {
+			if contract.Name == "" {
+				return errors.New("user group id 0 is reserved")
+			}
+			if named != 0 {
+				contract.Id = named
+			} else if contract.Id, err = nextUserGroupId(txn, group); err != nil {
+				return err
+			}
+		} else if contract.Name != "" && named != 0 && named != contract.Id {
+			return errors.New("user group name " + contract.Name + " is taken")
+		}
+		if existing, err := GetUserGroup(txn, group, contract.Id); err == nil {
+			if existing.Owner != entry.Command.ClientId {
+				return errors.New("only owner can change user group")
+			}
+			if contract.Name == "" {
+				contract.Name = existing.Name
+			} else if existing.Name != "" && existing.Name != contract.Name {
+				if err := txn.Delete(DBKey(group, GROUP_NAMES, []byte(existing.Name))); err != nil {
+					return err
+				}
+			}
+		} else if err != badger.ErrKeyNotFound {
+			return err
+		}
+		if contract.Name != "" {
+			if err := txn.Set(DBKey(group, GROUP_NAMES, []byte(contract.Name)), utils.U64Bytes(contract.Id), 0x00); err != nil {
+				return err
+			}
+		}
+		contract.Owner = entry.Command.ClientId
+		return SetUserGroup(txn, group, contract)
+	}); err == nil {
+		log.Println("user group set")
+		return []byte{1}
+	} else {
+		log.Println("cannot set user group:", err)
+		return []byte{0}
+	}
+}
+
+func (s *PCFSServer) GetUserGroup(ctx context.Context, req *pb.GetUserGroupRequest) (*pb.UserGroup, error) {
+	if _, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature); err != nil {
+		log.Println("rejected get user group:", err)
+		return nil, err
+	}
+	var res *pb.UserGroup
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		id := req.Id
+		if id == 0 {
+			named, err := UserGroupByName(txn, req.Group, req.Name)
+			if err != nil {
+				return err
+			}
+			if named == 0 {
+				return errors.New("no user group named " + req.Name)
+			}
+			id = named
+		}
+		userGroup, err := GetUserGroup(txn, req.Group, id)
+		res = userGroup
+		return err
+	}); err != nil {
+		log.Println("cannot get user group:", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+)
+
+// Failure domains are nested: zone > rack > host
+// Stash nodes without labels fall into the same unnamed zone and rack, and each one is a host by it's own
+
+func zoneOf(stash *pb.HostStash) string {
+	return stash.Zone
+}
+
+func rackOf(stash *pb.HostStash) string {
+	return fmt.Sprint(stash.Zone, "/", stash.Rack)
+}
+
+func hostOf(stash *pb.HostStash) string {
+	if stash.Host == "" {
+		return fmt.Sprint(stash.Zone, "/", stash.Rack, "/", stash.HostId)
+	}
+	return fmt.Sprint(stash.Zone, "/", stash.Rack, "/", stash.Host)
+}
+
+type domainCounter struct {
+	zones map[string]int
+	racks map[string]int
+	hosts map[string]int
+}
+
+func newDomainCounter(stashes []*pb.HostStash) *domainCounter {
+	c := &domainCounter{
+		zones: map[string]int{},
+		racks: map[string]int{},
+		hosts: map[string]int{},
+	}
+	for _, stash := range stashes {
+		c.add(stash)
+	}
+	return c
+}
+
+func (c *domainCounter) add(stash *pb.HostStash) {
+	c.zones[zoneOf(stash)]++
+	c.racks[rackOf(stash)]++
+	c.hosts[hostOf(stash)]++
+}
+
+// lower is better, compare zone first, then rack and host
+func (c *domainCounter) crowded(stash *pb.HostStash, than *pb.HostStash) bool {
+	a := []int{c.zones[zoneOf(stash)], c.racks[rackOf(stash)], c.hosts[hostOf(stash)]}
+	b := []int{c.zones[zoneOf(than)], c.racks[rackOf(than)], c.hosts[hostOf(than)]}
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return false
+}
+
+// SpreadStashes picks up to num stashes from candidates, preferring failure domains that
+// have the fewest replicas so far, existing replicas included.
+// Candidates should come in key order so that every node picks the same stashes for the majority vote
+func SpreadStashes(candidates []*pb.HostStash, existing []*pb.HostStash, num int) []*pb.HostStash {
+	counter := newDomainCounter(existing)
+	picked := []*pb.HostStash{}
+	used := map[uint64]bool{}
+	for _, stash := range existing {
+		used[stash.HostId] = true
+	}
+	for len(picked) < num {
+		var best *pb.HostStash
+		for _, candidate := range candidates {
+			if used[candidate.HostId] {
+				continue
+			}
+			if best == nil || counter.crowded(best, candidate) {
+				best = candidate
+			}
+		}
+		if best == nil {
+			break
+		}
+		used[best.HostId] = true
+		counter.add(best)
+		picked = append(picked, best)
+	}
+	return picked
+}
+
+// DomainSpreadWarning checks if replicas on given hosts are spread across as many zones and racks
+// as the cluster can offer. Returns an empty string when they are
+func DomainSpreadWarning(txn *badger.Txn, group uint64, hostIds []uint64) string {
+	all, err := ListHostStashes(txn, group)
+	if err != nil {
+		return fmt.Sprint("cannot check replica spread: ", err)
+	}
+	stashes := map[uint64]*pb.HostStash{}
+	for _, stash := range all {
+		stashes[stash.HostId] = stash
+	}
+	replicas := []*pb.HostStash{}
+	for _, hostId := range hostIds {
+		if stash, found := stashes[hostId]; found {
+			replicas = append(replicas, stash)
+		}
+	}
+	cluster := newDomainCounter(all)
+	placed := newDomainCounter(replicas)
+	expected := func(available int) int {
+		if available < len(replicas) {
+			return available
+		}
+		return len(replicas)
+	}
+	if len(placed.zones) < expected(len(cluster.zones)) {
+		return fmt.Sprint("replicas placed in ", len(placed.zones), " zones, ", len(cluster.zones), " available")
+	}
+	if len(placed.racks) < expected(len(cluster.racks)) {
+		return fmt.Sprint("replicas placed in ", len(placed.racks), " racks, ", len(cluster.racks), " available")
+	}
+	return ""
+}
+
+// KeepsSpread checks if replicas after a move cover at least as many zones and racks as before
+func KeepsSpread(before []*pb.HostStash, after []*pb.HostStash) bool {
+	b := newDomainCounter(before)
+	a := newDomainCounter(after)
+	return len(a.zones) >= len(b.zones) && len(a.racks) >= len(b.racks)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Storage policies override replications, block size and placement zones of the volume
+// A new directory inherits the policy of it's parent, a new file inherits the policy of it's directory
+// and both can override fields at creation. Changing a directory policy only applies to children created afterwards
+// Zero replications of a file follow the volume, so volume replication jobs still cover it
+
+// fields set in override replace the ones in base
+func MergePolicy(base *pb.StoragePolicy, override *pb.StoragePolicy) *pb.StoragePolicy {
+	if base == nil && override == nil {
+		return nil
+	}
+	policy := &pb.StoragePolicy{}
+	for _, p := range []*pb.StoragePolicy{base, override} {
+		if p == nil {
+			continue
+		}
+		if p.Replications != 0 {
+			policy.Replications = p.Replications
+		}
+		if p.BlockSize != 0 {
+			policy.BlockSize = p.BlockSize
+		}
+		if len(p.Zones) > 0 {
+			policy.Zones = p.Zones
+		}
+	}
+	return policy
+}
+
+func ValidatePolicy(txn *badger.Txn, group uint64, volume *pb.Volume, policy *pb.StoragePolicy) error {
+	if policy == nil {
+		return nil
+	}
+	replications := volume.Replications
+	if policy.Replications != 0 {
+		replications = policy.Replications
+	}
+	blockSize := volume.BlockSize
+	if policy.BlockSize != 0 {
+		blockSize = policy.BlockSize
+	}
+	if err := ValidateVolumeOptions(txn, group, replications, blockSize); err != nil {
+		return err
+	}
+	if len(policy.Zones) == 0 {
+		return nil
+	}
+	stashes, err := ListHostStashes(txn, group)
+	if err != nil {
+		return err
+	}
+	inZones := 0
+	for _, stash := range stashes {
+		if InZones(policy.Zones, stash) {
+			inZones++
+		}
+	}
+	if replications > uint32(inZones) {
+		return errors.New(fmt.Sprint("replications ", replications, " exceeds ", inZones, " stash nodes in zones ", policy.Zones))
+	}
+	return nil
+}
+
+// empty zones allow any stash
+func InZones(zones []string, stash *pb.HostStash) bool {
+	if len(zones) == 0 {
+		return true
+	}
+	for _, zone := range zones {
+		if zoneOf(stash) == zone {
+			return true
+		}
+	}
+	return false
+}
+
+func FileReplications(file *pb.FileMeta, volume *pb.Volume) uint32 {
+	if file.Policy != nil && file.Policy.Replications != 0 {
+		return file.Policy.Replications
+	}
+	return volume.Replications
+}
+
+func FileZones(file *pb.FileMeta) []string {
+	if file.Policy == nil {
+		return nil
+	}
+	return file.Policy.Zones
+}
+
+// owner or admin of the directory sets it's policy for children created afterwards
+func (s *PCFSServer) smSetPolicy(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.SetPolicyContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode set policy contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		dir, err := GetDirectory(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if !canAdminDir(txn, group, dir, entry.Command.ClientId) {
+			return errors.New("only owner or admin can set policy")
+		}
+		volume, err := GetVolume(txn, group, dir.Volume)
+		if err != nil {
+			return err
+		}
+		if err := ValidatePolicy(txn, group, volume, contract.Policy); err != nil {
+			return err
+		}
+		dir.Policy = contract.Policy
+		if dir.ChangedAt, err = LogClock(txn, group); err != nil {
+			return err
+		}
+		return SetDirectory(txn, group, dir)
+	}); err == nil {
+		log.Println("policy set")
+		return []byte{1}
+	} else {
+		log.Println("cannot set policy:", err)
+		return []byte{0}
+	}
+}
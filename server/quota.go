@@ -0,0 +1,172 @@
+package server
+
+import (
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Quotas limit bytes and number of files and directories in a directory subtree
+// The quota of a volume is the quota of it's root directory
+// Every directory keeps usage of it's subtree, updated along the parent chain by contracts that create,
+// grow, move and delete items. Usage of trees created before it was tracked is nil, it's counted when read
+// and saved when a quota is set or the dir is moved or trashed, charges leave uncounted dirs as they are
+// Contracts refused by a quota return QUOTA_EXCEEDED instead of 0
+
+const QUOTA_EXCEEDED = 2
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+func quotaResult(err error) []byte {
+	if err == ErrQuotaExceeded {
+		return []byte{QUOTA_EXCEEDED}
+	}
+	return []byte{0}
+}
+
+func addUsage(value uint64, delta int64) uint64 {
+	if delta < 0 && uint64(-delta) > value {
+		return 0
+	}
+	return uint64(int64(value) + delta)
+}
+
+func exceedsQuota(dir *pb.Directory, bytes int64, files int64, dirs int64) bool {
+	if dir.Quota == nil {
+		return false
+	}
+	usage := dir.Usage
+	if usage == nil {
+		usage = &pb.DirUsage{}
+	}
+	if bytes > 0 && dir.Quota.Bytes != 0 && addUsage(usage.Bytes, bytes) > dir.Quota.Bytes {
+		return true
+	}
+	if files+dirs > 0 && dir.Quota.Files != 0 && addUsage(usage.Files+usage.Dirs, files+dirs) > dir.Quota.Files {
+		return true
+	}
+	return false
+}
+
+// ChargeQuota checks and adds usage to dir and all it's ancestors, negative deltas are never refused.
+// Ancestors are saved, dir is only changed in place for the caller to save it with it's other changes
+func ChargeQuota(txn *badger.Txn, group uint64, dir *pb.Directory, bytes int64, files int64, dirs int64) error {
+	return chargeChain(txn, group, dir, bytes, files, dirs, true)
+}
+
+// trashed items stay charged to the volume root, only the dirs below it are charged
+func chargeBelowRoot(txn *badger.Txn, group uint64, dir *pb.Directory, bytes int64, files int64, dirs int64) error {
+	return chargeChain(txn, group, dir, bytes, files, dirs, false)
+}
+
+func chargeChain(txn *badger.Txn, group uint64, dir *pb.Directory, bytes int64, files int64, dirs int64, root bool) error {
+	chain := []*pb.Directory{dir}
+	for parentKey := dir.Parent; len(parentKey) > 0; {
+		parent, err := GetDirectory(txn, group, parentKey)
+		if err != nil {
+			return err
+		}
+		chain = append(chain, parent)
+		parentKey = parent.Parent
+	}
+	if !root {
+		chain = chain[:len(chain)-1]
+	}
+	for _, d := range chain {
+		if exceedsQuota(d, bytes, files, dirs) {
+			log.Println("quota of dir", d.Name, "exceeded")
+			return ErrQuotaExceeded
+		}
+	}
+	for i, d := range chain {
+		if d.Usage == nil {
+			continue
+		}
+		d.Usage.Bytes = addUsage(d.Usage.Bytes, bytes)
+		d.Usage.Files = addUsage(d.Usage.Files, files)
+		d.Usage.Dirs = addUsage(d.Usage.Dirs, dirs)
+		if i > 0 {
+			if err := SetDirectory(txn, group, d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// usage of an uncounted dir is counted from it's subtree, the caller saves it when it can
+func CountedUsage(txn *badger.Txn, group uint64, dir *pb.Directory) (*pb.DirUsage, error) {
+	if dir.Usage != nil {
+		return dir.Usage, nil
+	}
+	return SubtreeUsage(txn, group, dir)
+}
+
+// walks the subtree, files and directories in it are counted, not the dir itself
+func SubtreeUsage(txn *badger.Txn, group uint64, dir *pb.Directory) (*pb.DirUsage, error) {
+	usage := &pb.DirUsage{}
+	for _, token := range dir.Files {
+		if token[0] == byte(pb.DirectoryItem_DIR) {
+			usage.Dirs++
+			child, err := GetDirectory(txn, group, token[1:])
+			if err != nil {
+				return nil, err
+			}
+			childUsage, err := SubtreeUsage(txn, group, child)
+			if err != nil {
+				return nil, err
+			}
+			usage.Bytes += childUsage.Bytes
+			usage.Files += childUsage.Files
+			usage.Dirs += childUsage.Dirs
+		} else {
+			usage.Files++
+			file, err := GetFile(txn, group, token[1:])
+			if err != nil {
+				return nil, err
+			}
+			usage.Bytes += file.Size
+		}
+	}
+	return usage, nil
+}
+
+// only the owner or an admin of the volume sets quotas, zero quota removes the limit
+func (s *PCFSServer) smSetQuota(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.SetQuotaContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode set quota contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		dir, err := GetDirectory(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		volume, err := GetVolume(txn, group, dir.Volume)
+		if err != nil {
+			return err
+		}
+		if !canAdminVolume(txn, group, volume, entry.Command.ClientId) {
+			return errors.New("only owner or admin of the volume can set quota")
+		}
+		if dir.Usage, err = SubtreeUsage(txn, group, dir); err != nil {
+			return err
+		}
+		dir.Quota = contract.Quota
+		if dir.Quota != nil && dir.Quota.Bytes == 0 && dir.Quota.Files == 0 {
+			dir.Quota = nil
+		}
+		return SetDirectory(txn, group, dir)
+	}); err == nil {
+		log.Println("quota set")
+		return []byte{1}
+	} else {
+		log.Println("cannot set quota:", err)
+		return []byte{0}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/c2h5oh/datasize"
 	"github.com/golang/protobuf/proto"
 	"log"
+	"time"
 )
 
 func (s *PCFSServer) CheckStashGroup(join bool) {
@@ -36,12 +37,18 @@ func (s *PCFSServer) RegisterNode(config FileConfig) {
 	if err != nil {
 		panic(err)
 	}
-	log.Println("trying to register this node as stash with capacity:", c.HumanReadable())
+	log.Println("trying to register this node as stash with capacity:", c.HumanReadable(),
+		"zone:", config.Zone, "rack:", config.Rack, "host:", config.Host)
 	host := &pb.HostStash{
 		HostId:   s.BFTRaft.Id,
 		Capacity: c.Bytes(),
 		Used:     0,
 		Owner:    s.BFTRaft.Id,
+		Zone:     config.Zone,
+		Rack:     config.Rack,
+		Host:     config.Host,
+		LastSeen: uint64(time.Now().UnixNano()),
+		Address:  config.TLS.Address,
 	}
 	hostData, err := proto.Marshal(host)
 	if err != nil {
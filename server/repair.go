@@ -0,0 +1,320 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Repair finds blocks which have less live replicas than the volume requires
+// and copy them host-to-host to new stash nodes suggested by SuggestBlockStash.
+// New host lists are committed by the replace replicas contract.
+// Only the leader of the stash group runs the repair, other nodes skip the round
+// Setback: a stash is considered dead only after it missed heartbeats for the dead timeout
+
+type blockReplicas struct {
+	file         []byte
+	index        uint64
+	size         uint32
+	hosts        []uint64
+	hash         []byte
+	replications uint32
+	// placement zones of the file policy, empty for any
+	zones []string
+}
+
+func (s *PCFSServer) StashLeader() uint64 {
+	group := s.BFTRaft.GetOnboardGroup(STASH_GROUP)
+	if group == nil {
+		return 0
+	}
+	return group.Leader
+}
+
+func (s *PCFSServer) IsStashLeader() bool {
+	return s.StashLeader() == s.BFTRaft.Id
+}
+
+func (s *PCFSServer) isStash(hostId uint64) bool {
+	registered := false
+	s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		_, err := GetHostStash(txn, STASH_GROUP, hostId)
+		registered = err == nil
+		return nil
+	})
+	return registered
+}
+
+func (s *PCFSServer) StartRepair(config FileConfig) {
+	interval := ParseDuration(config.RepairInterval, time.Minute)
+	throttle := NewThrottle(ParseBandwidth(config.RepairBandwidth, 10*1024*1024))
+	log.Println("start block repair every", interval)
+	go func() {
+		for true {
+			time.Sleep(interval)
+			if s.IsStashLeader() {
+				s.RepairBlocks(throttle)
+				s.RunReplicationJobs(throttle)
+				s.PurgeExpiredTrash()
+			}
+		}
+	}()
+}
+
+func (s *PCFSServer) RepairBlocks(throttle *Throttle) {
+	blocks, err := s.scanBlockReplicas()
+	if err != nil {
+		log.Println("cannot scan blocks for repair:", err)
+		return
+	}
+	states := s.StashStates()
+	repaired := 0
+	for _, block := range blocks {
+		// suspect and maintenance stashes still hold their replicas, only dead ones need replacement
+		live := []uint64{}
+		for _, hostId := range block.hosts {
+			if state, found := states[hostId]; found && state != pb.StashState_DEAD {
+				live = append(live, hostId)
+			}
+		}
+		if uint32(len(live)) >= block.replications {
+			continue
+		}
+		if len(live) == 0 {
+			log.Println("block", block.index, "of file", block.file, "lost all replicas")
+			continue
+		}
+		if err := s.repairBlock(block, live, states, throttle); err == nil {
+			repaired++
+		} else {
+			log.Println("cannot repair block", block.index, "of file", block.file, ":", err)
+		}
+	}
+	log.Println("repair round finished, repaired", repaired, "blocks")
+}
+
+func (s *PCFSServer) scanBlockReplicas() ([]*blockReplicas, error) {
+	group := STASH_GROUP
+	blocks := []*blockReplicas{}
+	err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		volumes := map[string]*pb.Volume{}
+		return ForEachFile(txn, group, func(file *pb.FileMeta) error {
+			vol, found := volumes[string(file.Volume)]
+			if !found {
+				vol, _ = GetVolume(txn, group, file.Volume)
+				volumes[string(file.Volume)] = vol
+			}
+			if vol == nil {
+				log.Println("cannot find volume of file:", file.Name)
+				return nil
+			}
+			for _, block := range file.Blocks {
+				blocks = append(blocks, &blockReplicas{
+					file:         file.Key,
+					index:        block.Index,
+					size:         file.BlockSize,
+					hosts:        block.Hosts,
+					hash:         block.Hash,
+					replications: FileReplications(file, vol),
+					zones:        FileZones(file),
+				})
+			}
+			return nil
+		})
+	})
+	return blocks, err
+}
+
+func (s *PCFSServer) repairBlock(block *blockReplicas, live []uint64, states map[uint64]pb.StashState, throttle *Throttle) error {
+	dead := []uint64{}
+	for _, hostId := range block.hosts {
+		if !containsHost(live, hostId) {
+			dead = append(dead, hostId)
+		}
+	}
+	// only online and draining replicas can serve as copy source
+	sources := []uint64{}
+	for _, hostId := range live {
+		if states[hostId] == pb.StashState_ONLINE || states[hostId] == pb.StashState_DRAINING {
+			sources = append(sources, hostId)
+		}
+	}
+	if len(sources) == 0 {
+		return errors.New("no online replica to copy from")
+	}
+	need := int(block.replications) - len(live)
+	suggestion, err := s.SuggestBlockStash(context.Background(), &pb.BlockStashSuggestionRequest{
+		Group:    STASH_GROUP,
+		Num:      uint32(need + len(dead)),
+		Existing: live,
+		Zones:    block.zones,
+	})
+	if err != nil {
+		return err
+	}
+	added := []uint64{}
+	for _, stash := range suggestion.Nodes {
+		if len(added) >= need {
+			break
+		}
+		if containsHost(dead, stash.HostId) || states[stash.HostId] != pb.StashState_ONLINE {
+			continue
+		}
+		source := sources[len(added)%len(sources)]
+		if err := s.copyBlock(block, source, stash.HostId, throttle); err != nil {
+			log.Println("cannot copy block to", stash.HostId, ":", err)
+			continue
+		}
+		added = append(added, stash.HostId)
+	}
+	if len(added) == 0 {
+		return errors.New("no stash available for new replicas")
+	}
+	// dead hosts are only dropped when they got replaced, they may come back
+	newHosts := append(append(append([]uint64{}, live...), added...), dead[minInt(len(added), len(dead)):]...)
+	return s.replaceReplicas(block.file, block.index, block.hosts, newHosts)
+}
+
+func (s *PCFSServer) copyBlock(block *blockReplicas, source uint64, target uint64, throttle *Throttle) error {
+	host := s.BFTRaft.GetHostNTXN(target)
+	if host == nil {
+		return errors.New("cannot find target host")
+	}
+	client := s.GetPeerRPC(host)
+	if client == nil {
+		return errors.New("cannot connect target host")
+	}
+	throttle.Wait(uint64(block.size))
+	req := &pb.ReplicateBlockRequest{
+		Group:    STASH_GROUP,
+		Index:    block.index,
+		File:     block.file,
+		Source:   source,
+		Hash:     block.hash,
+		ClientId: s.BFTRaft.Id,
+	}
+	if err := s.SignRequest(req, &req.Signature); err != nil {
+		return err
+	}
+	res, err := client.ReplicateBlock(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	if !res.Succeed {
+		return errors.New("replicate block failed")
+	}
+	return nil
+}
+
+func (s *PCFSServer) replaceReplicas(file []byte, index uint64, oldHosts []uint64, newHosts []uint64) error {
+	contract := &pb.ReplaceBlockReplicasContract{
+		File:     file,
+		Index:    index,
+		OldHosts: oldHosts,
+		NewHosts: newHosts,
+	}
+	contractData, err := proto.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, REPLACE_REPLICAS, contractData)
+	if err != nil {
+		return err
+	}
+	if (*res)[0] != 1 {
+		return errors.New("replace replicas contract failed")
+	}
+	return nil
+}
+
+// readers report replicas they could not heal, the leader drops the ones it finds diverged too for repair to replace
+func (s *PCFSServer) ReportReplicas(ctx context.Context, req *pb.ReportReplicasRequest) (*pb.WriteResult, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected replica report:", err)
+		return nil, err
+	}
+	if !s.IsStashLeader() {
+		return nil, errors.New("replicas are reported to the stash leader")
+	}
+	meta, err := s.GetMajorityFileMeta(req.Group, req.File)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Hash) == 0 || req.Index >= uint64(len(meta.Blocks)) || !bytes.Equal(meta.Blocks[req.Index].Hash, req.Hash) {
+		return nil, errors.New("block changed since read")
+	}
+	hosts := meta.Blocks[req.Index].Hosts
+	diverged := []uint64{}
+	for _, hostId := range req.Hosts {
+		if containsHost(hosts, hostId) && !containsHost(diverged, hostId) &&
+			s.replicaDiverged(req.Group, req.File, req.Index, req.Hash, hostId) {
+			diverged = append(diverged, hostId)
+		}
+	}
+	newHosts := []uint64{}
+	for _, hostId := range hosts {
+		if !containsHost(diverged, hostId) {
+			newHosts = append(newHosts, hostId)
+		}
+	}
+	if len(diverged) == 0 || len(newHosts) == 0 {
+		return &pb.WriteResult{Succeed: false}, nil
+	}
+	log.Println("replicas", diverged, "of block", req.Index, "reported by", clientId, "diverged")
+	if err := s.replaceReplicas(req.File, req.Index, hosts, newHosts); err != nil {
+		return nil, err
+	}
+	return &pb.WriteResult{Succeed: true}, nil
+}
+
+// only a replica answering with other content counts, unreachable ones are left to the dead timeout
+func (s *PCFSServer) replicaDiverged(group uint64, file []byte, index uint64, hash []byte, hostId uint64) bool {
+	host := s.BFTRaft.GetHostNTXN(hostId)
+	if host == nil {
+		return false
+	}
+	client := s.GetPeerRPC(host)
+	if client == nil {
+		return false
+	}
+	req := &pb.GetBlockRequest{
+		Group:    group,
+		Index:    index,
+		File:     file,
+		ClientId: s.BFTRaft.Id,
+	}
+	if err := s.SignRequest(req, &req.Signature); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	block, err := client.GetBlock(ctx, req)
+	if err != nil {
+		return false
+	}
+	blockHash, _ := utils.SHA1Hash(block.Data)
+	return !bytes.Equal(blockHash, hash)
+}
+
+func containsHost(hosts []uint64, hostId uint64) bool {
+	for _, h := range hosts {
+		if h == hostId {
+			return true
+		}
+	}
+	return false
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
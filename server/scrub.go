@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	bft "github.com/PomeloCloud/BFTRaft4go/server"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Scrubber runs on every stash node, it re-hashes local blocks and compare them with hashes in file meta data
+// Corrupted replicas are moved into quarantine and then healed from a healthy replica
+// Setback: a block written but not hashed by it's writer yet looks corrupted,
+// 			so mismatched blocks are checked against refreshed meta data again before quarantine
+
+type localBlock struct {
+	group uint64
+	index uint64
+	file  []byte
+}
+
+func (s *PCFSServer) StartScrubber(config FileConfig) {
+	interval := ParseDuration(config.ScrubInterval, 24*time.Hour)
+	log.Println("start block scrubber every", interval)
+	go func() {
+		for true {
+			time.Sleep(interval)
+			s.ScrubBlocks()
+		}
+	}()
+}
+
+func (s *PCFSServer) ScrubBlocks() {
+	blocks := []*localBlock{}
+	keyPrefix := bft.ComposeKeyPrefix(3, BLOCKS)
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.IteratorOptions{})
+		defer iter.Close()
+		for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+			data, err := iter.Item().Value()
+			if err != nil {
+				return err
+			}
+			block := &pb.BlockData{}
+			if err := proto.Unmarshal(data, block); err != nil {
+				return err
+			}
+			blocks = append(blocks, &localBlock{group: block.Group, index: block.Index, file: block.File})
+		}
+		return nil
+	}); err != nil {
+		log.Println("cannot list local blocks for scrub:", err)
+		return
+	}
+	metas := map[string]*pb.FileMeta{}
+	corrupted := 0
+	for _, block := range blocks {
+		meta, found := metas[string(block.file)]
+		if !found {
+			meta, _ = s.GetMajorityFileMeta(block.group, block.file)
+			metas[string(block.file)] = meta
+		}
+		if meta == nil || block.index >= uint64(len(meta.Blocks)) {
+			continue
+		}
+		if !s.blockCorrupted(block, meta.Blocks[block.index].Hash) {
+			continue
+		}
+		// the writer may have just updated the hash
+		meta, err := s.GetMajorityFileMeta(block.group, block.file)
+		if err != nil || block.index >= uint64(len(meta.Blocks)) || !s.blockCorrupted(block, meta.Blocks[block.index].Hash) {
+			continue
+		}
+		corrupted++
+		log.Println("block", block.index, "of file", block.file, "is corrupted")
+		s.quarantineAndHeal(block, meta.Blocks[block.index])
+	}
+	log.Println("scrub finished,", len(blocks), "blocks checked,", corrupted, "corrupted")
+}
+
+func (s *PCFSServer) blockCorrupted(block *localBlock, expected []byte) bool {
+	if len(expected) == 0 {
+		return false
+	}
+	corrupted := false
+	s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		data, err := GetBlockData(txn, block.group, block.file, block.index)
+		if err != nil {
+			return err
+		}
+		hash, _ := utils.SHA1Hash(data.Data)
+		corrupted = !bytes.Equal(hash, expected)
+		return nil
+	})
+	return corrupted
+}
+
+func (s *PCFSServer) quarantineAndHeal(block *localBlock, meta *pb.Block) {
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		data, err := GetBlockData(txn, block.group, block.file, block.index)
+		if err != nil {
+			return err
+		}
+		return QuarantineBlock(txn, data)
+	}); err != nil {
+		log.Println("cannot quarantine block:", err)
+		return
+	}
+	for _, hostId := range meta.Hosts {
+		if hostId == s.BFTRaft.Id {
+			continue
+		}
+		req := &pb.ReplicateBlockRequest{
+			Group:    block.group,
+			Index:    block.index,
+			File:     block.file,
+			Source:   hostId,
+			Hash:     meta.Hash,
+			ClientId: s.BFTRaft.Id,
+		}
+		if err := s.SignRequest(req, &req.Signature); err != nil {
+			log.Println("cannot sign replicate block request:", err)
+			return
+		}
+		if _, err := s.ReplicateBlock(context.Background(), req); err == nil {
+			log.Println("block", block.index, "of file", block.file, "healed from", hostId)
+			return
+		}
+	}
+	log.Println("cannot find healthy replica for block", block.index, "of file", block.file)
+}
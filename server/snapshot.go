@@ -0,0 +1,569 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"strings"
+)
+
+// Snapshots freeze the directory tree of a volume at a point in time
+// Meta data is copy-on-write: taking a snapshot only records it, live dirs and files stand for themselves in it
+// until they change. Before a dir or file is overwritten or deleted, it's stored version is frozen under every
+// snapshot of the volume taken after it was created that doesn't have it yet, so a snapshot costs what changed.
+// Files under a write lease are frozen when the lease is taken or the snapshot is, before stashes overwrite blocks
+// Block data is shared with live files, every content referenced by frozen files is counted by (file, index, hash)
+// Stash nodes copy their replica aside before overwriting or deleting a block whose content is still
+// referenced, so snapshot reads ask for the content hash and get either the live or the kept copy
+// Snapshots are read only and browsed under "/<volume>/.snapshots/<name>"
+// Deleting a snapshot releases contents no other snapshot references, their kept copies are deleted by the client
+// Kept copies are moved off draining stashes, the frozen meta data is pointed at the new host
+// Setback: kept copies are not repaired, a lost host loses the copies only it kept
+
+const SNAPSHOTS_DIR = ".snapshots"
+
+func SnapshotKey(volume []byte, name string) []byte {
+	hash, _ := utils.SHA1Hash(append(append([]byte{}, volume...), name...))
+	return hash
+}
+
+func snapshotDBKey(group uint64, volume []byte, name string) []byte {
+	return DBKey(group, SNAPSHOTS, append(append([]byte{}, volume...), name...))
+}
+
+func snapshotItemDBKey(group uint64, t uint32, snapshot []byte, key []byte) []byte {
+	return DBKey(group, t, append(append([]byte{}, snapshot...), key...))
+}
+
+func blockRefDBKey(group uint64, file []byte, index uint64, hash []byte) []byte {
+	return DBKey(group, SNAPSHOT_REFS, append(append(append([]byte{}, file...), utils.U64Bytes(index)...), hash...))
+}
+
+// the volume root of the tree is where the reserved snapshots dir appears
+func reservedName(dir *pb.Directory, name string) bool {
+	return len(dir.Parent) == 0 && name == SNAPSHOTS_DIR
+}
+
+func getProto(txn *badger.Txn, key []byte, msg proto.Message) error {
+	item, err := txn.Get(key)
+	if err != nil {
+		return err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(value, msg)
+}
+
+func setProto(txn *badger.Txn, key []byte, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return txn.Set(key, data, 0x00)
+}
+
+func GetSnapshot(txn *badger.Txn, group uint64, volume []byte, name string) (*pb.Snapshot, error) {
+	snapshot := &pb.Snapshot{}
+	if err := getProto(txn, snapshotDBKey(group, volume, name), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func ListSnapshots(txn *badger.Txn, group uint64, volume []byte) ([]*pb.Snapshot, error) {
+	snapshots := []*pb.Snapshot{}
+	keyPrefix := snapshotDBKey(group, volume, "")
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+		data, err := iter.Item().Value()
+		if err != nil {
+			return nil, err
+		}
+		snapshot := &pb.Snapshot{}
+		if err := proto.Unmarshal(data, snapshot); err != nil {
+			return nil, err
+		}
+		// names sharing a prefix with other volume keys are not ours
+		if bytes.Equal(snapshot.Volume, volume) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// the frozen dir, or the live one while it has not changed since the snapshot
+func GetSnapshotDir(txn *badger.Txn, group uint64, snapshot []byte, key []byte) (*pb.Directory, error) {
+	dir := &pb.Directory{}
+	if err := getProto(txn, snapshotItemDBKey(group, SNAPSHOT_DIRS, snapshot, key), dir); err == badger.ErrKeyNotFound {
+		return GetDirectory(txn, group, key)
+	} else if err != nil {
+		return nil, err
+	}
+	return dir, nil
+}
+
+func GetSnapshotFile(txn *badger.Txn, group uint64, snapshot []byte, key []byte) (*pb.FileMeta, error) {
+	file := &pb.FileMeta{}
+	if err := getProto(txn, snapshotItemDBKey(group, SNAPSHOT_FILES, snapshot, key), file); err == badger.ErrKeyNotFound {
+		return GetFile(txn, group, key)
+	} else if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func BlockRefs(txn *badger.Txn, group uint64, file []byte, index uint64, hash []byte) (uint64, error) {
+	item, err := txn.Get(blockRefDBKey(group, file, index, hash))
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return 0, err
+	}
+	return utils.BytesU64(value, 0), nil
+}
+
+// returns references left
+func AdjustBlockRefs(txn *badger.Txn, group uint64, file []byte, index uint64, hash []byte, delta int64) (uint64, error) {
+	refs, err := BlockRefs(txn, group, file, index, hash)
+	if err != nil {
+		return 0, err
+	}
+	refs = addUsage(refs, delta)
+	key := blockRefDBKey(group, file, index, hash)
+	if refs == 0 {
+		return 0, txn.Delete(key)
+	}
+	return refs, txn.Set(key, utils.U64Bytes(refs), 0x00)
+}
+
+// snapshots of the volume still sharing the live item, those taken after it was created and before it changed
+func sharingSnapshots(txn *badger.Txn, group uint64, volume []byte, t uint32, key []byte, createdAt uint64) ([][]byte, error) {
+	snapshots, err := ListSnapshots(txn, group, volume)
+	if err != nil {
+		return nil, err
+	}
+	keys := [][]byte{}
+	for _, snapshot := range snapshots {
+		if createdAt > snapshot.CreatedAt {
+			continue
+		}
+		if _, err := txn.Get(snapshotItemDBKey(group, t, snapshot.Key, key)); err == nil {
+			continue
+		} else if err != badger.ErrKeyNotFound {
+			return nil, err
+		}
+		keys = append(keys, snapshot.Key)
+	}
+	return keys, nil
+}
+
+// freezes the stored version of the dir, called before it's overwritten or deleted
+func freezeDir(txn *badger.Txn, group uint64, key []byte) error {
+	dir := &pb.Directory{}
+	if err := getProto(txn, DBKey(group, DIRECTORY, key), dir); err == badger.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	snapshots, err := sharingSnapshots(txn, group, dirVolume(dir), SNAPSHOT_DIRS, dir.Key, dir.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err := setProto(txn, snapshotItemDBKey(group, SNAPSHOT_DIRS, snapshot, dir.Key), dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freezes the stored version of the file and references it's block contents
+func freezeFile(txn *badger.Txn, group uint64, key []byte) error {
+	file := &pb.FileMeta{}
+	if err := getProto(txn, DBKey(group, FILE_META, key), file); err == badger.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	snapshots, err := sharingSnapshots(txn, group, file.Volume, SNAPSHOT_FILES, file.Key, file.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err := setProto(txn, snapshotItemDBKey(group, SNAPSHOT_FILES, snapshot, file.Key), file); err != nil {
+			return err
+		}
+		for _, block := range file.Blocks {
+			if _, err := AdjustBlockRefs(txn, group, file.Key, block.Index, block.Hash, 1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// files of the volume being written may get blocks overwritten any time, they are frozen with the snapshot
+func freezeLeasedFiles(txn *badger.Txn, group uint64, volume []byte, now uint64) error {
+	keyPrefix := DBKey(group, FILE_LOCK, nil)
+	for _, key := range prefixKeys(txn, keyPrefix) {
+		lock := &pb.FileWriteLock{}
+		if err := getProto(txn, key, lock); err != nil {
+			return err
+		}
+		if lock.Expires <= now {
+			continue
+		}
+		file, err := GetFile(txn, group, lock.Key)
+		if err != nil || !bytes.Equal(file.Volume, volume) {
+			continue
+		}
+		if err := freezeFile(txn, group, file.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PCFSServer) smSnapshotVolume(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.SnapshotContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode snapshot contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if contract.Name == "" || strings.Contains(contract.Name, "/") {
+			return errors.New("invalid snapshot name")
+		}
+		volume, err := GetVolume(txn, group, contract.Volume)
+		if err != nil {
+			return err
+		}
+		if !canAdminVolume(txn, group, volume, entry.Command.ClientId) {
+			return errors.New("only owner or admin can snapshot volume")
+		}
+		if _, err := GetSnapshot(txn, group, volume.Key, contract.Name); err == nil {
+			return errors.New("snapshot already exists")
+		} else if err != badger.ErrKeyNotFound {
+			return err
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		snapshot := &pb.Snapshot{
+			Key:       SnapshotKey(volume.Key, contract.Name),
+			Volume:    volume.Key,
+			Name:      contract.Name,
+			RootDir:   volume.RootDir,
+			CreatedAt: now,
+		}
+		if err := setProto(txn, snapshotDBKey(group, volume.Key, snapshot.Name), snapshot); err != nil {
+			return err
+		}
+		return freezeLeasedFiles(txn, group, volume.Key, now)
+	}); err == nil {
+		log.Println("snapshot", contract.Name, "created")
+		return []byte{1}
+	} else {
+		log.Println("cannot create snapshot:", err)
+		return []byte{0}
+	}
+}
+
+func prefixKeys(txn *badger.Txn, keyPrefix []byte) [][]byte {
+	keys := [][]byte{}
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+		keys = append(keys, append([]byte{}, iter.Item().Key()...))
+	}
+	return keys
+}
+
+// returns released block contents with the hosts that may keep a copy of them
+func (s *PCFSServer) smDeleteSnapshot(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.DeleteSnapshotContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode delete snapshot contract:", err)
+		return []byte{0}
+	}
+	released := &pb.DeletedFiles{}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		volume, err := GetVolume(txn, group, contract.Volume)
+		if err != nil {
+			return err
+		}
+		if !canAdminVolume(txn, group, volume, entry.Command.ClientId) {
+			return errors.New("only owner or admin can delete snapshot")
+		}
+		snapshot, err := GetSnapshot(txn, group, volume.Key, contract.Name)
+		if err != nil {
+			return err
+		}
+		for _, key := range prefixKeys(txn, snapshotItemDBKey(group, SNAPSHOT_FILES, snapshot.Key, nil)) {
+			file := &pb.FileMeta{}
+			if err := getProto(txn, key, file); err != nil {
+				return err
+			}
+			live, _ := GetFile(txn, group, file.Key)
+			blocks := []*pb.Block{}
+			for _, block := range file.Blocks {
+				refs, err := AdjustBlockRefs(txn, group, file.Key, block.Index, block.Hash, -1)
+				if err != nil {
+					return err
+				}
+				if refs > 0 {
+					continue
+				}
+				if live != nil && block.Index < uint64(len(live.Blocks)) {
+					for _, hostId := range live.Blocks[block.Index].Hosts {
+						if !containsHost(block.Hosts, hostId) {
+							block.Hosts = append(block.Hosts, hostId)
+						}
+					}
+				}
+				blocks = append(blocks, block)
+			}
+			if len(blocks) > 0 {
+				released.Files = append(released.Files, &pb.FileMeta{Key: file.Key, Name: file.Name, Blocks: blocks})
+			}
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		for _, key := range prefixKeys(txn, snapshotItemDBKey(group, SNAPSHOT_DIRS, snapshot.Key, nil)) {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return txn.Delete(snapshotDBKey(group, volume.Key, snapshot.Name))
+	}); err == nil {
+		log.Println("snapshot", contract.Name, "deleted")
+		resData, _ := proto.Marshal(released)
+		return resData
+	} else {
+		log.Println("cannot delete snapshot:", err)
+		return []byte{0}
+	}
+}
+
+// the holder of the kept copy is replaced when the block in the meta data has the content
+func moveKeptHost(file *pb.FileMeta, contract *pb.MoveKeptBlockContract) bool {
+	if contract.Index >= uint64(len(file.Blocks)) {
+		return false
+	}
+	block := file.Blocks[contract.Index]
+	if !bytes.Equal(block.Hash, contract.Hash) || !containsHost(block.Hosts, contract.From) {
+		return false
+	}
+	hosts := []uint64{contract.To}
+	for _, hostId := range block.Hosts {
+		if hostId != contract.From && hostId != contract.To {
+			hosts = append(hosts, hostId)
+		}
+	}
+	block.Hosts = hosts
+	return true
+}
+
+// points snapshots and versions having the content at the stash it's kept copy moved to
+func (s *PCFSServer) smMoveKeptBlock(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.MoveKeptBlockContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode move kept block contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		stash, err := GetHostStash(txn, group, contract.From)
+		if err != nil {
+			return err
+		}
+		if stash.Owner != entry.Command.ClientId {
+			return errors.New("kept block not moved by stash owner")
+		}
+		if _, err := GetHostStash(txn, group, contract.To); err != nil {
+			return err
+		}
+		for _, key := range prefixKeys(txn, DBKey(group, SNAPSHOTS, nil)) {
+			snapshot := &pb.Snapshot{}
+			if err := getProto(txn, key, snapshot); err != nil {
+				return err
+			}
+			fileKey := snapshotItemDBKey(group, SNAPSHOT_FILES, snapshot.Key, contract.File)
+			file := &pb.FileMeta{}
+			if err := getProto(txn, fileKey, file); err == badger.ErrKeyNotFound {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if moveKeptHost(file, contract) {
+				if err := setProto(txn, fileKey, file); err != nil {
+					return err
+				}
+			}
+		}
+		versions, err := ListFileVersions(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if moveKeptHost(version.Meta, contract) {
+				if err := setProto(txn, versionDBKey(group, version.File, version.Number), version); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err == nil {
+		log.Println("kept block moved to", contract.To)
+		return []byte{1}
+	} else {
+		log.Println("cannot move kept block:", err)
+		return []byte{0}
+	}
+}
+
+// copies the local replica aside before it's overwritten or deleted, if a snapshot still references it's content
+func preserveSnapshotBlock(txn *badger.Txn, group uint64, file []byte, index uint64) error {
+	item, err := txn.Get(BlockDBKey(group, file, index))
+	if err == badger.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	data, err := item.Value()
+	if err != nil {
+		return err
+	}
+	block := &pb.BlockData{}
+	if err := proto.Unmarshal(data, block); err != nil {
+		return err
+	}
+	hash, _ := utils.SHA1Hash(block.Data)
+	if refs, err := BlockRefs(txn, group, file, index, hash); err != nil || refs == 0 {
+		return err
+	}
+	return txn.Set(SnapshotBlockDBKey(group, file, index, hash), data, 0x00)
+}
+
+// the live block when it still has the content, or the copy kept for snapshots
+func GetSnapshotBlockData(txn *badger.Txn, group uint64, file []byte, index uint64, hash []byte) (*pb.BlockData, error) {
+	if block, err := GetBlockData(txn, group, file, index); err == nil {
+		if liveHash, _ := utils.SHA1Hash(block.Data); bytes.Equal(liveHash, hash) {
+			return block, nil
+		}
+	}
+	block := &pb.BlockData{}
+	if err := getProto(txn, SnapshotBlockDBKey(group, file, index, hash), block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// lists snapshots of the volume, or a directory inside one of them with it's frozen permissions
+func listSnapshotPath(txn *badger.Txn, group uint64, volume *pb.Volume, parts []string, clientId uint64,
+	res *pb.ListDirectoryResponse) error {
+	rootDir, err := GetDirectory(txn, group, volume.RootDir)
+	if err != nil {
+		return err
+	}
+	if err := CheckDirAccess(txn, group, rootDir, clientId, PERM_READ|PERM_EXEC); err != nil {
+		return err
+	}
+	names := []string{}
+	for _, part := range parts {
+		if part != "" {
+			names = append(names, part)
+		}
+	}
+	res.Volume = volume
+	if len(names) == 0 {
+		snapshots, err := ListSnapshots(txn, group, volume.Key)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots {
+			dir, err := GetSnapshotDir(txn, group, snapshot.Key, snapshot.RootDir)
+			if err != nil {
+				return err
+			}
+			dir.Name = snapshot.Name
+			res.Items = append(res.Items, &pb.DirectoryItem{
+				Type: pb.DirectoryItem_DIR,
+				File: &pb.FileMeta{},
+				Dir:  dir,
+			})
+		}
+		res.Name = SNAPSHOTS_DIR
+		res.Dir = &pb.Directory{Name: SNAPSHOTS_DIR, Volume: volume.Key}
+		return nil
+	}
+	snapshot, err := GetSnapshot(txn, group, volume.Key, names[0])
+	if err != nil {
+		return errors.New("cannot find snapshot " + names[0])
+	}
+	dir, err := GetSnapshotDir(txn, group, snapshot.Key, snapshot.RootDir)
+	if err != nil {
+		return err
+	}
+NAME:
+	for _, name := range names[1:] {
+		if err := CheckDirAccess(txn, group, dir, clientId, PERM_EXEC); err != nil {
+			return err
+		}
+		for _, token := range dir.Files {
+			if token[0] != byte(pb.DirectoryItem_DIR) {
+				continue
+			}
+			sub, err := GetSnapshotDir(txn, group, snapshot.Key, token[1:])
+			if err != nil {
+				return err
+			}
+			if sub.Name == name {
+				dir = sub
+				continue NAME
+			}
+		}
+		return errors.New("cannot find dir")
+	}
+	if err := CheckDirAccess(txn, group, dir, clientId, PERM_READ|PERM_EXEC); err != nil {
+		return err
+	}
+	for _, token := range dir.Files {
+		var item *pb.DirectoryItem
+		if token[0] == byte(pb.DirectoryItem_DIR) {
+			sub, err := GetSnapshotDir(txn, group, snapshot.Key, token[1:])
+			if err != nil {
+				return err
+			}
+			item = &pb.DirectoryItem{Type: pb.DirectoryItem_DIR, File: &pb.FileMeta{}, Dir: sub}
+		} else {
+			file, err := GetSnapshotFile(txn, group, snapshot.Key, token[1:])
+			if err != nil {
+				return err
+			}
+			item = &pb.DirectoryItem{Type: pb.DirectoryItem_FILE, File: file, Dir: &pb.Directory{}}
+		}
+		res.Items = append(res.Items, item)
+	}
+	res.Key = dir.Key
+	res.Name = dir.Name
+	res.Dir = dir
+	res.Snapshot = snapshot
+	return nil
+}
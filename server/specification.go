@@ -15,7 +15,7 @@ package server
 // File: meta data for files. block index, name size, timestamps etc
 // Directory: indices for files. may also contain sub-directory if required
 // Volume: indices for volume. unspecified, will be used for user control
-// HostStash: meta data to track node storage capacity.
+// HostStash: meta data to track node storage capacity and it's failure domain labels (zone, rack, host).
 // 			  only the node can create and alter it's own stash data with signed client request
 
 // The BFTRaft beta group will store all of those meta data but not those blocks binary data.
@@ -38,3 +38,5 @@ package server
 //      then renew it every 1 minute to prevent it from been obsolete
 // A WriteLock should contain node id, expire time, and a client signature.
 // To write a block, stash server will check for existence of the WriteLock and then verify it
+// Expire time is measured by the log clock, the largest client time seen by lock and heartbeat contracts
+// 		so every node expires a lock at the same log entry
@@ -1,21 +1,37 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	bft "github.com/PomeloCloud/BFTRaft4go/server"
 	"github.com/PomeloCloud/BFTRaft4go/utils"
 	pb "github.com/PomeloCloud/pcfs/proto"
 	"github.com/dgraph-io/badger"
 	"github.com/golang/protobuf/proto"
 	"log"
 	"strings"
+	"time"
 )
 
+// readers need read permission on the file, stash nodes holding a replica of the block can always read it
 func (s *PCFSServer) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.BlockData, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected block read:", err)
+		return nil, err
+	}
+	if err := s.checkBlockRead(req, clientId); err != nil {
+		log.Println("rejected block read from", clientId, ":", err)
+		return nil, err
+	}
 	var res *pb.BlockData
 	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		if len(req.Hash) > 0 {
+			bd, err := GetSnapshotBlockData(txn, req.Group, req.File, req.Index, req.Hash)
+			res = bd
+			return err
+		}
 		if bd, err := GetBlockData(txn, req.Group, req.File, req.Index); err == nil {
 			res = bd
 		} else {
@@ -32,8 +48,17 @@ func (s *PCFSServer) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb
 }
 
 func (s *PCFSServer) SetBlock(ctx context.Context, data *pb.BlockData) (*pb.WriteResult, error) {
+	if err := s.authorizeBlockWrite(ctx, data); err != nil {
+		return nil, err
+	}
+	// signature is only for the request, don't store it
+	data.ClientId = 0
+	data.Signature = nil
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
 		if _, err := GetBlockData(txn, data.Group, data.File, data.Index); err == nil {
+			if err := preserveSnapshotBlock(txn, data.Group, data.File, data.Index); err != nil {
+				return err
+			}
 			return SetBlock(txn, data)
 		} else {
 			return err
@@ -53,17 +78,38 @@ func (s *PCFSServer) SetBlock(ctx context.Context, data *pb.BlockData) (*pb.Writ
 	}
 }
 
+// readers need read permission on the file
+// other stash nodes get the meta data block RPCs and permission checks need, without name and attributes
 func (s *PCFSServer) GetFileMeta(ctx context.Context, req *pb.GetFileRequest) (*pb.FileMeta, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected get file meta:", err)
+		return nil, err
+	}
 	var res *pb.FileMeta
 	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
-		if bd, err := GetFile(txn, req.Group, req.File); err == nil {
+		bd, err := GetFile(txn, req.Group, req.File)
+		if err != nil {
+			return err
+		}
+		if clientId == s.BFTRaft.Id || clientId == s.StashLeader() {
 			res = bd
-		} else {
+			return nil
+		}
+		if err := CheckFileAccess(txn, req.Group, bd, clientId, PERM_READ); err == nil {
+			res = bd
+			return nil
+		} else if _, stashErr := GetHostStash(txn, req.Group, clientId); stashErr != nil {
 			return err
 		}
+		bd.Name = ""
+		bd.Xattrs = nil
+		res = bd
 		return nil
 	}); err == nil {
 		return res, nil
+	} else if err == badger.ErrKeyNotFound {
+		return nil, ErrFileNotFound
 	} else {
 		msg := fmt.Sprint("cannot get the file: ", err)
 		log.Println(msg)
@@ -71,14 +117,41 @@ func (s *PCFSServer) GetFileMeta(ctx context.Context, req *pb.GetFileRequest) (*
 	}
 }
 
+func (s *PCFSServer) GetFileWriteLock(ctx context.Context, req *pb.GetFileRequest) (*pb.FileWriteLock, error) {
+	var res *pb.FileWriteLock
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		if lock, err := GetLiveWriteLock(txn, req.Group, req.File); err == nil {
+			res = lock
+		} else {
+			return err
+		}
+		return nil
+	}); err == nil {
+		return res, nil
+	} else {
+		msg := fmt.Sprint("cannot get the file lock: ", err)
+		log.Println(msg)
+		return nil, errors.New(msg)
+	}
+}
+
+// volumes are visible to their owners and to who can read their root dir, like in ListVolumes
 func (s *PCFSServer) GetVolume(ctx context.Context, req *pb.GetVolumeRequest) (*pb.Volume, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected get volume:", err)
+		return nil, err
+	}
 	var res *pb.Volume
 	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
-		if bd, err := GetVolume(txn, req.Group, IdFromName(req.Name)); err == nil {
-			res = bd
-		} else {
+		bd, err := ResolveVolume(txn, req.Group, req.Name, clientId)
+		if err != nil {
 			return err
 		}
+		if !volumeVisible(txn, req.Group, bd, clientId) {
+			return errors.New("volume not visible to client")
+		}
+		res = bd
 		return nil
 	}); err == nil {
 		return res, nil
@@ -90,13 +163,27 @@ func (s *PCFSServer) GetVolume(ctx context.Context, req *pb.GetVolumeRequest) (*
 }
 
 func (s *PCFSServer) GetDirectory(ctx context.Context, req *pb.GetDirectoryRequest) (*pb.Directory, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected get dir:", err)
+		return nil, err
+	}
 	var res *pb.Directory
 	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
-		if bd, err := GetDirectory(txn, req.Group, req.Key); err == nil {
-			res = bd
-		} else {
+		bd, err := GetDirectory(txn, req.Group, req.Key)
+		if err != nil {
+			return err
+		}
+		if clientId != s.BFTRaft.Id && clientId != s.StashLeader() {
+			if err := CheckDirAccess(txn, req.Group, bd, clientId, PERM_READ); err != nil {
+				return err
+			}
+		}
+		// uncounted usage is counted for the reply, it's saved by contracts
+		if bd.Usage, err = CountedUsage(txn, req.Group, bd); err != nil {
 			return err
 		}
+		res = bd
 		return nil
 	}); err == nil {
 		return res, nil
@@ -109,88 +196,111 @@ func (s *PCFSServer) GetDirectory(ctx context.Context, req *pb.GetDirectoryReque
 
 // naive implementation
 func (s *PCFSServer) ListDirectory(ctx context.Context, req *pb.ListDirectoryRequest) (*pb.ListDirectoryResponse, error) {
-	addrParts := strings.Split(req.Path, "/")
-	group := req.Group
-	volumeName := addrParts[1]
-	volumeKey := IdFromName(volumeName)
 	res := &pb.ListDirectoryResponse{}
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected list dir:", err)
+		return nil, err
+	}
 	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
-		volume, err := GetVolume(txn, group, volumeKey)
+		return listPath(txn, req.Group, req.Path, clientId, res)
+	}); err == nil {
+		if !req.Xattrs {
+			stripListXattrs(res)
+		}
+		return res, nil
+	} else {
+		log.Println("error on getting dir:", err)
+		return nil, err
+	}
+}
+
+func listPath(txn *badger.Txn, group uint64, dirPath string, clientId uint64, res *pb.ListDirectoryResponse) error {
+	addrParts := strings.Split(dirPath, "/")
+	if len(addrParts) < 2 {
+		return errors.New("invalid path")
+	}
+	volumeName := addrParts[1]
+	volume, err := ResolveVolume(txn, group, volumeName, clientId)
+	if err != nil {
+		log.Println("cannot get volume for list dir")
+		return err
+	}
+	if len(addrParts) > 2 && addrParts[2] == SNAPSHOTS_DIR {
+		return listSnapshotPath(txn, group, volume, addrParts[3:], clientId, res)
+	}
+	parentDirKey := volume.RootDir
+ADDRPART:
+	for i := 2; i < len(addrParts); i++ {
+		if addrParts[i] == "" {
+			continue
+		}
+		parentDir, err := GetDirectory(txn, group, parentDirKey)
 		if err != nil {
-			log.Println("cannot get volume for list dir")
+			log.Println("cannot get dir:", err)
 			return err
 		}
-		parentDirKey := volume.RootDir
-	ADDRPART:
-		for i := 2; i < len(addrParts); i++ {
-			if addrParts[i] == "" {
-				continue
+		if err := CheckDirAccess(txn, group, parentDir, clientId, PERM_EXEC); err != nil {
+			return err
+		}
+		for _, file := range parentDir.Files {
+			if file[0] == byte(pb.DirectoryItem_DIR) {
+				dirKey := file[1:]
+				subDir, err := GetDirectory(txn, group, dirKey)
+				if err != nil {
+					log.Println("cannot iter over parent, missing sub:", subDir, "error:", err)
+					continue
+				}
+				if subDir.Name == addrParts[i] {
+					parentDirKey = subDir.Key
+					continue ADDRPART
+				}
 			}
-			parentDir, err := GetDirectory(txn, group, parentDirKey)
+		}
+		return errors.New("cannot find dir")
+	}
+	dir, err := GetDirectory(txn, group, parentDirKey)
+	if err != nil {
+		return errors.New("cannot get dir for target dir")
+	}
+	if err := CheckDirAccess(txn, group, dir, clientId, PERM_READ|PERM_EXEC); err != nil {
+		return err
+	}
+	items := []*pb.DirectoryItem{}
+	for _, file := range dir.Files {
+		t := file[0]
+		k := file[1:]
+		var item *pb.DirectoryItem
+		switch t {
+		case byte(pb.DirectoryItem_DIR):
+			subDir, err := GetDirectory(txn, group, k)
 			if err != nil {
-				log.Println("cannot get dir:", err)
 				return err
 			}
-			for _, file := range parentDir.Files {
-				if file[0] == byte(pb.DirectoryItem_DIR) {
-					dirKey := file[1:]
-					subDir, err := GetDirectory(txn, group, dirKey)
-					if err != nil {
-						log.Println("cannot iter over parent, missing sub:", subDir, "error:", err)
-						continue
-					}
-					if subDir.Name == addrParts[i] {
-						parentDirKey = subDir.Key
-						continue ADDRPART
-					}
-				}
+			item = &pb.DirectoryItem{
+				Type: pb.DirectoryItem_DIR,
+				File: &pb.FileMeta{},
+				Dir:  subDir,
 			}
-			return errors.New("cannot find dir")
-		}
-		dir, err := GetDirectory(txn, group, parentDirKey)
-		if err != nil {
-			return errors.New("cannot get dir for target dir")
-		}
-		items := []*pb.DirectoryItem{}
-		for _, file := range dir.Files {
-			t := file[0]
-			k := file[1:]
-			var item *pb.DirectoryItem
-			switch t {
-			case byte(pb.DirectoryItem_DIR):
-				subDir, err := GetDirectory(txn, group, k)
-				if err != nil {
-					return err
-				}
-				item = &pb.DirectoryItem{
-					Type: pb.DirectoryItem_DIR,
-					File: &pb.FileMeta{},
-					Dir:  subDir,
-				}
-			case byte(pb.DirectoryItem_FILE):
-				subFile, err := GetFile(txn, group, k)
-				if err != nil {
-					return err
-				}
-				item = &pb.DirectoryItem{
-					Type: pb.DirectoryItem_FILE,
-					File: subFile,
-					Dir:  &pb.Directory{},
-				}
+		case byte(pb.DirectoryItem_FILE):
+			subFile, err := GetFile(txn, group, k)
+			if err != nil {
+				return err
+			}
+			item = &pb.DirectoryItem{
+				Type: pb.DirectoryItem_FILE,
+				File: subFile,
+				Dir:  &pb.Directory{},
 			}
-			items = append(items, item)
 		}
-		res.Key = dir.Key
-		res.Items = items
-		res.Name = dir.Name
-		res.Volume = volume
-		return nil
-	}); err == nil {
-		return res, nil
-	} else {
-		log.Println("error on getting dir:", err)
-		return nil, err
+		items = append(items, item)
 	}
+	res.Key = dir.Key
+	res.Items = items
+	res.Name = dir.Name
+	res.Volume = volume
+	res.Dir = dir
+	return nil
 }
 
 // Following RPCs are for block stash servers
@@ -202,6 +312,18 @@ func (s *PCFSServer) AppendToBlock(ctx context.Context, req *pb.AppendToBlockReq
 	offset := req.Offset
 	remains := uint64(len(data))
 	blockHash := []byte{}
+	if err := s.CheckPeer(ctx, req.ClientId); err != nil {
+		log.Println("rejected append from", req.ClientId, ":", err)
+		return nil, err
+	}
+	if err := s.VerifyRequest(req.ClientId, req, &req.Signature); err != nil {
+		log.Println("rejected unsigned or forged append from", req.ClientId, ":", err)
+		return nil, err
+	}
+	if err := s.CheckWriteLease(group, req.File, req.ClientId); err != nil {
+		log.Println("rejected append without lease from", req.ClientId, ":", err)
+		return nil, err
+	}
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
 		block, err := GetBlockData(txn, group, req.File, req.Index)
 		dataIdx := 0
@@ -219,6 +341,9 @@ func (s *PCFSServer) AppendToBlock(ctx context.Context, req *pb.AppendToBlockReq
 			dataIdx++
 		}
 		remains = uint64(len(data) - dataIdx)
+		if err := preserveSnapshotBlock(txn, group, req.File, req.Index); err != nil {
+			return err
+		}
 		SetBlock(txn, block)
 		blockHash, _ = utils.SHA1Hash(block.Data)
 		return nil
@@ -235,19 +360,31 @@ func (s *PCFSServer) AppendToBlock(ctx context.Context, req *pb.AppendToBlockReq
 
 func (s *PCFSServer) CreateBlock(ctx context.Context, req *pb.CreateBlockRequest) (*pb.WriteResult, error) {
 	group := req.Group
+	if err := s.CheckPeer(ctx, req.ClientId); err != nil {
+		log.Println("rejected create block from", req.ClientId, ":", err)
+		return nil, err
+	}
+	if err := s.VerifyRequest(req.ClientId, req, &req.Signature); err != nil {
+		log.Println("rejected unsigned or forged create block from", req.ClientId, ":", err)
+		return nil, err
+	}
+	if err := s.CheckWriteLease(group, req.File, req.ClientId); err != nil {
+		log.Println("rejected create block without lease from", req.ClientId, ":", err)
+		return nil, err
+	}
 	fileMeta, err := s.GetMajorityFileMeta(group, req.File)
 	if err != nil {
 		log.Println("cannot get block file meta:", err)
 		return nil, err
 	}
 	blockDBKey := BlockDBKey(group, req.File, req.Index)
+	block := &pb.BlockData{
+		Group: req.Group,
+		Index: req.Index,
+		File:  req.File,
+		Data:  make([]byte, fileMeta.BlockSize),
+	}
 	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
-		block := &pb.BlockData{
-			Group: req.Group,
-			Index: req.Index,
-			File:  req.File,
-			Data:  make([]byte, fileMeta.BlockSize),
-		}
 		if _, err := txn.Get(blockDBKey); err != badger.ErrKeyNotFound {
 			return errors.New("block already exists")
 		} else {
@@ -272,10 +409,11 @@ func (s *PCFSServer) CreateBlock(ctx context.Context, req *pb.CreateBlockRequest
 		switch (*res)[0] {
 		case 1:
 			log.Println("confirm block succeed")
+			blockHash, _ := utils.SHA1Hash(block.Data)
 			return &pb.WriteResult{
 				Succeed:   true,
 				Remains:   0,
-				BlockHash: []byte{},
+				BlockHash: blockHash,
 			}, nil
 		default:
 			msg := "confirm block failed:"
@@ -290,45 +428,69 @@ func (s *PCFSServer) CreateBlock(ctx context.Context, req *pb.CreateBlockRequest
 	}
 }
 
+// invoked after the block have been removed from the file meta data, like moved to other stash
+// with a hash, the copy kept for snapshots is deleted after no snapshot references it
+// live blocks are only deleted when the group agrees the file meta data doesn't reference them here anymore
 func (s *PCFSServer) DeleteBlock(ctx context.Context, req *pb.DeleteBlockRequest) (*pb.WriteResult, error) {
-	return nil, errors.New("unimplemented")
+	if err := s.VerifyRequest(req.ClientId, req, &req.Signature); err != nil {
+		log.Println("rejected unsigned or forged delete block from", req.ClientId, ":", err)
+		return nil, err
+	}
+	if len(req.Hash) == 0 && !s.BlockUnreferenced(req.Group, req.File, req.Index, s.BFTRaft.Id) {
+		return nil, errors.New("block may still be referenced by file meta data")
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if len(req.Hash) > 0 {
+			if refs, err := BlockRefs(txn, req.Group, req.File, req.Index, req.Hash); err != nil {
+				return err
+			} else if refs > 0 {
+				return errors.New("block content is still referenced by snapshots")
+			}
+			return txn.Delete(SnapshotBlockDBKey(req.Group, req.File, req.Index, req.Hash))
+		}
+		if err := preserveSnapshotBlock(txn, req.Group, req.File, req.Index); err != nil {
+			return err
+		}
+		return txn.Delete(BlockDBKey(req.Group, req.File, req.Index))
+	}); err == nil {
+		log.Println("delete block successful")
+		return &pb.WriteResult{
+			Succeed: true,
+			Remains: 0,
+		}, nil
+	} else {
+		log.Println("cannot delete block:", err)
+		return nil, err
+	}
 }
 
+// Suggested hosts are spread across failure domains, the client should take them in order
 func (s *PCFSServer) SuggestBlockStash(ctx context.Context, req *pb.BlockStashSuggestionRequest) (*pb.BlockStashSuggestion, error) {
 	hosts := []*pb.HostStash{}
 	remainRquired := _10MB
 	group := req.Group
-	startKey := DBKey(group, STASH, utils.U64Bytes(0))
-	keyPrefix := bft.ComposeKeyPrefix(group, STASH)
 	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
-		iter := txn.NewIterator(badger.IteratorOptions{})
-		iter.Seek(startKey)
-		for true {
-			if uint32(len(hosts)) >= req.Num {
-				break
-			}
-			if iter.ValidForPrefix(keyPrefix) {
-				hostData, err := iter.Item().Value()
-				if err == nil {
-					host := pb.HostStash{}
-					if err := proto.Unmarshal(hostData, &host); err == nil {
-						if host.Capacity-host.Used > uint64(remainRquired) {
-							hosts = append(hosts, &host)
-						}
-					} else {
-						log.Println("error on decoding stash value:", err)
-						break
-					}
-				} else {
-					log.Println("error on get stash value:", err)
-					break
-				}
-				iter.Next()
-			} else {
-				break
+		stashes, err := ListHostStashes(txn, group)
+		if err != nil {
+			return err
+		}
+		existingIds := map[uint64]bool{}
+		for _, hostId := range req.Existing {
+			existingIds[hostId] = true
+		}
+		candidates := []*pb.HostStash{}
+		existing := []*pb.HostStash{}
+		now := time.Now()
+		for _, host := range stashes {
+			if existingIds[host.HostId] {
+				existing = append(existing, host)
+			} else if s.StashStateAt(host, now) != pb.StashState_ONLINE || !InZones(req.Zones, host) {
+				continue
+			} else if host.Capacity-host.Used > uint64(remainRquired) {
+				candidates = append(candidates, host)
 			}
 		}
-		iter.Close()
+		hosts = SpreadStashes(candidates, existing, int(req.Num))
 		return nil
 	}); err == nil {
 		log.Println("sent", len(hosts), "stash hosts")
@@ -338,3 +500,79 @@ func (s *PCFSServer) SuggestBlockStash(ctx context.Context, req *pb.BlockStashSu
 		return nil, err
 	}
 }
+
+// Copy a block from the source stash into this node, used to re-replicate blocks
+// Invoked by repair and balancer, data will not go through the client
+// replicas are pulled on request of the stash leader, or of a stash holding the block
+// the pulled content must have the hash recorded in file meta data
+func (s *PCFSServer) ReplicateBlock(ctx context.Context, req *pb.ReplicateBlockRequest) (*pb.WriteResult, error) {
+	if len(req.Hash) == 0 {
+		return nil, errors.New("replicate block needs the block hash")
+	}
+	if req.Kept {
+		if err := s.checkKeptReplication(req); err != nil {
+			log.Println("rejected replicate kept block from", req.ClientId, ":", err)
+			return nil, err
+		}
+	} else {
+		meta, err := s.GetMajorityFileMeta(req.Group, req.File)
+		if err != nil {
+			return nil, err
+		}
+		if req.Index >= uint64(len(meta.Blocks)) || !bytes.Equal(meta.Blocks[req.Index].Hash, req.Hash) {
+			return nil, errors.New("block hash does not match file meta data")
+		}
+		if err := s.checkReplication(req, req.Group, req.File, req.Index, meta.Blocks[req.Index].Hosts); err != nil {
+			log.Println("rejected replicate block from", req.ClientId, ":", err)
+			return nil, err
+		}
+	}
+	source := s.BFTRaft.GetHostNTXN(req.Source)
+	if source == nil {
+		return nil, errors.New("cannot find replication source host")
+	}
+	client := s.GetPeerRPC(source)
+	if client == nil {
+		return nil, errors.New("cannot connect replication source host")
+	}
+	blockReq := &pb.GetBlockRequest{
+		Group:     req.Group,
+		Index:     req.Index,
+		File:      req.File,
+		ClientId:  s.BFTRaft.Id,
+		Replicate: req,
+		// the source may hold the content live or kept aside
+		Hash: req.Hash,
+	}
+	if err := s.SignRequest(blockReq, &blockReq.Signature); err != nil {
+		return nil, err
+	}
+	block, err := client.GetBlock(ctx, blockReq)
+	if err != nil {
+		log.Println("cannot get block from replication source:", err)
+		return nil, err
+	}
+	blockHash, _ := utils.SHA1Hash(block.Data)
+	if !bytes.Equal(blockHash, req.Hash) {
+		msg := "replication source block hash mismatch"
+		log.Println(msg)
+		return nil, errors.New(msg)
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if req.Kept {
+			return setProto(txn, SnapshotBlockDBKey(req.Group, req.File, req.Index, req.Hash), block)
+		}
+		if err := preserveSnapshotBlock(txn, block.Group, block.File, block.Index); err != nil {
+			return err
+		}
+		return SetBlock(txn, block)
+	}); err != nil {
+		log.Println("cannot store replicated block:", err)
+		return nil, err
+	}
+	return &pb.WriteResult{
+		Succeed:   true,
+		Remains:   0,
+		BlockHash: blockHash,
+	}, nil
+}
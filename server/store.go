@@ -1,12 +1,14 @@
 package server
 
 import (
+	"errors"
 	bft "github.com/PomeloCloud/BFTRaft4go/server"
 	"github.com/PomeloCloud/BFTRaft4go/utils"
 	pb "github.com/PomeloCloud/pcfs/proto"
 	"github.com/dgraph-io/badger"
 	"github.com/golang/protobuf/proto"
 	"log"
+	"math"
 )
 
 func DBKey(group uint64, t uint32, key []byte) []byte {
@@ -23,6 +25,16 @@ func BlockDBKey(group uint64, file []byte, index uint64) []byte {
 	return DBKey(3, BLOCKS, append(append(utils.U64Bytes(group), utils.U64Bytes(index)...), file...))
 }
 
+// corrupted blocks found by scrubber are kept aside for inspection
+func QuarantineDBKey(group uint64, file []byte, index uint64) []byte {
+	return DBKey(3, QUARANTINE, append(append(utils.U64Bytes(group), utils.U64Bytes(index)...), file...))
+}
+
+// block contents kept for snapshots after the live block changed
+func SnapshotBlockDBKey(group uint64, file []byte, index uint64, hash []byte) []byte {
+	return DBKey(3, SNAPSHOT_BLOCKS, append(append(append(utils.U64Bytes(group), utils.U64Bytes(index)...), file...), hash...))
+}
+
 func GetDirectory(txn *badger.Txn, group uint64, key []byte) (*pb.Directory, error) {
 	dbkey := DBKey(group, DIRECTORY, key)
 	dirItem, err := txn.Get(dbkey)
@@ -44,6 +56,9 @@ func GetDirectory(txn *badger.Txn, group uint64, key []byte) (*pb.Directory, err
 }
 
 func SetDirectory(txn *badger.Txn, group uint64, directory *pb.Directory) error {
+	if err := freezeDir(txn, group, directory.Key); err != nil {
+		return err
+	}
 	dbKey := DBKey(group, DIRECTORY, directory.Key)
 	data, err := proto.Marshal(directory)
 	if err != nil {
@@ -56,12 +71,12 @@ func SetDirectory(txn *badger.Txn, group uint64, directory *pb.Directory) error
 func GetWriteLock(txn *badger.Txn, group uint64, key []byte) (*pb.FileWriteLock, error) {
 	dbkey := DBKey(group, FILE_LOCK, key)
 	lockItem, err := txn.Get(dbkey)
-	if err == nil {
+	if err != nil {
 		log.Println("cannot get file lock item:", err)
 		return nil, err
 	}
 	lockValue, err := lockItem.Value()
-	if err == nil {
+	if err != nil {
 		log.Println("cannot get file lock value:", err)
 		return nil, err
 	}
@@ -73,6 +88,91 @@ func GetWriteLock(txn *badger.Txn, group uint64, key []byte) (*pb.FileWriteLock,
 	return lock, nil
 }
 
+func HoldsWriteLock(txn *badger.Txn, group uint64, key []byte, clientId uint64) error {
+	lock, err := GetLiveWriteLock(txn, group, key)
+	if err != nil {
+		return err
+	}
+	if lock.Owner != clientId {
+		return errors.New("client does not hold the write lease")
+	}
+	return nil
+}
+
+// expired locks are left in place until taken over or released, they just don't count
+func GetLiveWriteLock(txn *badger.Txn, group uint64, key []byte) (*pb.FileWriteLock, error) {
+	lock, err := GetWriteLock(txn, group, key)
+	if err != nil {
+		return nil, errors.New("file is not locked for writing")
+	}
+	now, err := LogClock(txn, group)
+	if err != nil {
+		return nil, err
+	}
+	if lock.Expires <= now {
+		return nil, errors.New("write lease expired")
+	}
+	return lock, nil
+}
+
+// Log clock is the largest client time carried by contracts, it's the same on every node at the same log entry
+// A contract moves it at most MAX_CLOCK_SKEW ahead, so a client with it's clock set ahead cannot expire every lease at once
+// Setback: such client still moves it forward a bit with every contract it sends
+// Only a heartbeat from a stash owner starts the clock, contracts sent before it are rejected,
+// so a client cannot set the clock anywhere it likes on a new cluster
+func LogClock(txn *badger.Txn, group uint64) (uint64, error) {
+	item, err := txn.Get(DBKey(group, LOG_CLOCK, []byte{}))
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return 0, err
+	}
+	return utils.BytesU64(value, 0), nil
+}
+
+var ErrClockNotStarted = errors.New("log clock not started by stash heartbeats")
+
+func AdvanceLogClock(txn *badger.Txn, group uint64, clientTime uint64) (uint64, error) {
+	now, err := LogClock(txn, group)
+	if err != nil {
+		return 0, err
+	}
+	if now == 0 {
+		return 0, ErrClockNotStarted
+	}
+	if clientTime <= now {
+		return now, nil
+	}
+	if clientTime-now > MAX_CLOCK_SKEW {
+		clientTime = now + MAX_CLOCK_SKEW
+	}
+	return clientTime, txn.Set(DBKey(group, LOG_CLOCK, []byte{}), utils.U64Bytes(clientTime), 0x00)
+}
+
+// the caller must have checked the contract is sent by a stash owner
+func startLogClock(txn *badger.Txn, group uint64, clientTime uint64) (uint64, error) {
+	now, err := LogClock(txn, group)
+	if err != nil {
+		return 0, err
+	}
+	if now > 0 {
+		return AdvanceLogClock(txn, group, clientTime)
+	}
+	return clientTime, txn.Set(DBKey(group, LOG_CLOCK, []byte{}), utils.U64Bytes(clientTime), 0x00)
+}
+
+// expire times saturate instead of wrapping around
+func addSaturating(a uint64, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
 func SetWriteLock(txn *badger.Txn, group uint64, lock *pb.FileWriteLock) error {
 	dbKey := DBKey(group, FILE_LOCK, lock.Key)
 	data, err := proto.Marshal(lock)
@@ -109,6 +209,9 @@ func GetFile(txn *badger.Txn, group uint64, key []byte) (*pb.FileMeta, error) {
 }
 
 func SetFile(txn *badger.Txn, group uint64, file *pb.FileMeta) error {
+	if err := freezeFile(txn, group, file.Key); err != nil {
+		return err
+	}
 	dbKey := DBKey(group, FILE_META, file.Key)
 	data, err := proto.Marshal(file)
 	if err != nil {
@@ -118,6 +221,28 @@ func SetFile(txn *badger.Txn, group uint64, file *pb.FileMeta) error {
 	return txn.Set(dbKey, data, 0x00)
 }
 
+func ForEachFile(txn *badger.Txn, group uint64, f func(file *pb.FileMeta) error) error {
+	keyPrefix := bft.ComposeKeyPrefix(group, FILE_META)
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+		fileData, err := iter.Item().Value()
+		if err != nil {
+			log.Println("cannot get file value:", err)
+			return err
+		}
+		file := &pb.FileMeta{}
+		if err := proto.Unmarshal(fileData, file); err != nil {
+			log.Println("cannot decode file:", err)
+			return err
+		}
+		if err := f(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GetVolume(txn *badger.Txn, group uint64, key []byte) (*pb.Volume, error) {
 	dbkey := DBKey(group, VOLUMES, key)
 	volItem, err := txn.Get(dbkey)
@@ -138,6 +263,16 @@ func GetVolume(txn *badger.Txn, group uint64, key []byte) (*pb.Volume, error) {
 	return vol, nil
 }
 
+func SetVolume(txn *badger.Txn, group uint64, volume *pb.Volume) error {
+	dbKey := DBKey(group, VOLUMES, volume.Key)
+	data, err := proto.Marshal(volume)
+	if err != nil {
+		log.Println("cannot encode volume")
+		return err
+	}
+	return txn.Set(dbKey, data, 0x00)
+}
+
 func GetBlockData(txn *badger.Txn, group uint64, file []byte, index uint64) (*pb.BlockData, error) {
 	dbkey := BlockDBKey(group, file, index)
 	bdItem, err := txn.Get(dbkey)
@@ -168,6 +303,18 @@ func SetBlock(txn *badger.Txn, block *pb.BlockData) error {
 	return txn.Set(dbKey, data, 0x00)
 }
 
+func QuarantineBlock(txn *badger.Txn, block *pb.BlockData) error {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		log.Println("cannot encode block")
+		return err
+	}
+	if err := txn.Set(QuarantineDBKey(block.Group, block.File, block.Index), data, 0x00); err != nil {
+		return err
+	}
+	return txn.Delete(BlockDBKey(block.Group, block.File, block.Index))
+}
+
 func GetHostStash(txn *badger.Txn, group uint64, nodeId uint64) (*pb.HostStash, error) {
 	dbkey := DBKey(group, STASH, utils.U64Bytes(nodeId))
 	volItem, err := txn.Get(dbkey)
@@ -197,3 +344,61 @@ func SetHostStash(txn *badger.Txn, group uint64, host *pb.HostStash) error {
 	}
 	return txn.Set(dbKey, data, 0x00)
 }
+
+func ListHostStashes(txn *badger.Txn, group uint64) ([]*pb.HostStash, error) {
+	hosts := []*pb.HostStash{}
+	keyPrefix := bft.ComposeKeyPrefix(group, STASH)
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+		hostData, err := iter.Item().Value()
+		if err != nil {
+			log.Println("error on get stash value:", err)
+			return nil, err
+		}
+		host := &pb.HostStash{}
+		if err := proto.Unmarshal(hostData, host); err != nil {
+			log.Println("error on decoding stash value:", err)
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// advisory locks share the FILE_LOCK key space with write leases, keys are prefixed to not collide
+func AdvisoryLockDBKey(group uint64, key []byte) []byte {
+	return DBKey(group, FILE_LOCK, append([]byte("advisory-"), key...))
+}
+
+func GetAdvisoryLock(txn *badger.Txn, group uint64, key []byte) (*pb.AdvisoryLock, error) {
+	lockItem, err := txn.Get(AdvisoryLockDBKey(group, key))
+	if err != nil {
+		return nil, err
+	}
+	lockValue, err := lockItem.Value()
+	if err != nil {
+		log.Println("cannot get advisory lock value:", err)
+		return nil, err
+	}
+	lock := &pb.AdvisoryLock{}
+	if err := proto.Unmarshal(lockValue, lock); err != nil {
+		log.Println("cannot decode advisory lock:", err)
+		return nil, err
+	}
+	return lock, nil
+}
+
+// locks without holders and waiters are removed
+func SetAdvisoryLock(txn *badger.Txn, group uint64, lock *pb.AdvisoryLock) error {
+	dbKey := AdvisoryLockDBKey(group, lock.Key)
+	if len(lock.Holders) == 0 && len(lock.Waiters) == 0 {
+		return txn.Delete(dbKey)
+	}
+	data, err := proto.Marshal(lock)
+	if err != nil {
+		log.Println("cannot encode advisory lock")
+		return err
+	}
+	return txn.Set(dbKey, data, 0x00)
+}
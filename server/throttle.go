@@ -0,0 +1,34 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle limits bytes per second moved between stash nodes by repair and balancer
+type Throttle struct {
+	rate uint64
+	lock sync.Mutex
+	next time.Time
+}
+
+// zero rate means unlimited
+func NewThrottle(rate uint64) *Throttle {
+	return &Throttle{rate: rate}
+}
+
+// Wait blocks until n more bytes can be sent without exceeding the rate
+func (t *Throttle) Wait(n uint64) {
+	if t.rate == 0 {
+		return
+	}
+	t.lock.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	wait := t.next.Sub(now)
+	t.next = t.next.Add(time.Duration(n * uint64(time.Second) / t.rate))
+	t.lock.Unlock()
+	time.Sleep(wait)
+}
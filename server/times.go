@@ -0,0 +1,128 @@
+package server
+
+import (
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"time"
+)
+
+// Timestamps are set by contracts from the log clock of the group, client times only move the clock forward
+// and at most MAX_CLOCK_SKEW per contract, so every node sets the same time and times never go backwards
+// The clock is started by the first stash heartbeat, contracts with times are rejected before it
+// Snapshots, versions and trash entries are stamped and expired by the same clock
+// mtime changes with the content, ctime with the content and the meta data, birth time is set once
+// mtime and ctime of a directory change with the items in it
+// atime is optional, clients that record it send a contract when a read file's atime is stale like relatime:
+// older than it's mtime or ctime, or older than a day
+// Chtimes sets atime and mtime for tools that keep timestamps of their source, ctime is still the log clock
+
+const ATIME_INTERVAL = uint64(24 * time.Hour)
+
+// contracts without a client time take the current log clock
+func contractTime(txn *badger.Txn, group uint64, clientTime uint64) (uint64, error) {
+	if clientTime != 0 {
+		return AdvanceLogClock(txn, group, clientTime)
+	}
+	now, err := LogClock(txn, group)
+	if err == nil && now == 0 {
+		return 0, ErrClockNotStarted
+	}
+	return now, err
+}
+
+func AccessTimeStale(file *pb.FileMeta, now uint64) bool {
+	return file.AccessedAt <= file.LastModified || file.AccessedAt <= file.ChangedAt || file.AccessedAt+ATIME_INTERVAL <= now
+}
+
+// items in the dir changed, the caller saves dir
+func touchDir(dir *pb.Directory, now uint64) {
+	dir.LastModified = now
+	dir.ChangedAt = now
+}
+
+func (s *PCFSServer) smAccessFile(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.AccessFileContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode access file contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		file, err := GetFile(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		if err := CheckFileAccess(txn, group, file, entry.Command.ClientId, PERM_READ); err != nil {
+			return err
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		if !AccessTimeStale(file, now) {
+			return nil
+		}
+		file.AccessedAt = now
+		return SetFile(txn, group, file)
+	}); err == nil {
+		return []byte{1}
+	} else {
+		log.Println("cannot record access time:", err)
+		return []byte{0}
+	}
+}
+
+// only the owner can set times, like chmod
+func (s *PCFSServer) smChtimes(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.ChtimesContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode chtimes contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		if contract.Dir {
+			dir, err := GetDirectory(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if dir.Owner != entry.Command.ClientId {
+				return errors.New("only owner can change times")
+			}
+			if contract.LastModified != 0 {
+				dir.LastModified = contract.LastModified
+			}
+			dir.ChangedAt = now
+			return SetDirectory(txn, group, dir)
+		}
+		file, err := GetFile(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if file.Owner != entry.Command.ClientId {
+			return errors.New("only owner can change times")
+		}
+		if contract.LastModified != 0 {
+			file.LastModified = contract.LastModified
+		}
+		if contract.AccessedAt != 0 {
+			file.AccessedAt = contract.AccessedAt
+		}
+		file.ChangedAt = now
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("times changed")
+		return []byte{1}
+	} else {
+		log.Println("cannot change times:", err)
+		return []byte{0}
+	}
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// With TLS configured, PCFS services are served on their own address instead of the BFTRaft server
+// The address is put into the host stash so peers know where to connect
+// Certificates carry the node id as common name, it's the peer identity for authorization checks
+// Certificate files are checked for rotation periodically, new handshakes use the new ones
+
+type certStore struct {
+	config  TLSConfig
+	lock    sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+func newCertStore(config TLSConfig) (*certStore, error) {
+	c := &certStore{config: config}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *certStore) lastModified() time.Time {
+	latest := time.Time{}
+	for _, path := range []string{c.config.Cert, c.config.Key, c.config.CA} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+func (c *certStore) reload() error {
+	modTime := c.lastModified()
+	c.lock.RLock()
+	unchanged := c.cert != nil && !modTime.After(c.modTime)
+	c.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+	// node certificates are not issued for host names, system roots cannot verify them
+	if c.config.CA == "" {
+		return errors.New("CA is required for TLS")
+	}
+	cert, err := tls.LoadX509KeyPair(c.config.Cert, c.config.Key)
+	if err != nil {
+		return err
+	}
+	caData, err := ioutil.ReadFile(c.config.CA)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return errors.New("no certificate found in CA file")
+	}
+	c.lock.Lock()
+	c.cert = &cert
+	c.pool = pool
+	c.modTime = modTime
+	c.lock.Unlock()
+	return nil
+}
+
+func (c *certStore) watch(interval time.Duration) {
+	for true {
+		time.Sleep(interval)
+		if err := c.reload(); err != nil {
+			log.Println("cannot reload certificates, keep using the old ones:", err)
+		}
+	}
+}
+
+func (c *certStore) current() (*tls.Certificate, *x509.CertPool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.cert, c.pool
+}
+
+// client certificates are required and verified against current CA
+func (c *certStore) serverConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := c.current()
+			return &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}, nil
+		},
+	}
+}
+
+// server certificate is verified against current CA and must belong to the node dialed
+// host names are not checked, nodes are known by id
+func (c *certStore) clientConfig(hostId uint64) *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := c.current()
+			return cert, nil
+		},
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, pool := c.current()
+			if len(rawCerts) == 0 {
+				return errors.New("no server certificate")
+			}
+			certs := []*x509.Certificate{}
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs = append(certs, cert)
+			}
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				intermediates.AddCert(cert)
+			}
+			if _, err := certs[0].Verify(x509.VerifyOptions{Roots: pool, Intermediates: intermediates}); err != nil {
+				return err
+			}
+			if certs[0].Subject.CommonName != strconv.FormatUint(hostId, 10) {
+				return errors.New(fmt.Sprint("server certificate not issued for node ", hostId))
+			}
+			return nil
+		},
+	}
+}
+
+func (s *PCFSServer) ServeTLS(config TLSConfig) {
+	store, err := newCertStore(config)
+	if err != nil {
+		panic(err)
+	}
+	listener, err := net.Listen("tcp", config.Address)
+	if err != nil {
+		panic(err)
+	}
+	s.tls = store
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(store.serverConfig())))
+	pb.RegisterPCFSServer(server, s)
+	go store.watch(ParseDuration(config.ReloadInterval, time.Minute))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Println("TLS server stopped:", err)
+		}
+	}()
+	log.Println("serving storage services with TLS on", config.Address)
+}
+
+// connects to the PCFS services of the host, over TLS when it's configured on this node
+func (s *PCFSServer) GetPeerRPC(host *rpb.Host) pb.PCFSClient {
+	if host == nil {
+		log.Println("cannot get peer rpc for unknown host")
+		return nil
+	}
+	if s.tls == nil {
+		conn, err := utils.GetClientConn(host.ServerAddr)
+		if err != nil {
+			log.Println("cannot get peer rpc:", err)
+			return nil
+		}
+		return pb.NewPCFSClient(conn)
+	}
+	var stash *pb.HostStash
+	s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		stash, _ = GetHostStash(txn, STASH_GROUP, host.Id)
+		return nil
+	})
+	if stash == nil || stash.Address == "" {
+		log.Println("cannot find TLS address of host:", host.Id)
+		return nil
+	}
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+	if conn, found := s.conns[stash.Address]; found {
+		return pb.NewPCFSClient(conn)
+	}
+	conn, err := grpc.Dial(stash.Address, grpc.WithTransportCredentials(credentials.NewTLS(s.tls.clientConfig(host.Id))))
+	if err != nil {
+		log.Println("cannot get peer rpc:", err)
+		return nil
+	}
+	s.conns[stash.Address] = conn
+	return pb.NewPCFSClient(conn)
+}
+
+// node id from the verified client certificate, only available with mutual TLS
+func PeerIdentity(ctx context.Context) (uint64, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(info.State.VerifiedChains[0][0].Subject.CommonName, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// requests claiming to be from a client must come from the same node over mutual TLS
+func (s *PCFSServer) CheckPeer(ctx context.Context, clientId uint64) error {
+	if s.tls == nil || s.tls.config.CA == "" {
+		return nil
+	}
+	id, ok := PeerIdentity(ctx)
+	if !ok {
+		return errors.New("no verified client certificate")
+	}
+	if id != clientId {
+		return errors.New(fmt.Sprint("client certificate belongs to node ", id, " not ", clientId))
+	}
+	return nil
+}
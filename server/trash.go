@@ -0,0 +1,407 @@
+package server
+
+import (
+	"context"
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Volumes with trash enabled move deleted items into the trash instead of deleting them
+// A trashed item is detached from it's dir, it's directory and file records and block data stay as they are
+// The entry keeps the original path and deletion time, restoring attaches the item back to the dir it was deleted from
+// The stash group leader purges entries older than the retention of the volume and deletes their blocks
+// Trashed items leave the usage of their dirs but stay charged to the volume root until purged,
+// their blocks still take stash space
+// Deletion times and expiry are judged by the log clock, so client times cannot expire entries early
+// Setback: an item can only be restored after the dir it was deleted from, trashed parents go first
+
+func trashDBKey(group uint64, volume []byte, key []byte) []byte {
+	return DBKey(group, TRASH, append(append([]byte{}, volume...), key...))
+}
+
+func dirVolume(dir *pb.Directory) []byte {
+	if len(dir.Volume) == 0 {
+		return dir.Key
+	}
+	return dir.Volume
+}
+
+func GetTrashEntry(txn *badger.Txn, group uint64, volume []byte, key []byte) (*pb.TrashEntry, error) {
+	entry := &pb.TrashEntry{}
+	if err := getProto(txn, trashDBKey(group, volume, key), entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// volume can be nil for entries of all volumes
+func ListTrashEntries(txn *badger.Txn, group uint64, volume []byte) ([]*pb.TrashEntry, error) {
+	entries := []*pb.TrashEntry{}
+	for _, key := range prefixKeys(txn, trashDBKey(group, volume, nil)) {
+		entry := &pb.TrashEntry{}
+		if err := getProto(txn, key, entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// path of an item named name in dir, from the volume root
+func itemPath(txn *badger.Txn, group uint64, dir *pb.Directory, name string) (string, error) {
+	path := "/" + name
+	for d := dir; len(d.Parent) > 0; {
+		path = "/" + d.Name + path
+		parent, err := GetDirectory(txn, group, d.Parent)
+		if err != nil {
+			return "", err
+		}
+		d = parent
+	}
+	return path, nil
+}
+
+// the same checks deleting the item permanently would do, nothing is changed
+func checkTrashable(txn *badger.Txn, group uint64, token []byte, clientId uint64, recursive bool) error {
+	if token[0] == byte(pb.DirectoryItem_FILE) {
+		if lock, err := GetLiveWriteLock(txn, group, token[1:]); err == nil && lock.Owner != clientId {
+			return errors.New("file is being written")
+		}
+		return nil
+	}
+	dir, err := GetDirectory(txn, group, token[1:])
+	if err != nil {
+		return err
+	}
+	if len(dir.Files) == 0 {
+		return nil
+	}
+	if !recursive {
+		return errors.New("dir " + dir.Name + " is not empty")
+	}
+	if err := CheckDirAccess(txn, group, dir, clientId, PERM_WRITE|PERM_EXEC); err != nil {
+		return err
+	}
+	for _, t := range dir.Files {
+		if err := checkTrashable(txn, group, t, clientId, recursive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detaches the item from dir into the trash, the caller saves dir
+func trashItem(txn *badger.Txn, group uint64, dir *pb.Directory, token []byte, clientId uint64, recursive bool, now uint64) error {
+	if err := checkTrashable(txn, group, token, clientId, recursive); err != nil {
+		return err
+	}
+	name, err := tokenName(txn, group, token)
+	if err != nil {
+		return err
+	}
+	path, err := itemPath(txn, group, dir, name)
+	if err != nil {
+		return err
+	}
+	entry := &pb.TrashEntry{
+		Key:       token[1:],
+		Volume:    dirVolume(dir),
+		Dir:       dir.Key,
+		Path:      path,
+		IsDir:     token[0] == byte(pb.DirectoryItem_DIR),
+		DeletedAt: now,
+		DeletedBy: clientId,
+		Usage:     &pb.DirUsage{},
+	}
+	if entry.IsDir {
+		sub, err := GetDirectory(txn, group, entry.Key)
+		if err != nil {
+			return err
+		}
+		if sub.Usage == nil {
+			if sub.Usage, err = SubtreeUsage(txn, group, sub); err != nil {
+				return err
+			}
+			if err := SetDirectory(txn, group, sub); err != nil {
+				return err
+			}
+		}
+		entry.Usage.Dirs = 1 + sub.Usage.Dirs
+		entry.Usage.Bytes = sub.Usage.Bytes
+		entry.Usage.Files = sub.Usage.Files
+	} else {
+		file, err := GetFile(txn, group, entry.Key)
+		if err != nil {
+			return err
+		}
+		entry.Usage.Bytes = file.Size
+		entry.Usage.Files = 1
+	}
+	removeToken(dir, entry.Key)
+	if err := chargeBelowRoot(txn, group, dir, -int64(entry.Usage.Bytes), -int64(entry.Usage.Files), -int64(entry.Usage.Dirs)); err != nil {
+		return err
+	}
+	log.Println("moved", path, "to trash")
+	return setProto(txn, trashDBKey(group, entry.Volume, entry.Key), entry)
+}
+
+// the trash of the volume is used when enabled and the delete is not permanent
+func useTrash(txn *badger.Txn, group uint64, dir *pb.Directory, contract *pb.DeleteItemContract) (bool, error) {
+	if contract.Permanent {
+		return false, nil
+	}
+	volume, err := GetVolume(txn, group, dirVolume(dir))
+	if err != nil {
+		return false, err
+	}
+	return volume.Trash != nil && volume.Trash.Enabled, nil
+}
+
+func (s *PCFSServer) smRestoreTrash(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.RestoreTrashContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode restore trash contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		trashed, err := GetTrashEntry(txn, group, contract.Volume, contract.Key)
+		if err != nil {
+			return err
+		}
+		dir, err := GetDirectory(txn, group, trashed.Dir)
+		if err != nil {
+			return errors.New("dir of " + trashed.Path + " no longer exists")
+		}
+		for key := dir.Key; len(key) > 0; {
+			if _, err := GetTrashEntry(txn, group, trashed.Volume, key); err == nil {
+				return errors.New("dir of " + trashed.Path + " is in trash")
+			}
+			ancestor, err := GetDirectory(txn, group, key)
+			if err != nil {
+				return err
+			}
+			key = ancestor.Parent
+		}
+		if err := CheckDirAccess(txn, group, dir, clientId, PERM_WRITE|PERM_EXEC); err != nil {
+			return err
+		}
+		token := append([]byte{byte(pb.DirectoryItem_FILE)}, trashed.Key...)
+		if trashed.IsDir {
+			token[0] = byte(pb.DirectoryItem_DIR)
+		}
+		name, err := tokenName(txn, group, token)
+		if err != nil {
+			return err
+		}
+		for _, t := range dir.Files {
+			if n, err := tokenName(txn, group, t); err == nil && n == name {
+				return errors.New("name " + name + " already exists")
+			}
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		touchDir(dir, now)
+		usage := trashed.Usage
+		if err := chargeBelowRoot(txn, group, dir, int64(usage.Bytes), int64(usage.Files), int64(usage.Dirs)); err != nil {
+			return err
+		}
+		dir.Files = append(dir.Files, token)
+		if err := SetDirectory(txn, group, dir); err != nil {
+			return err
+		}
+		log.Println("restored", trashed.Path, "from trash")
+		return txn.Delete(trashDBKey(group, trashed.Volume, trashed.Key))
+	}); err == nil {
+		return []byte{1}
+	} else {
+		log.Println("cannot restore from trash:", err)
+		return quotaResult(err)
+	}
+}
+
+func trashExpired(volume *pb.Volume, entry *pb.TrashEntry, now uint64) bool {
+	return volume.Trash != nil && volume.Trash.Retention != 0 && entry.DeletedAt+volume.Trash.Retention < now
+}
+
+// expired entries can be purged by anyone, others by who deleted them or admins of the volume
+// deleted files are returned to the caller to delete their blocks
+func (s *PCFSServer) smPurgeTrash(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.PurgeTrashContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode purge trash contract:", err)
+		return []byte{0}
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		volume, err := GetVolume(txn, group, contract.Volume)
+		if err != nil {
+			return err
+		}
+		trashed, err := GetTrashEntry(txn, group, volume.Key, contract.Key)
+		if err != nil {
+			return err
+		}
+		now, err := LogClock(txn, group)
+		if err != nil {
+			return err
+		}
+		if !trashExpired(volume, trashed, now) &&
+			trashed.DeletedBy != clientId && !canAdminVolume(txn, group, volume, clientId) {
+			return errors.New("only who deleted it or admin can purge trash before it expires")
+		}
+		// permissions were checked when it was deleted
+		if trashed.IsDir {
+			dir, err := GetDirectory(txn, group, trashed.Key)
+			if err != nil {
+				return err
+			}
+			if err := deleteTree(txn, group, dir, trashed.DeletedBy, true, &pb.DirUsage{}, deleted); err != nil {
+				return err
+			}
+		} else {
+			file, err := GetFile(txn, group, trashed.Key)
+			if err != nil {
+				return err
+			}
+			if err := deleteFile(txn, group, file, trashed.DeletedBy, deleted); err != nil {
+				return err
+			}
+		}
+		root, err := GetDirectory(txn, group, volume.RootDir)
+		if err != nil {
+			return err
+		}
+		usage := trashed.Usage
+		if err := ChargeQuota(txn, group, root, -int64(usage.Bytes), -int64(usage.Files), -int64(usage.Dirs)); err != nil {
+			return err
+		}
+		if err := SetDirectory(txn, group, root); err != nil {
+			return err
+		}
+		log.Println("purged", trashed.Path, "from trash")
+		return txn.Delete(trashDBKey(group, volume.Key, trashed.Key))
+	}); err == nil {
+		resData, _ := proto.Marshal(deleted)
+		return resData
+	} else {
+		log.Println("cannot purge trash:", err)
+		return []byte{0}
+	}
+}
+
+// invoked by the stash group leader
+func (s *PCFSServer) PurgeExpiredTrash() {
+	expired := []*pb.TrashEntry{}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		now, err := LogClock(txn, STASH_GROUP)
+		if err != nil {
+			return err
+		}
+		entries, err := ListTrashEntries(txn, STASH_GROUP, nil)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if volume, err := GetVolume(txn, STASH_GROUP, entry.Volume); err == nil && trashExpired(volume, entry, now) {
+				expired = append(expired, entry)
+			}
+		}
+		return nil
+	}); err != nil {
+		log.Println("cannot list trash:", err)
+		return
+	}
+	for _, entry := range expired {
+		contractData, err := proto.Marshal(&pb.PurgeTrashContract{
+			Volume: entry.Volume,
+			Key:    entry.Key,
+		})
+		if err != nil {
+			continue
+		}
+		res, err := s.BFTRaft.Client.ExecCommand(STASH_GROUP, PURGE_TRASH, contractData)
+		if err != nil || len(*res) == 1 {
+			log.Println("cannot purge", entry.Path, "from trash:", err)
+			continue
+		}
+		deleted := &pb.DeletedFiles{}
+		if err := proto.Unmarshal(*res, deleted); err != nil {
+			continue
+		}
+		s.deleteFileBlocks(deleted.Files, false)
+		s.deleteFileBlocks(deleted.Released, true)
+	}
+}
+
+// best effort, blocks left behind are not referenced by any file
+// with kept, copies kept for snapshots and versions are deleted instead of live blocks
+func (s *PCFSServer) deleteFileBlocks(files []*pb.FileMeta, kept bool) {
+	for _, file := range files {
+		for _, block := range file.Blocks {
+			for _, hostId := range block.Hosts {
+				host := s.BFTRaft.GetHostNTXN(hostId)
+				if host == nil {
+					continue
+				}
+				client := s.GetPeerRPC(host)
+				if client == nil {
+					continue
+				}
+				req := &pb.DeleteBlockRequest{
+					Group:    STASH_GROUP,
+					Index:    block.Index,
+					File:     file.Key,
+					ClientId: s.BFTRaft.Id,
+				}
+				if kept {
+					req.Hash = block.Hash
+				}
+				if err := s.SignRequest(req, &req.Signature); err != nil {
+					log.Println("cannot sign delete block request:", err)
+					continue
+				}
+				if _, err := client.DeleteBlock(context.Background(), req); err != nil {
+					log.Println("cannot delete block", block.Index, "of", file.Name, "from", hostId, ":", err)
+				}
+			}
+		}
+	}
+}
+
+func (s *PCFSServer) ListTrash(ctx context.Context, req *pb.ListTrashRequest) (*pb.ListTrashResponse, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected trash list:", err)
+		return nil, err
+	}
+	res := &pb.ListTrashResponse{}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		volume, err := GetVolume(txn, req.Group, req.Volume)
+		if err != nil {
+			return err
+		}
+		root, err := GetDirectory(txn, req.Group, volume.RootDir)
+		if err != nil {
+			return err
+		}
+		if err := CheckDirAccess(txn, req.Group, root, clientId, PERM_READ); err != nil {
+			return err
+		}
+		res.Entries, err = ListTrashEntries(txn, req.Group, volume.Key)
+		return err
+	}); err != nil {
+		log.Println("cannot list trash:", err)
+		return nil, errors.New("cannot list trash")
+	}
+	return res, nil
+}
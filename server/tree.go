@@ -0,0 +1,295 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"strings"
+)
+
+// Delete and move change the directory tree, usage of all ancestors is updated in the same contract
+// Deletes go to the trash of the volume when it's enabled, unless they are permanent
+// Deleted file meta data is returned to the client, block data on stashes is deleted by the client afterwards.
+// Blocks it fails to delete are left on the stashes without references
+// Moves only happen inside a volume, keys of moved items don't change
+
+func findToken(dir *pb.Directory, key []byte) ([]byte, bool) {
+	for _, token := range dir.Files {
+		if bytes.Equal(token[1:], key) {
+			return token, true
+		}
+	}
+	return nil, false
+}
+
+func removeToken(dir *pb.Directory, key []byte) {
+	for i, token := range dir.Files {
+		if bytes.Equal(token[1:], key) {
+			dir.Files = append(dir.Files[:i], dir.Files[i+1:]...)
+			return
+		}
+	}
+}
+
+func tokenName(txn *badger.Txn, group uint64, token []byte) (string, error) {
+	if token[0] == byte(pb.DirectoryItem_DIR) {
+		dir, err := GetDirectory(txn, group, token[1:])
+		if err != nil {
+			return "", err
+		}
+		return dir.Name, nil
+	}
+	file, err := GetFile(txn, group, token[1:])
+	if err != nil {
+		return "", err
+	}
+	return file.Name, nil
+}
+
+// files being written by others cannot be deleted
+func deleteFile(txn *badger.Txn, group uint64, file *pb.FileMeta, clientId uint64, deleted *pb.DeletedFiles) error {
+	if lock, err := GetLiveWriteLock(txn, group, file.Key); err == nil && lock.Owner != clientId {
+		return errors.New("file " + file.Name + " is being written")
+	}
+	if err := dropVersions(txn, group, file, deleted); err != nil {
+		return err
+	}
+	if err := txn.Delete(DBKey(group, FILE_LOCK, file.Key)); err != nil {
+		return err
+	}
+	if err := freezeFile(txn, group, file.Key); err != nil {
+		return err
+	}
+	deleted.Files = append(deleted.Files, file)
+	return txn.Delete(DBKey(group, FILE_META, file.Key))
+}
+
+// counts everything deleted into usage, the dir itself included
+func deleteTree(txn *badger.Txn, group uint64, dir *pb.Directory, clientId uint64, recursive bool,
+	usage *pb.DirUsage, deleted *pb.DeletedFiles) error {
+	if len(dir.Files) > 0 {
+		if !recursive {
+			return errors.New("dir " + dir.Name + " is not empty")
+		}
+		if err := CheckDirAccess(txn, group, dir, clientId, PERM_WRITE|PERM_EXEC); err != nil {
+			return err
+		}
+	}
+	for _, token := range dir.Files {
+		if token[0] == byte(pb.DirectoryItem_DIR) {
+			sub, err := GetDirectory(txn, group, token[1:])
+			if err != nil {
+				return err
+			}
+			if err := deleteTree(txn, group, sub, clientId, recursive, usage, deleted); err != nil {
+				return err
+			}
+			continue
+		}
+		file, err := GetFile(txn, group, token[1:])
+		if err != nil {
+			return err
+		}
+		if err := deleteFile(txn, group, file, clientId, deleted); err != nil {
+			return err
+		}
+		usage.Bytes += file.Size
+		usage.Files++
+	}
+	usage.Dirs++
+	if err := freezeDir(txn, group, dir.Key); err != nil {
+		return err
+	}
+	return txn.Delete(DBKey(group, DIRECTORY, dir.Key))
+}
+
+func (s *PCFSServer) smDeleteItem(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.DeleteItemContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode delete item contract:", err)
+		return []byte{0}
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		dir, err := GetDirectory(txn, group, contract.Dir)
+		if err != nil {
+			return err
+		}
+		if err := CheckDirAccess(txn, group, dir, clientId, PERM_WRITE|PERM_EXEC); err != nil {
+			return err
+		}
+		token, found := findToken(dir, contract.Key)
+		if !found {
+			return errors.New("cannot find item in dir")
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		touchDir(dir, now)
+		if trash, err := useTrash(txn, group, dir, contract); err != nil {
+			return err
+		} else if trash {
+			if err := trashItem(txn, group, dir, token, clientId, contract.Recursive, now); err != nil {
+				return err
+			}
+			return SetDirectory(txn, group, dir)
+		}
+		usage := &pb.DirUsage{}
+		if token[0] == byte(pb.DirectoryItem_DIR) {
+			sub, err := GetDirectory(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if err := deleteTree(txn, group, sub, clientId, contract.Recursive, usage, deleted); err != nil {
+				return err
+			}
+		} else {
+			file, err := GetFile(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if err := deleteFile(txn, group, file, clientId, deleted); err != nil {
+				return err
+			}
+			usage.Bytes = file.Size
+			usage.Files = 1
+		}
+		removeToken(dir, contract.Key)
+		if err := ChargeQuota(txn, group, dir, -int64(usage.Bytes), -int64(usage.Files), -int64(usage.Dirs)); err != nil {
+			return err
+		}
+		return SetDirectory(txn, group, dir)
+	}); err == nil {
+		log.Println("deleted", len(deleted.Files), "files")
+		resData, _ := proto.Marshal(deleted)
+		return resData
+	} else {
+		log.Println("cannot delete item:", err)
+		return []byte{0}
+	}
+}
+
+// the item is renamed to name in the destination dir, dst dir can be the source dir for a rename
+func (s *PCFSServer) smMoveItem(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.MoveItemContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode move item contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if contract.Name == "" || strings.Contains(contract.Name, "/") {
+			return errors.New("invalid name")
+		}
+		srcDir, err := GetDirectory(txn, group, contract.SrcDir)
+		if err != nil {
+			return err
+		}
+		dstDir, err := GetDirectory(txn, group, contract.DstDir)
+		if err != nil {
+			return err
+		}
+		for _, dir := range []*pb.Directory{srcDir, dstDir} {
+			if err := CheckDirAccess(txn, group, dir, clientId, PERM_WRITE|PERM_EXEC); err != nil {
+				return err
+			}
+		}
+		if reservedName(dstDir, contract.Name) {
+			return errors.New("name " + contract.Name + " is reserved")
+		}
+		if !bytes.Equal(srcDir.Volume, dstDir.Volume) {
+			return errors.New("cannot move across volumes")
+		}
+		token, found := findToken(srcDir, contract.Key)
+		if !found {
+			return errors.New("cannot find item in dir")
+		}
+		for _, t := range dstDir.Files {
+			if bytes.Equal(t[1:], contract.Key) {
+				continue
+			}
+			if name, err := tokenName(txn, group, t); err == nil && name == contract.Name {
+				return errors.New("name " + contract.Name + " already exists")
+			}
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		usage := &pb.DirUsage{}
+		if token[0] == byte(pb.DirectoryItem_DIR) {
+			// a dir cannot be moved into it's own subtree
+			for key := dstDir.Key; len(key) > 0; {
+				if bytes.Equal(key, contract.Key) {
+					return errors.New("cannot move dir into itself")
+				}
+				ancestor, err := GetDirectory(txn, group, key)
+				if err != nil {
+					return err
+				}
+				key = ancestor.Parent
+			}
+			dir, err := GetDirectory(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if dir.Usage, err = CountedUsage(txn, group, dir); err != nil {
+				return err
+			}
+			usage.Dirs = 1 + dir.Usage.Dirs
+			usage.Bytes = dir.Usage.Bytes
+			usage.Files = dir.Usage.Files
+			dir.Name = contract.Name
+			dir.Parent = dstDir.Key
+			dir.ChangedAt = now
+			if err := SetDirectory(txn, group, dir); err != nil {
+				return err
+			}
+		} else {
+			file, err := GetFile(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			usage.Bytes = file.Size
+			usage.Files = 1
+			file.Name = contract.Name
+			file.Dir = dstDir.Key
+			file.ChangedAt = now
+			if err := SetFile(txn, group, file); err != nil {
+				return err
+			}
+		}
+		removeToken(srcDir, contract.Key)
+		touchDir(srcDir, now)
+		if err := ChargeQuota(txn, group, srcDir, -int64(usage.Bytes), -int64(usage.Files), -int64(usage.Dirs)); err != nil {
+			return err
+		}
+		if err := SetDirectory(txn, group, srcDir); err != nil {
+			return err
+		}
+		// reload, the source chain may have changed it
+		if dstDir, err = GetDirectory(txn, group, contract.DstDir); err != nil {
+			return err
+		}
+		if err := ChargeQuota(txn, group, dstDir, int64(usage.Bytes), int64(usage.Files), int64(usage.Dirs)); err != nil {
+			return err
+		}
+		dstDir.Files = append(dstDir.Files, token)
+		touchDir(dstDir, now)
+		return SetDirectory(txn, group, dstDir)
+	}); err == nil {
+		log.Println("item moved")
+		return []byte{1}
+	} else {
+		log.Println("cannot move item:", err)
+		return quotaResult(err)
+	}
+}
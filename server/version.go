@@ -0,0 +1,289 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	"github.com/PomeloCloud/BFTRaft4go/utils"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+)
+
+// Volumes with versioning enabled keep a version of the file meta data for every closed write session
+// Versions share block contents with the live file the same way snapshots do, by (file, index, hash) references,
+// so stash nodes keep the old content aside when a versioned block is overwritten
+// Retention of the volume is applied when a new version is committed, released contents are deleted by the client
+// Past versions are read only. Restoring one sets the block list of the file back to the version's, hosts of
+// changed blocks hold the old content kept aside and the writer has them put it back in place, a new version follows
+
+func versionDBKey(group uint64, file []byte, number uint64) []byte {
+	return DBKey(group, VERSIONS, append(append([]byte{}, file...), utils.U64Bytes(number)...))
+}
+
+func GetFileVersion(txn *badger.Txn, group uint64, file []byte, number uint64) (*pb.FileVersion, error) {
+	version := &pb.FileVersion{}
+	if err := getProto(txn, versionDBKey(group, file, number), version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// oldest first
+func ListFileVersions(txn *badger.Txn, group uint64, file []byte) ([]*pb.FileVersion, error) {
+	versions := []*pb.FileVersion{}
+	keyPrefix := DBKey(group, VERSIONS, file)
+	iter := txn.NewIterator(badger.IteratorOptions{})
+	defer iter.Close()
+	for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+		data, err := iter.Item().Value()
+		if err != nil {
+			return nil, err
+		}
+		version := &pb.FileVersion{}
+		if err := proto.Unmarshal(data, version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func sameBlocks(a *pb.FileMeta, b *pb.FileMeta) bool {
+	if len(a.Blocks) != len(b.Blocks) {
+		return false
+	}
+	for i := range a.Blocks {
+		if !bytes.Equal(a.Blocks[i].Hash, b.Blocks[i].Hash) {
+			return false
+		}
+	}
+	return true
+}
+
+// drops the version and returns block contents no longer referenced, live may be nil for deleted files
+func releaseVersion(txn *badger.Txn, group uint64, version *pb.FileVersion, live *pb.FileMeta) (*pb.FileMeta, error) {
+	released := &pb.FileMeta{Key: version.File, Name: version.Meta.Name}
+	for _, block := range version.Meta.Blocks {
+		refs, err := AdjustBlockRefs(txn, group, version.File, block.Index, block.Hash, -1)
+		if err != nil {
+			return nil, err
+		}
+		if refs > 0 {
+			continue
+		}
+		if live != nil && block.Index < uint64(len(live.Blocks)) {
+			for _, hostId := range live.Blocks[block.Index].Hosts {
+				if !containsHost(block.Hosts, hostId) {
+					block.Hosts = append(block.Hosts, hostId)
+				}
+			}
+		}
+		released.Blocks = append(released.Blocks, block)
+	}
+	return released, txn.Delete(versionDBKey(group, version.File, version.Number))
+}
+
+// invoked by the writer before it releases the write lock, it's a no op for volumes without versioning
+func (s *PCFSServer) smCommitVersion(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.CommitVersionContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode commit version contract:", err)
+		return []byte{0}
+	}
+	released := &pb.DeletedFiles{}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if err := HoldsWriteLock(txn, group, contract.File, entry.Command.ClientId); err != nil {
+			return err
+		}
+		file, err := GetFile(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		volume, err := GetVolume(txn, group, file.Volume)
+		if err != nil {
+			return err
+		}
+		retention := volume.Versioning
+		if retention == nil || !retention.Enabled {
+			return nil
+		}
+		// one client with it's clock ahead cannot expire every version
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		versions, err := ListFileVersions(txn, group, file.Key)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 || !sameBlocks(versions[len(versions)-1].Meta, file) {
+			file.Version++
+			version := &pb.FileVersion{
+				File:      file.Key,
+				Number:    file.Version,
+				Meta:      proto.Clone(file).(*pb.FileMeta),
+				CreatedAt: now,
+			}
+			for _, block := range file.Blocks {
+				if _, err := AdjustBlockRefs(txn, group, file.Key, block.Index, block.Hash, 1); err != nil {
+					return err
+				}
+			}
+			if err := setProto(txn, versionDBKey(group, file.Key, version.Number), version); err != nil {
+				return err
+			}
+			if err := SetFile(txn, group, file); err != nil {
+				return err
+			}
+			versions = append(versions, version)
+		}
+		for i, version := range versions[:len(versions)-1] {
+			expired := retention.MaxAge != 0 && addSaturating(version.CreatedAt, retention.MaxAge) < now
+			if !expired && (retention.Keep == 0 || uint32(len(versions)-i) <= retention.Keep) {
+				continue
+			}
+			meta, err := releaseVersion(txn, group, version, file)
+			if err != nil {
+				return err
+			}
+			if len(meta.Blocks) > 0 {
+				released.Released = append(released.Released, meta)
+			}
+		}
+		return nil
+	}); err == nil {
+		resData, _ := proto.Marshal(released)
+		return resData
+	} else {
+		log.Println("cannot commit version:", err)
+		return []byte{0}
+	}
+}
+
+// invoked by the writer, returns replicas of live blocks the restored file does not list anymore
+func (s *PCFSServer) smRestoreVersion(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.RestoreVersionContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode restore version contract:", err)
+		return []byte{0}
+	}
+	deleted := &pb.DeletedFiles{}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if err := HoldsWriteLock(txn, group, contract.File, entry.Command.ClientId); err != nil {
+			return err
+		}
+		file, err := GetFile(txn, group, contract.File)
+		if err != nil {
+			return err
+		}
+		version, err := GetFileVersion(txn, group, file.Key, contract.Number)
+		if err != nil {
+			return err
+		}
+		// unchanged blocks stay where they are live
+		blocks := version.Meta.Blocks
+		dropped := []*pb.Block{}
+		for i, block := range file.Blocks {
+			if i >= len(blocks) {
+				dropped = append(dropped, block)
+				continue
+			}
+			if bytes.Equal(block.Hash, blocks[i].Hash) {
+				blocks[i] = block
+				continue
+			}
+			stale := []uint64{}
+			for _, hostId := range block.Hosts {
+				if !containsHost(blocks[i].Hosts, hostId) {
+					stale = append(stale, hostId)
+				}
+			}
+			if len(stale) > 0 {
+				dropped = append(dropped, &pb.Block{Index: block.Index, Hash: block.Hash, Hosts: stale})
+			}
+		}
+		if dir, err := GetDirectory(txn, group, file.Dir); err == nil {
+			delta := int64(len(blocks)-len(file.Blocks)) * int64(file.BlockSize)
+			if err := ChargeQuota(txn, group, dir, delta, 0, 0); err != nil {
+				return err
+			}
+			if err := SetDirectory(txn, group, dir); err != nil {
+				return err
+			}
+		}
+		now, err := contractTime(txn, group, contract.ClientTime)
+		if err != nil {
+			return err
+		}
+		file.Blocks = blocks
+		file.Size = uint64(len(file.Blocks)) * uint64(file.BlockSize)
+		file.LastModified = now
+		file.ChangedAt = now
+		if len(dropped) > 0 {
+			deleted.Files = append(deleted.Files, &pb.FileMeta{Key: file.Key, Name: file.Name, Blocks: dropped})
+		}
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("version", contract.Number, "restored")
+		resData, _ := proto.Marshal(deleted)
+		return resData
+	} else {
+		log.Println("cannot restore version:", err)
+		return quotaResult(err)
+	}
+}
+
+// releases all versions of a file being deleted
+func dropVersions(txn *badger.Txn, group uint64, file *pb.FileMeta, deleted *pb.DeletedFiles) error {
+	versions, err := ListFileVersions(txn, group, file.Key)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		meta, err := releaseVersion(txn, group, version, nil)
+		if err != nil {
+			return err
+		}
+		// the live content is deleted with the file
+		blocks := []*pb.Block{}
+		for _, block := range meta.Blocks {
+			if block.Index >= uint64(len(file.Blocks)) || !bytes.Equal(file.Blocks[block.Index].Hash, block.Hash) {
+				blocks = append(blocks, block)
+			}
+		}
+		if len(blocks) > 0 {
+			meta.Blocks = blocks
+			deleted.Released = append(deleted.Released, meta)
+		}
+	}
+	return nil
+}
+
+func (s *PCFSServer) ListVersions(ctx context.Context, req *pb.ListVersionsRequest) (*pb.ListVersionsResponse, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected version list:", err)
+		return nil, err
+	}
+	res := &pb.ListVersionsResponse{}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		file, err := GetFile(txn, req.Group, req.File)
+		if err != nil {
+			return err
+		}
+		if err := CheckFileAccess(txn, req.Group, file, clientId, PERM_READ); err != nil {
+			return err
+		}
+		res.Versions, err = ListFileVersions(txn, req.Group, req.File)
+		return err
+	}); err != nil {
+		log.Println("cannot list versions:", err)
+		return nil, errors.New("cannot list versions")
+	}
+	return res, nil
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	bft "github.com/PomeloCloud/BFTRaft4go/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Volumes are owned by the client that created them, every owner has it's own namespace of volume names
+// In paths, a volume is named "<owner>:<name>", a bare name is in the namespace of the caller
+// Volumes created before ownership existed have no owner, they are still found by their bare names
+// Only stash owners and clients on the volume creators list can create volumes
+
+var volumeCreatorsKey = []byte("volume-creators")
+
+const (
+	DEFAULT_REPLICATIONS = uint32(3)
+	DEFAULT_BLOCK_SIZE   = uint32(8 * 1024)
+	MAX_REPLICATIONS     = uint32(32)
+)
+
+func VolumeKey(owner uint64, name string) []byte {
+	return IdFromName(fmt.Sprint(owner, ":", name))
+}
+
+func ParseVolumeName(name string, clientId uint64) (uint64, string) {
+	if i := strings.Index(name, ":"); i > 0 {
+		if owner, err := strconv.ParseUint(name[:i], 10, 64); err == nil {
+			return owner, name[i+1:]
+		}
+	}
+	return clientId, name
+}
+
+func ResolveVolume(txn *badger.Txn, group uint64, name string, clientId uint64) (*pb.Volume, error) {
+	owner, volumeName := ParseVolumeName(name, clientId)
+	volume, err := GetVolume(txn, group, VolumeKey(owner, volumeName))
+	if err != badger.ErrKeyNotFound {
+		return volume, err
+	}
+	if legacy, err := GetVolume(txn, group, IdFromName(name)); err == nil && legacy.Owner == 0 {
+		return legacy, nil
+	}
+	return nil, err
+}
+
+func GetVolumeCreators(txn *badger.Txn, group uint64) (*pb.PrincipalList, error) {
+	creators := &pb.PrincipalList{}
+	item, err := txn.Get(DBKey(group, SETTINGS, volumeCreatorsKey))
+	if err == badger.ErrKeyNotFound {
+		return creators, nil
+	} else if err != nil {
+		return nil, err
+	}
+	value, err := item.Value()
+	if err != nil {
+		return nil, err
+	}
+	return creators, proto.Unmarshal(value, creators)
+}
+
+func isStashOwner(txn *badger.Txn, group uint64, clientId uint64) bool {
+	stashes, err := ListHostStashes(txn, group)
+	if err != nil {
+		return false
+	}
+	for _, stash := range stashes {
+		if stash.Owner == clientId {
+			return true
+		}
+	}
+	return false
+}
+
+func CanCreateVolume(txn *badger.Txn, group uint64, clientId uint64) bool {
+	if isStashOwner(txn, group, clientId) {
+		return true
+	}
+	creators, err := GetVolumeCreators(txn, group)
+	return err == nil && containsHost(creators.Ids, clientId)
+}
+
+// volumes are visible to their owners and to clients that can list their root dir
+func volumeVisible(txn *badger.Txn, group uint64, volume *pb.Volume, clientId uint64) bool {
+	if volume.Owner == clientId {
+		return true
+	}
+	rootDir, err := GetDirectory(txn, group, volume.RootDir)
+	if err != nil {
+		return false
+	}
+	return CheckDirAccess(txn, group, rootDir, clientId, PERM_READ) == nil
+}
+
+func (s *PCFSServer) ListVolumes(ctx context.Context, req *pb.ListVolumesRequest) (*pb.ListVolumesResponse, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected list volumes:", err)
+		return nil, err
+	}
+	res := &pb.ListVolumesResponse{Volumes: []*pb.Volume{}}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		keyPrefix := bft.ComposeKeyPrefix(req.Group, VOLUMES)
+		iter := txn.NewIterator(badger.IteratorOptions{})
+		defer iter.Close()
+		for iter.Seek(keyPrefix); iter.ValidForPrefix(keyPrefix); iter.Next() {
+			data, err := iter.Item().Value()
+			if err != nil {
+				return err
+			}
+			volume := &pb.Volume{}
+			if err := proto.Unmarshal(data, volume); err != nil {
+				return err
+			}
+			if volumeVisible(txn, req.Group, volume, clientId) {
+				res.Volumes = append(res.Volumes, volume)
+			}
+		}
+		return nil
+	}); err == nil {
+		return res, nil
+	} else {
+		log.Println("cannot list volumes:", err)
+		return nil, err
+	}
+}
+
+// only stash owners can change the volume creators list
+func (s *PCFSServer) smSetVolumeCreators(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	creators := &pb.PrincipalList{}
+	if err := proto.Unmarshal(*arg, creators); err != nil {
+		log.Println("cannode decode volume creators:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		if !isStashOwner(txn, group, entry.Command.ClientId) {
+			return errors.New("only stash owners can set volume creators")
+		}
+		data, err := proto.Marshal(creators)
+		if err != nil {
+			return err
+		}
+		return txn.Set(DBKey(group, SETTINGS, volumeCreatorsKey), data, 0x00)
+	}); err == nil {
+		log.Println("volume creators set")
+		return []byte{1}
+	} else {
+		log.Println("cannot set volume creators:", err)
+		return []byte{0}
+	}
+}
+
+// replicas of a block are on different stashes, so there can't be more of them than registered stashes
+func ValidateVolumeOptions(txn *badger.Txn, group uint64, replications uint32, blockSize uint32) error {
+	stashes, err := ListHostStashes(txn, group)
+	if err != nil {
+		return err
+	}
+	if replications < 1 || replications > MAX_REPLICATIONS {
+		return errors.New(fmt.Sprint("replications should be between 1 and ", MAX_REPLICATIONS))
+	}
+	if replications > uint32(len(stashes)) {
+		return errors.New(fmt.Sprint("replications ", replications, " exceeds ", len(stashes), " stash nodes"))
+	}
+	if blockSize < _1KB || blockSize > _10MB {
+		return errors.New(fmt.Sprint("block size should be between ", _1KB, " and ", _10MB))
+	}
+	return nil
+}
+
+func canAdminVolume(txn *badger.Txn, group uint64, volume *pb.Volume, clientId uint64) bool {
+	if volume.Owner == clientId {
+		return true
+	}
+	rootDir, err := GetDirectory(txn, group, volume.RootDir)
+	return err == nil && canAdminDir(txn, group, rootDir, clientId)
+}
+
+// new block size applies to files created afterwards, existing files keep theirs
+// changed replications are brought to existing blocks by a replication job
+func (s *PCFSServer) smUpdateVolume(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.UpdateVolumeContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode update volume contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		volume, err := GetVolume(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if !canAdminVolume(txn, group, volume, entry.Command.ClientId) {
+			return errors.New("only owner or admin can update volume")
+		}
+		if contract.Replications != 0 && contract.Replications != volume.Replications {
+			volume.Replications = contract.Replications
+			if err := StartReplicationJob(txn, group, volume); err != nil {
+				return err
+			}
+		}
+		if contract.BlockSize != 0 {
+			volume.BlockSize = contract.BlockSize
+		}
+		if contract.Versioning != nil {
+			volume.Versioning = contract.Versioning
+		}
+		if contract.Trash != nil {
+			volume.Trash = contract.Trash
+		}
+		if err := ValidateVolumeOptions(txn, group, volume.Replications, volume.BlockSize); err != nil {
+			return err
+		}
+		return SetVolume(txn, group, volume)
+	}); err == nil {
+		log.Println("volume updated")
+		return []byte{1}
+	} else {
+		log.Println("cannot update volume:", err)
+		return []byte{0}
+	}
+}
+
+// only empty volumes can be deleted, by their owners
+func (s *PCFSServer) smDeleteVolume(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	contract := &pb.DeleteVolumeContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode delete volume contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		volume, err := GetVolume(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if volume.Owner != entry.Command.ClientId {
+			return errors.New("only owner can delete volume")
+		}
+		rootDir, err := GetDirectory(txn, group, volume.RootDir)
+		if err != nil {
+			return err
+		}
+		if len(rootDir.Files) > 0 {
+			return errors.New("volume is not empty")
+		}
+		if snapshots, err := ListSnapshots(txn, group, volume.Key); err != nil {
+			return err
+		} else if len(snapshots) > 0 {
+			return errors.New("volume has snapshots")
+		}
+		if entries, err := ListTrashEntries(txn, group, volume.Key); err != nil {
+			return err
+		} else if len(entries) > 0 {
+			return errors.New("volume has items in trash")
+		}
+		if err := txn.Delete(DBKey(group, DIRECTORY, volume.RootDir)); err != nil {
+			return err
+		}
+		if err := txn.Delete(DBKey(group, JOBS, volume.Key)); err != nil {
+			return err
+		}
+		return txn.Delete(DBKey(group, VOLUMES, volume.Key))
+	}); err == nil {
+		log.Println("volume deleted")
+		return []byte{1}
+	} else {
+		log.Println("cannot delete volume:", err)
+		return []byte{0}
+	}
+}
+
+// usage is the logical bytes, files and dirs counted on the root dir, replicas are not included
+func (s *PCFSServer) GetVolumeUsage(ctx context.Context, req *pb.GetVolumeRequest) (*pb.VolumeUsage, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected volume usage:", err)
+		return nil, err
+	}
+	usage := &pb.VolumeUsage{}
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		volume, err := ResolveVolume(txn, req.Group, req.Name, clientId)
+		if err != nil {
+			return err
+		}
+		if !volumeVisible(txn, req.Group, volume, clientId) {
+			return errors.New("volume not visible to client")
+		}
+		usage.Volume = volume
+		if job, err := GetReplicationJob(txn, req.Group, volume.Key); err == nil {
+			usage.Job = job
+		}
+		rootDir, err := GetDirectory(txn, req.Group, volume.RootDir)
+		if err != nil {
+			return err
+		}
+		rootUsage, err := CountedUsage(txn, req.Group, rootDir)
+		if err != nil {
+			return err
+		}
+		usage.Files = rootUsage.Files
+		usage.Dirs = rootUsage.Dirs
+		usage.Size = rootUsage.Bytes
+		return nil
+	}); err == nil {
+		return usage, nil
+	} else {
+		log.Println("cannot get volume usage:", err)
+		return nil, err
+	}
+}
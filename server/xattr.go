@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	rpb "github.com/PomeloCloud/BFTRaft4go/proto/server"
+	pb "github.com/PomeloCloud/pcfs/proto"
+	"github.com/dgraph-io/badger"
+	"github.com/golang/protobuf/proto"
+	"log"
+	"path"
+)
+
+// Extended attributes are kept in the replicated meta data of files and directories
+// They carry content types, checksums, tags and application meta data, the file system does not read them
+// Setting and removing them needs write permission on the item, they are read with Stat and listing when asked for
+// Sizes are limited for they are in every copy of the meta data, and in snapshots and versions of it
+
+const (
+	XATTR_NAME_LIMIT  = 255
+	XATTR_VALUE_LIMIT = 16 * 1024
+	// names and values of all attributes of an item
+	XATTRS_LIMIT = 64 * 1024
+)
+
+// returns the attributes with the change applied
+func setXattr(xattrs []*pb.Xattr, contract *pb.SetXattrContract) ([]*pb.Xattr, error) {
+	if contract.Name == "" || len(contract.Name) > XATTR_NAME_LIMIT {
+		return nil, errors.New("invalid attribute name")
+	}
+	if len(contract.Value) > XATTR_VALUE_LIMIT {
+		return nil, errors.New(fmt.Sprint("attribute value is larger than ", XATTR_VALUE_LIMIT, " bytes"))
+	}
+	result := []*pb.Xattr{}
+	found := false
+	size := 0
+	for _, xattr := range xattrs {
+		if xattr.Name == contract.Name {
+			found = true
+			if contract.Remove {
+				continue
+			}
+			xattr = &pb.Xattr{Name: contract.Name, Value: contract.Value}
+		}
+		size += len(xattr.Name) + len(xattr.Value)
+		result = append(result, xattr)
+	}
+	if contract.Remove && !found {
+		return nil, errors.New("no attribute " + contract.Name)
+	}
+	if !contract.Remove && !found {
+		size += len(contract.Name) + len(contract.Value)
+		result = append(result, &pb.Xattr{Name: contract.Name, Value: contract.Value})
+	}
+	if size > XATTRS_LIMIT {
+		return nil, errors.New(fmt.Sprint("attributes are larger than ", XATTRS_LIMIT, " bytes"))
+	}
+	return result, nil
+}
+
+func (s *PCFSServer) smSetXattr(arg *[]byte, entry *rpb.LogEntry) []byte {
+	group := entry.Command.Group
+	clientId := entry.Command.ClientId
+	contract := &pb.SetXattrContract{}
+	if err := proto.Unmarshal(*arg, contract); err != nil {
+		log.Println("cannode decode set xattr contract:", err)
+		return []byte{0}
+	}
+	if err := s.BFTRaft.DB.Update(func(txn *badger.Txn) error {
+		now, err := LogClock(txn, group)
+		if err != nil {
+			return err
+		}
+		if contract.Dir {
+			dir, err := GetDirectory(txn, group, contract.Key)
+			if err != nil {
+				return err
+			}
+			if err := CheckDirAccess(txn, group, dir, clientId, PERM_WRITE); err != nil {
+				return err
+			}
+			if dir.Xattrs, err = setXattr(dir.Xattrs, contract); err != nil {
+				return err
+			}
+			dir.ChangedAt = now
+			return SetDirectory(txn, group, dir)
+		}
+		file, err := GetFile(txn, group, contract.Key)
+		if err != nil {
+			return err
+		}
+		if err := CheckFileAccess(txn, group, file, clientId, PERM_WRITE); err != nil {
+			return err
+		}
+		if file.Xattrs, err = setXattr(file.Xattrs, contract); err != nil {
+			return err
+		}
+		file.ChangedAt = now
+		return SetFile(txn, group, file)
+	}); err == nil {
+		log.Println("xattr", contract.Name, "changed")
+		return []byte{1}
+	} else {
+		log.Println("cannot set xattr:", err)
+		return []byte{0}
+	}
+}
+
+func stripItemXattrs(item *pb.DirectoryItem) {
+	if item.File != nil {
+		item.File.Xattrs = nil
+	}
+	if item.Dir != nil {
+		item.Dir.Xattrs = nil
+	}
+}
+
+func stripListXattrs(res *pb.ListDirectoryResponse) {
+	if res.Dir != nil {
+		res.Dir.Xattrs = nil
+	}
+	for _, item := range res.Items {
+		stripItemXattrs(item)
+	}
+}
+
+// the file or directory at the path, the root of a volume is a directory too
+func (s *PCFSServer) Stat(ctx context.Context, req *pb.StatRequest) (*pb.DirectoryItem, error) {
+	clientId, err := s.CallerIdentity(ctx, req.ClientId, req, &req.Signature)
+	if err != nil {
+		log.Println("rejected stat:", err)
+		return nil, err
+	}
+	itemPath := path.Clean("/" + req.Path)
+	dirPath, name := path.Split(itemPath)
+	var item *pb.DirectoryItem
+	if err := s.BFTRaft.DB.View(func(txn *badger.Txn) error {
+		res := &pb.ListDirectoryResponse{}
+		if dirPath == "/" {
+			if err := listPath(txn, req.Group, itemPath, clientId, res); err != nil {
+				return err
+			}
+			item = &pb.DirectoryItem{Type: pb.DirectoryItem_DIR, File: &pb.FileMeta{}, Dir: res.Dir}
+			return nil
+		}
+		if err := listPath(txn, req.Group, dirPath, clientId, res); err != nil {
+			return err
+		}
+		for _, i := range res.Items {
+			if (i.Type == pb.DirectoryItem_FILE && i.File.Name == name) ||
+				(i.Type == pb.DirectoryItem_DIR && i.Dir.Name == name) {
+				item = i
+				return nil
+			}
+		}
+		return errors.New("cannot find " + itemPath)
+	}); err != nil {
+		log.Println("cannot stat:", err)
+		return nil, err
+	}
+	if !req.Xattrs {
+		stripItemXattrs(item)
+	}
+	return item, nil
+}